@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/wotlk/sim/core"
+	"github.com/wowsims/wotlk/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var aplCmd = &cobra.Command{
+	Use:   "apl",
+	Short: "convert APL rotations between json and text",
+	Long:  "convert APL rotations between json (APLRotation in protojson format) and the simc-like text format",
+}
+
+var aplToTextCmd = &cobra.Command{
+	Use:   "totext [file]",
+	Short: "convert an APL rotation from json to text",
+	Long:  "convert an APL rotation from json to text, reading from stdin if no file is given",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := readAPLInput(args)
+		if err != nil {
+			return err
+		}
+
+		rotation := &proto.APLRotation{}
+		if err := protojson.Unmarshal(data, rotation); err != nil {
+			return fmt.Errorf("cannot parse APL json: %w", err)
+		}
+
+		fmt.Print(core.APLRotationToText(rotation))
+		return nil
+	},
+}
+
+var aplFromTextCmd = &cobra.Command{
+	Use:   "fromtext [file]",
+	Short: "convert an APL rotation from text to json",
+	Long:  "convert an APL rotation from text to json, reading from stdin if no file is given",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := readAPLInput(args)
+		if err != nil {
+			return err
+		}
+
+		rotation, err := core.APLRotationFromText(string(data))
+		if err != nil {
+			return fmt.Errorf("cannot parse APL text: %w", err)
+		}
+
+		fmt.Println(protojson.Format(rotation))
+		return nil
+	},
+}
+
+func init() {
+	aplCmd.AddCommand(aplToTextCmd)
+	aplCmd.AddCommand(aplFromTextCmd)
+}
+
+func readAPLInput(args []string) ([]byte, error) {
+	if len(args) == 0 {
+		return io.ReadAll(os.Stdin)
+	}
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %w", args[0], err)
+	}
+	return data, nil
+}
@@ -11,6 +11,12 @@ import (
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
+var (
+	combatLogFormat string
+	combatLogFile   string
+	replaySeed      int64
+)
+
 var simCmd = &cobra.Command{
 	Use:   "sim",
 	Short: "simulate items & settings",
@@ -21,6 +27,9 @@ func init() {
 	simCmd.Flags().StringVar(&infile, "infile", "input.json", "location of input file (RaidSimRequest in protojson format)")
 	simCmd.Flags().StringVar(&infile, "output", "", "location of output file, defaults to stdout")
 	simCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
+	simCmd.Flags().StringVar(&combatLogFormat, "combatlog", "", "records a structured combat log of the first iteration, either 'jsonl' or 'csv'")
+	simCmd.Flags().StringVar(&combatLogFile, "combatlog-output", "", "location of the combat log file, defaults to the combat_log field of the results")
+	simCmd.Flags().Int64Var(&replaySeed, "replay-seed", 0, "replays only the iteration with this seed (e.g. a min_seed or max_seed from earlier results), with full logs")
 	simCmd.MarkFlagRequired("infile")
 }
 
@@ -36,6 +45,20 @@ func simMain(cmd *cobra.Command, args []string) {
 		log.Fatalf("failed to load input json file: %s", err)
 	}
 
+	switch combatLogFormat {
+	case "":
+	case "jsonl":
+		input.SimOptions.CombatLogFormat = proto.CombatLogFormat_CombatLogFormatJsonLines
+	case "csv":
+		input.SimOptions.CombatLogFormat = proto.CombatLogFormat_CombatLogFormatCsv
+	default:
+		log.Fatalf("unknown combat log format %q, expected 'jsonl' or 'csv'", combatLogFormat)
+	}
+
+	if cmd.Flags().Changed("replay-seed") {
+		input.SimOptions.Replay = &proto.ReplayOptions{Seed: replaySeed}
+	}
+
 	var output []byte
 	reporter := make(chan *proto.ProgressMetrics, 10)
 	core.RunRaidSimAsync(input, reporter)
@@ -51,6 +74,17 @@ func simMain(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	if combatLogFile != "" {
+		err = os.WriteFile(combatLogFile, []byte(finalResult.CombatLog), 0666)
+		if err != nil {
+			log.Fatalf("failed to write combat log file: %s", err)
+		}
+		finalResult.CombatLog = ""
+		if verbose {
+			fmt.Printf("Wrote combat log file: `%s` successfully.\n", combatLogFile)
+		}
+	}
+
 	output, err = protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(finalResult)
 	if err != nil {
 		log.Fatalf("failed to marshal final results: %s", err)
@@ -3,6 +3,7 @@ package core
 
 import (
 	"context"
+	"runtime"
 
 	"github.com/wowsims/wotlk/sim/core/proto"
 	"github.com/wowsims/wotlk/sim/core/stats"
@@ -19,6 +20,13 @@ func ComputeStats(csr *proto.ComputeStatsRequest) *proto.ComputeStatsResult {
 	}
 }
 
+/**
+ * Checks the APL rotations of all players for problems, without running the sim.
+ */
+func ValidateAPL(request *proto.ValidateAPLRequest) *proto.ValidateAPLResult {
+	return validateAPLRotations(request)
+}
+
 /**
  * Returns stat weights and EP values, with standard deviations, for all stats.
  */
@@ -43,8 +51,9 @@ func RunRaidSim(request *proto.RaidSimRequest) *proto.RaidSimResult {
 	return RunSim(request, nil)
 }
 
+// Unless the request says otherwise, iterations are split across all CPUs.
 func RunRaidSimAsync(request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics) {
-	go RunSim(request, progress)
+	go runSim(request, progress, false, numSimWorkers(request.SimOptions, runtime.NumCPU()))
 }
 
 func RunBulkSim(request *proto.BulkSimRequest) *proto.BulkSimResult {
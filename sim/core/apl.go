@@ -2,6 +2,7 @@ package core
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/wowsims/wotlk/sim/core/proto"
@@ -11,25 +12,230 @@ import (
 type APLRotation struct {
 	unit         *Unit
 	priorityList []*APLAction
+
+	// Sorted by time.
+	prepullActions []*aplPrepullAction
+
+	// Named lists, referenced by call_action_list and run_action_list.
+	actionLists map[string]*aplActionList
+
+	// User-defined variables, by name.
+	variables map[string]*aplVariable
+
+	// The action being performed from the priority list.
+	choice aplChoice
+
+	// Strict sequence that is currently in progress, if any.
+	strictSequence *APLActionStrictSequence
+
+	// Condition the rotation is currently waiting on, if any.
+	waitUntil APLValue
+
+	// Set by run_action_list when the list it switched to had no available
+	// actions, so the rest of the rotation step is skipped.
+	ranOutOfActions bool
+
+	// Set when no action was available, so a resource gain or auto attack can
+	// wake the rotation up early.
+	idle bool
+
+	// Usage metrics for every list item, in list order.
+	actionMetrics []*APLActionMetrics
+
+	// Whether to record validation failures instead of panicking.
+	collectValidations bool
+	validations        []*proto.APLValidation
+}
+
+// Maximum number of actions that may be performed in a single rotation step,
+// to catch actions which are always available but never use the GCD.
+const aplMaxActionsPerStep = 100
+
+// How often to re-check the condition of a wait_until action.
+const aplWaitUntilPollInterval = time.Millisecond * 50
+
+type aplActionList struct {
+	name    string
+	actions []*APLAction
+}
+
+// The action chosen from a priority list, along with the variable actions
+// passed over on the way to it.
+type aplChoice struct {
+	action          *APLAction
+	variableActions []*APLAction
+}
+
+// Chooses the highest priority available action, and returns whether there was
+// one. Variable actions don't use the GCD or end the search, so the ones
+// reached are kept and only applied once the chosen action is performed.
+// Choosing has no side effects, so checking whether a list has an available
+// action never changes variables.
+func (choice *aplChoice) choose(sim *Simulation, actions []*APLAction) bool {
+	choice.action = nil
+	choice.variableActions = choice.variableActions[:0]
+	for _, action := range actions {
+		if !action.IsAvailable(sim) {
+			continue
+		}
+		if action.isVariableAction() {
+			choice.variableActions = append(choice.variableActions, action)
+			continue
+		}
+		choice.action = action
+		return true
+	}
+	return false
+}
+
+// Applies the variable actions reached before the chosen action, then performs
+// it.
+func (choice *aplChoice) execute(sim *Simulation) {
+	for _, variableAction := range choice.variableActions {
+		variableAction.Execute(sim)
+	}
+	choice.action.Execute(sim)
+}
+
+func (choice *aplChoice) reset() {
+	choice.action = nil
+	choice.variableActions = choice.variableActions[:0]
+}
+
+// Builds the actions for a list, skipping hidden items. listName is used for
+// validation locations.
+func (apl *APLRotation) newAPLActionListItems(listName string, listItems []*proto.APLListItem) []*APLAction {
+	actions := make([]*APLAction, len(listItems))
+	for i, aplItem := range listItems {
+		if aplItem.Hide {
+			continue
+		}
+		location := fmt.Sprintf("%s[%d]", listName, i)
+		apl.tryValidate(location, func() {
+			validateAPLCombatAction(aplItem.Action)
+			actions[i] = apl.unit.newAPLAction(aplItem.Action)
+		})
+		if actions[i] != nil {
+			actions[i].metrics = &APLActionMetrics{
+				Location: location,
+				ActionID: aplActionID(actions[i].impl),
+			}
+			apl.actionMetrics = append(apl.actionMetrics, actions[i].metrics)
+		}
+	}
+
+	if apl.collectValidations {
+		apl.lintActionList(listName, listItems, actions)
+	}
+	return FilterSlice(actions, func(action *APLAction) bool { return action != nil })
 }
 
 func (unit *Unit) newAPLRotation(config *proto.APLRotation) *APLRotation {
+	rotation := unit.buildAPLRotation(config, false)
+	if rotation != nil {
+		unit.Metrics.aplActions = rotation.actionMetrics
+		rotation.registerPrepullActions()
+	}
+	return rotation
+}
+
+// If collectValidations is set, validation failures are recorded in the
+// rotation instead of panicking, and invalid actions are left out.
+func (unit *Unit) buildAPLRotation(config *proto.APLRotation, collectValidations bool) *APLRotation {
 	if config == nil || !config.Enabled {
 		return nil
 	}
 
-	priorityList := MapSlice(config.PriorityList, func(aplItem *proto.APLListItem) *APLAction {
-		if aplItem.Hide {
-			return nil
-		} else {
-			return unit.newAPLAction(aplItem.Action)
+	rotation := &APLRotation{
+		unit:               unit,
+		actionLists:        make(map[string]*aplActionList, len(config.ActionLists)),
+		variables:          make(map[string]*aplVariable, len(config.Variables)),
+		collectValidations: collectValidations,
+	}
+
+	ok := rotation.tryValidate("", func() {
+		if config.Preset != "" {
+			config = unit.aplGetPreset(config.Preset)
 		}
+		validateAPLActionLists(config)
 	})
-	priorityList = FilterSlice(priorityList, func(action *APLAction) bool { return action != nil })
+	if !ok {
+		return rotation
+	}
+
+	for _, varConfig := range config.Variables {
+		varConfig := varConfig
+		rotation.tryValidate("variable."+varConfig.Name, func() {
+			if varConfig.Name == "" {
+				validationError("Variables must have a name")
+			}
+			if rotation.variables[varConfig.Name] != nil {
+				validationError("Duplicate variable name: %s", varConfig.Name)
+			}
+			rotation.variables[varConfig.Name] = &aplVariable{
+				name:         varConfig.Name,
+				initialValue: varConfig.InitialValue,
+				value:        varConfig.InitialValue,
+			}
+		})
+	}
+	for _, listConfig := range config.ActionLists {
+		rotation.actionLists[listConfig.Name] = &aplActionList{name: listConfig.Name}
+	}
+
+	// Set this before building any actions, so that references to named lists
+	// can be resolved during construction.
+	unit.Rotation = rotation
+
+	for _, listConfig := range config.ActionLists {
+		rotation.actionLists[listConfig.Name].actions = rotation.newAPLActionListItems("actions."+listConfig.Name, listConfig.PriorityList)
+	}
+	rotation.priorityList = rotation.newAPLActionListItems("actions", config.PriorityList)
+	rotation.prepullActions = rotation.newAPLPrepullActions(config.PrepullActions)
+
+	return rotation
+}
+
+func (unit *Unit) aplGetPreset(name string) *proto.APLRotation {
+	spec := unit.aplGetCharacter().Spec
+	preset := GetAPLPreset(spec, name)
+	if preset == nil {
+		validationError("No APL preset named %s for %s", name, spec)
+	}
+	return preset
+}
 
-	return &APLRotation{
-		unit:         unit,
-		priorityList: priorityList,
+func (apl *APLRotation) getActionList(name string) *aplActionList {
+	list := apl.actionLists[name]
+	if list == nil {
+		validationError("No action list named %s", name)
+	}
+	return list
+}
+
+func (apl *APLRotation) reset(sim *Simulation) {
+	apl.strictSequence = nil
+	apl.waitUntil = nil
+	apl.ranOutOfActions = false
+	apl.idle = false
+	for _, metrics := range apl.actionMetrics {
+		metrics.enabled = sim.Options.TraceApl
+	}
+	for _, prepullAction := range apl.prepullActions {
+		prepullAction.action.metrics.enabled = true
+		prepullAction.action.Reset(sim)
+	}
+	apl.choice.reset()
+	for _, variable := range apl.variables {
+		variable.value = variable.initialValue
+	}
+	for _, action := range apl.priorityList {
+		action.Reset(sim)
+	}
+	for _, list := range apl.actionLists {
+		for _, action := range list.actions {
+			action.Reset(sim)
+		}
 	}
 }
 
@@ -37,11 +243,31 @@ func (unit *Unit) newAPLRotation(config *proto.APLRotation) *APLRotation {
 // and leverage the community's existing familiarity.
 // https://github.com/simulationcraft/simc/wiki/ActionLists
 func (apl *APLRotation) DoNextAction(sim *Simulation) {
-	for _, action := range apl.priorityList {
-		if action.IsAvailable(sim) {
-			action.Execute(sim)
+	apl.idle = false
+	if apl.waitUntil != nil {
+		if !apl.waitUntil.GetBool(sim) {
+			apl.unit.WaitUntil(sim, sim.CurrentTime+aplWaitUntilPollInterval)
+			return
+		}
+		apl.waitUntil = nil
+	}
+
+	// Keep going until an action uses the GCD or starts a cast, since many
+	// actions (e.g. cooldowns) are off the GCD.
+	for i := 0; i < aplMaxActionsPerStep; i++ {
+		if !apl.doNextActionOnce(sim) {
+			break
+		}
+		if apl.ranOutOfActions {
+			apl.ranOutOfActions = false
+			break
+		}
+		if !apl.unit.GCD.IsReady(sim) || apl.unit.Hardcast.Expires > sim.CurrentTime {
 			return
 		}
+		if i == aplMaxActionsPerStep-1 {
+			panic(fmt.Sprintf("%s performed too many APL actions without using the GCD, at %s", apl.unit.Label, sim.CurrentTime))
+		}
 	}
 
 	if sim.Log != nil {
@@ -49,18 +275,154 @@ func (apl *APLRotation) DoNextAction(sim *Simulation) {
 	}
 	if apl.unit.GCD.IsReady(sim) {
 		apl.unit.WaitUntil(sim, sim.CurrentTime+time.Millisecond*500)
+		apl.idle = true
 	} else {
 		apl.unit.DoNothing()
 	}
 }
 
+// Called whenever the unit gains energy, rage, runes or runic power.
+// These often make a new action available, so an idle rotation is re-evaluated
+// right away rather than at the end of its wait.
+func (apl *APLRotation) onIdleWakeup(sim *Simulation) {
+	if !apl.idle || apl.unit.Hardcast.Expires > sim.CurrentTime {
+		return
+	}
+	apl.idle = false
+	apl.unit.SetGCDTimer(sim, sim.CurrentTime)
+}
+
+// Performs the highest priority available action. Returns false if there were
+// no available actions.
+func (apl *APLRotation) doNextActionOnce(sim *Simulation) bool {
+	if apl.strictSequence != nil {
+		if apl.strictSequence.IsAvailable(sim) {
+			apl.strictSequence.Execute(sim)
+			return true
+		}
+
+		if sim.Log != nil {
+			apl.unit.Log(sim, "Aborting strict sequence, next action is not available.")
+		}
+		apl.strictSequence.Reset(sim)
+		apl.strictSequence = nil
+	}
+
+	if apl.choice.choose(sim, apl.priorityList) {
+		apl.choice.execute(sim)
+		return true
+	}
+	return false
+}
+
+// Checks that all named action lists are uniquely named, that every list
+// reference points to an existing list, and that lists do not reference
+// themselves, directly or indirectly.
+func validateAPLActionLists(config *proto.APLRotation) {
+	listConfigs := make(map[string]*proto.APLActionList, len(config.ActionLists))
+	for _, listConfig := range config.ActionLists {
+		if listConfig.Name == "" {
+			validationError("Action lists must have a name")
+		}
+		if listConfigs[listConfig.Name] != nil {
+			validationError("Duplicate action list name: %s", listConfig.Name)
+		}
+		listConfigs[listConfig.Name] = listConfig
+	}
+
+	listItemRefs := func(listItems []*proto.APLListItem) []string {
+		var refs []string
+		for _, item := range listItems {
+			if !item.Hide {
+				refs = append(refs, aplActionListRefs(item.Action)...)
+			}
+		}
+		return refs
+	}
+
+	for _, name := range listItemRefs(config.PriorityList) {
+		if listConfigs[name] == nil {
+			validationError("No action list named %s", name)
+		}
+	}
+
+	// Depth-first search for cycles, starting from each list.
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	states := make(map[string]int, len(listConfigs))
+	var visit func(name string, path []string)
+	visit = func(name string, path []string) {
+		listConfig := listConfigs[name]
+		if listConfig == nil {
+			validationError("No action list named %s", name)
+		}
+		path = append(path, name)
+		switch states[name] {
+		case visiting:
+			validationError("Recursive action list reference: %s", strings.Join(path, " -> "))
+		case visited:
+			return
+		}
+
+		states[name] = visiting
+		for _, ref := range listItemRefs(listConfig.PriorityList) {
+			visit(ref, path)
+		}
+		states[name] = visited
+	}
+	for _, listConfig := range config.ActionLists {
+		visit(listConfig.Name, nil)
+	}
+}
+
+// Returns the names of all action lists referenced by an action, including
+// those within sequences.
+func aplActionListRefs(config *proto.APLAction) []string {
+	if config == nil {
+		return nil
+	}
+
+	switch action := config.Action.(type) {
+	case *proto.APLAction_CallActionList:
+		return []string{action.CallActionList.Name}
+	case *proto.APLAction_RunActionList:
+		return []string{action.RunActionList.Name}
+	}
+
+	var refs []string
+	for _, subaction := range aplSubactions(config) {
+		refs = append(refs, aplActionListRefs(subaction)...)
+	}
+	return refs
+}
+
+// Returns the actions nested directly within an action, e.g. by a sequence.
+func aplSubactions(config *proto.APLAction) []*proto.APLAction {
+	switch action := config.Action.(type) {
+	case *proto.APLAction_Sequence:
+		return action.Sequence.Actions
+	case *proto.APLAction_StrictSequence:
+		return action.StrictSequence.Actions
+	default:
+		return nil
+	}
+}
+
+const (
+	validationErrorPrefix   = "Validation Error: "
+	validationWarningPrefix = "Validation Warning: "
+)
+
 func validationError(message string, vals ...interface{}) {
-	panic("Validation Error: " + fmt.Sprintf(message, vals...))
+	panic(validationErrorPrefix + fmt.Sprintf(message, vals...))
 }
 
 // For validation issues that we can manage internally. Will probably make this a test-only panic later.
 func validationWarning(message string, vals ...interface{}) {
-	panic("Validation Warning: " + fmt.Sprintf(message, vals...))
+	panic(validationWarningPrefix + fmt.Sprintf(message, vals...))
 }
 
 func APLRotationFromJsonString(jsonString string) *proto.APLRotation {
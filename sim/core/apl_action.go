@@ -7,17 +7,51 @@ import (
 type APLAction struct {
 	condition APLValue
 	impl      APLActionImpl
+
+	// Only set for list items, nil for actions nested in other actions.
+	metrics *APLActionMetrics
 }
 
 func (action *APLAction) IsAvailable(sim *Simulation) bool {
+	if action.metrics != nil && action.metrics.enabled {
+		return action.isAvailableTraced(sim)
+	}
 	return (action.condition == nil || action.condition.GetBool(sim)) && action.impl.IsAvailable(sim)
 }
 
+func (action *APLAction) isAvailableTraced(sim *Simulation) bool {
+	action.metrics.Evaluations++
+	if action.condition != nil && !action.condition.GetBool(sim) {
+		return false
+	}
+	action.metrics.ConditionTrue++
+	return action.impl.IsAvailable(sim)
+}
+
 func (action *APLAction) Execute(sim *Simulation) {
+	if action.metrics != nil && action.metrics.enabled {
+		action.metrics.executed(sim)
+	}
 	action.impl.Execute(sim)
 }
 
+func (action *APLAction) Reset(sim *Simulation) {
+	action.impl.Reset(sim)
+}
+
+func (action *APLAction) isVariableAction() bool {
+	switch action.impl.(type) {
+	case *APLActionSetVariable, *APLActionAddVariable, *APLActionResetVariable:
+		return true
+	default:
+		return false
+	}
+}
+
 type APLActionImpl interface {
+	// Called once at the start of each iteration, to clear any internal state.
+	Reset(*Simulation)
+
 	// Whether this action is available to be used right now.
 	IsAvailable(*Simulation) bool
 
@@ -25,13 +59,24 @@ type APLActionImpl interface {
 	Execute(*Simulation)
 }
 
+// Provides empty implementations for the optional APLActionImpl functions.
+type defaultAPLActionImpl struct {
+}
+
+func (impl defaultAPLActionImpl) Reset(sim *Simulation) {}
+
 func (unit *Unit) newAPLAction(config *proto.APLAction) *APLAction {
 	if config == nil {
 		return nil
 	}
 
+	condition := unit.newAPLValue(config.Condition)
+	if condition != nil && condition.Type() == proto.APLValueType_ValueTypeString {
+		validationError("Conditions cannot be strings")
+	}
+
 	return &APLAction{
-		condition: unit.coerceTo(unit.newAPLValue(config.Condition), proto.APLValueType_ValueTypeBool),
+		condition: unit.coerceTo(condition, proto.APLValueType_ValueTypeBool),
 		impl:      unit.newAPLActionImpl(config),
 	}
 }
@@ -42,12 +87,73 @@ func (unit *Unit) newAPLActionImpl(config *proto.APLAction) APLActionImpl {
 	}
 
 	switch config.Action.(type) {
+	// Sequences
+	case *proto.APLAction_Sequence:
+		return unit.newActionSequence(config.GetSequence())
+	case *proto.APLAction_StrictSequence:
+		return unit.newActionStrictSequence(config.GetStrictSequence())
+
+	// Action lists
+	case *proto.APLAction_CallActionList:
+		return unit.newActionCallActionList(config.GetCallActionList())
+	case *proto.APLAction_RunActionList:
+		return unit.newActionRunActionList(config.GetRunActionList())
+
+	// Variables
+	case *proto.APLAction_SetVariable:
+		return unit.newActionSetVariable(config.GetSetVariable())
+	case *proto.APLAction_AddVariable:
+		return unit.newActionAddVariable(config.GetAddVariable())
+	case *proto.APLAction_ResetVariable:
+		return unit.newActionResetVariable(config.GetResetVariable())
+
+	// Core actions
 	case *proto.APLAction_CastSpell:
 		return unit.newActionCastSpell(config.GetCastSpell())
+	case *proto.APLAction_ChannelSpell:
+		return unit.newActionChannelSpell(config.GetChannelSpell())
+	case *proto.APLAction_ActivateCooldown:
+		return unit.newActionActivateCooldown(config.GetActivateCooldown())
+	case *proto.APLAction_AutocastCooldowns:
+		return unit.newActionAutocastCooldowns(config.GetAutocastCooldowns())
+	case *proto.APLAction_ActivateAura:
+		return unit.newActionActivateAura(config.GetActivateAura())
 	case *proto.APLAction_Wait:
 		return unit.newActionWait(config.GetWait())
+	case *proto.APLAction_WaitUntil:
+		return unit.newActionWaitUntil(config.GetWaitUntil())
+	case *proto.APLAction_ChangeTarget:
+		return unit.newActionChangeTarget(config.GetChangeTarget())
+	case *proto.APLAction_ItemSwap:
+		return unit.newActionItemSwap(config.GetItemSwap())
 	default:
 		validationError("Unimplemented action type")
 		return nil
 	}
 }
+
+// Returns the spell cast by an action, or nil if it doesn't cast exactly one.
+func aplActionSpell(impl APLActionImpl) *Spell {
+	switch action := impl.(type) {
+	case *APLActionCastSpell:
+		return action.spell
+	case *APLActionChannelSpell:
+		return action.spell
+	case *APLActionActivateCooldown:
+		return action.character.GetInitialMajorCooldown(action.actionID).Spell
+	default:
+		return nil
+	}
+}
+
+// Returns the spell, item or aura used by an action, or an empty ActionID if
+// it doesn't use exactly one.
+func aplActionID(impl APLActionImpl) ActionID {
+	if action, ok := impl.(*APLActionActivateAura); ok {
+		return action.aura.ActionID
+	}
+	if spell := aplActionSpell(impl); spell != nil {
+		return spell.ActionID
+	}
+	return ActionID{}
+}
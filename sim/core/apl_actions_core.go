@@ -6,28 +6,261 @@ import (
 	"github.com/wowsims/wotlk/sim/core/proto"
 )
 
+// Returns the Character which owns this unit's rotation.
+func (unit *Unit) aplGetCharacter() *Character {
+	agent := unit.Env.Raid.GetPlayerFromUnitIndex(unit.UnitIndex)
+	if agent == nil {
+		validationError("%s cannot use character-only APL actions", unit.Label)
+		return nil
+	}
+	return agent.GetCharacter()
+}
+
+// Chooses the target for an action.
+type aplTargetSelector struct {
+	unit        *Unit
+	config      *proto.APLTargetSelector
+	targetIndex int32
+	dotSpell    *Spell
+}
+
+// dotSpell is the spell to check when selecting targets which are missing a
+// dot, if the selector doesn't specify one itself.
+func (unit *Unit) newAPLTargetSelector(config *proto.APLTargetSelector, dotSpell *Spell) *aplTargetSelector {
+	if config == nil {
+		config = &proto.APLTargetSelector{}
+	}
+	selector := &aplTargetSelector{
+		unit:   unit,
+		config: config,
+	}
+
+	switch config.Type {
+	case proto.APLTargetSelector_SelectTargetIndex:
+		if config.TargetIndex < 0 || int(config.TargetIndex) >= len(unit.Env.Encounter.Targets) {
+			validationError("Invalid target index %d, encounter has %d targets", config.TargetIndex, len(unit.Env.Encounter.Targets))
+		}
+		selector.targetIndex = config.TargetIndex
+	case proto.APLTargetSelector_SelectMissingDot:
+		if config.DotSpellId != nil {
+			dotSpell = unit.aplGetSpell(config.DotSpellId)
+		}
+		if dotSpell == nil || (dotSpell.AOEDot() == nil && len(dotSpell.dots) == 0) {
+			validationError("Missing dot target selection requires a spell with a dot")
+		}
+		selector.dotSpell = dotSpell
+	}
+	return selector
+}
+
+// Returns the selected target, or nil if no target matches.
+func (selector *aplTargetSelector) Get(sim *Simulation) *Unit {
+	switch selector.config.Type {
+	case proto.APLTargetSelector_SelectTargetIndex:
+		// Encounter indices also cover adds, which can't be picked before they
+		// spawn or after they despawn.
+		target := sim.Encounter.Targets[selector.targetIndex]
+		if !target.IsEnabled() {
+			return nil
+		}
+		return &target.Unit
+	case proto.APLTargetSelector_SelectLowestHealth:
+		var lowest *Target
+		for _, target := range sim.Encounter.ActiveTargets {
+			if lowest == nil || target.RemainingHealth() < lowest.RemainingHealth() {
+				lowest = target
+			}
+		}
+		return &lowest.Unit
+	case proto.APLTargetSelector_SelectMissingDot:
+		for _, target := range sim.Encounter.TargetUnits {
+			dot := selector.dotSpell.AOEDot()
+			if dot == nil {
+				dot = selector.dotSpell.Dot(target)
+			}
+			if dot == nil || !dot.IsActive() {
+				return target
+			}
+		}
+		return nil
+	default:
+		return selector.unit.CurrentTarget
+	}
+}
+
 type APLActionCastSpell struct {
-	spell *Spell
+	defaultAPLActionImpl
+	spell  *Spell
+	target *aplTargetSelector
 }
 
 func (unit *Unit) newActionCastSpell(config *proto.APLActionCastSpell) APLActionImpl {
-	spell := unit.GetSpell(ProtoToActionID(config.SpellId))
+	spell := unit.aplGetSpell(config.SpellId)
 	if spell == nil {
-		validationWarning("No spell found for id: %s", ProtoToActionID(config.SpellId).String())
 		return nil
 	}
 	return &APLActionCastSpell{
-		spell: spell,
+		spell:  spell,
+		target: unit.newAPLTargetSelector(config.Target, spell),
 	}
 }
 func (action *APLActionCastSpell) IsAvailable(sim *Simulation) bool {
-	return action.spell.CanCast(sim, action.spell.Unit.CurrentTarget)
+	target := action.target.Get(sim)
+	return target != nil && action.spell.CanCast(sim, target)
 }
 func (action *APLActionCastSpell) Execute(sim *Simulation) {
-	action.spell.Cast(sim, action.spell.Unit.CurrentTarget)
+	action.spell.Cast(sim, action.target.Get(sim))
+}
+
+type APLActionChannelSpell struct {
+	defaultAPLActionImpl
+	spell       *Spell
+	target      *aplTargetSelector
+	interruptIf APLValue
+}
+
+func (unit *Unit) newActionChannelSpell(config *proto.APLActionChannelSpell) APLActionImpl {
+	spell := unit.aplGetSpell(config.SpellId)
+	if spell == nil {
+		return nil
+	}
+	if spell.DefaultCast.ChannelTime == 0 {
+		validationError("%s is not a channeled spell", spell.ActionID)
+	}
+	return &APLActionChannelSpell{
+		spell:       spell,
+		target:      unit.newAPLTargetSelector(config.Target, spell),
+		interruptIf: unit.coerceTo(unit.newAPLValue(config.InterruptIf), proto.APLValueType_ValueTypeBool),
+	}
+}
+func (action *APLActionChannelSpell) IsAvailable(sim *Simulation) bool {
+	target := action.target.Get(sim)
+	return target != nil && action.spell.CanCast(sim, target)
+}
+func (action *APLActionChannelSpell) Execute(sim *Simulation) {
+	target := action.target.Get(sim)
+	action.spell.Cast(sim, target)
+
+	if action.interruptIf == nil {
+		return
+	}
+
+	dot := action.spell.AOEDot()
+	if dot == nil {
+		dot = action.spell.Dot(target)
+	}
+	if dot == nil || dot.NumberOfTicks <= 1 {
+		return
+	}
+
+	unit := action.spell.Unit
+	gcdReadyAt := sim.CurrentTime + action.spell.CurCast.GCD
+	var pa *PendingAction
+	pa = StartPeriodicAction(sim, PeriodicActionOptions{
+		Period:   dot.TickPeriod(),
+		NumTicks: int(dot.NumberOfTicks) - 1,
+		// Run after the dot tick at the same timestamp.
+		Priority: ActionPriorityLow,
+		OnAction: func(sim *Simulation) {
+			if !dot.IsActive() || unit.Hardcast.ActionID != action.spell.ActionID {
+				pa.Cancel(sim)
+				return
+			}
+			if !action.interruptIf.GetBool(sim) {
+				return
+			}
+
+			if sim.Log != nil {
+				unit.Log(sim, "Interrupting channel of %s", action.spell.ActionID)
+			}
+			pa.Cancel(sim)
+			dot.Cancel(sim)
+			unit.Hardcast.Expires = sim.CurrentTime
+			unit.SetGCDTimer(sim, MaxDuration(sim.CurrentTime, gcdReadyAt))
+		},
+	})
+}
+
+type APLActionActivateCooldown struct {
+	defaultAPLActionImpl
+	character *Character
+	actionID  ActionID
+}
+
+func (unit *Unit) newActionActivateCooldown(config *proto.APLActionActivateCooldown) APLActionImpl {
+	character := unit.aplGetCharacter()
+	actionID := ProtoToActionID(config.CooldownId)
+	if character.GetInitialMajorCooldown(actionID).Spell == nil {
+		validationWarning("No major cooldown found for id: %s", actionID)
+		return nil
+	}
+	return &APLActionActivateCooldown{
+		character: character,
+		actionID:  actionID,
+	}
+}
+func (action *APLActionActivateCooldown) IsAvailable(sim *Simulation) bool {
+	mcd := action.character.GetMajorCooldown(action.actionID)
+	return mcd != nil && mcd.Spell.CanCast(sim, action.character.CurrentTarget)
+}
+func (action *APLActionActivateCooldown) Execute(sim *Simulation) {
+	action.character.GetMajorCooldown(action.actionID).activate(sim, action.character)
+	action.character.UpdateMajorCooldowns()
+}
+
+type APLActionActivateAura struct {
+	defaultAPLActionImpl
+	aura *Aura
+}
+
+func (unit *Unit) newActionActivateAura(config *proto.APLActionActivateAura) APLActionImpl {
+	return &APLActionActivateAura{
+		aura: unit.aplGetAura(config.AuraId, false).aura,
+	}
+}
+func (action *APLActionActivateAura) IsAvailable(sim *Simulation) bool {
+	return true
+}
+func (action *APLActionActivateAura) Execute(sim *Simulation) {
+	action.aura.Activate(sim)
+}
+
+type APLActionAutocastCooldowns struct {
+	defaultAPLActionImpl
+	character *Character
+	allTypes  bool
+
+	// MCD chosen by the last call to IsAvailable.
+	nextMCD *MajorCooldown
+}
+
+func (unit *Unit) newActionAutocastCooldowns(config *proto.APLActionAutocastCooldowns) APLActionImpl {
+	return &APLActionAutocastCooldowns{
+		character: unit.aplGetCharacter(),
+		allTypes:  config.AllTypes,
+	}
+}
+func (action *APLActionAutocastCooldowns) Reset(sim *Simulation) {
+	action.nextMCD = nil
+}
+func (action *APLActionAutocastCooldowns) IsAvailable(sim *Simulation) bool {
+	action.nextMCD = nil
+	for _, mcd := range action.character.GetMajorCooldowns() {
+		if mcd.IsEnabled() && (action.allTypes || mcd.Type.Matches(CooldownTypeDPS)) && mcd.IsReady(sim) && mcd.shouldActivateHelper(sim, action.character) {
+			action.nextMCD = mcd
+			return true
+		}
+	}
+	return false
+}
+func (action *APLActionAutocastCooldowns) Execute(sim *Simulation) {
+	action.nextMCD.activate(sim, action.character)
+	action.nextMCD = nil
+	action.character.UpdateMajorCooldowns()
 }
 
 type APLActionWait struct {
+	defaultAPLActionImpl
 	unit     *Unit
 	duration time.Duration
 }
@@ -44,3 +277,83 @@ func (action *APLActionWait) IsAvailable(sim *Simulation) bool {
 func (action *APLActionWait) Execute(sim *Simulation) {
 	action.unit.WaitUntil(sim, sim.CurrentTime+action.duration)
 }
+
+type APLActionWaitUntil struct {
+	defaultAPLActionImpl
+	unit      *Unit
+	condition APLValue
+}
+
+func (unit *Unit) newActionWaitUntil(config *proto.APLActionWaitUntil) APLActionImpl {
+	condition := unit.coerceTo(unit.newAPLValue(config.Condition), proto.APLValueType_ValueTypeBool)
+	if condition == nil {
+		validationError("Wait until action requires a condition")
+	}
+	return &APLActionWaitUntil{
+		unit:      unit,
+		condition: condition,
+	}
+}
+func (action *APLActionWaitUntil) IsAvailable(sim *Simulation) bool {
+	return !action.condition.GetBool(sim)
+}
+func (action *APLActionWaitUntil) Execute(sim *Simulation) {
+	action.unit.Rotation.waitUntil = action.condition
+	action.unit.WaitUntil(sim, sim.CurrentTime+aplWaitUntilPollInterval)
+}
+
+type APLActionChangeTarget struct {
+	defaultAPLActionImpl
+	unit   *Unit
+	target *aplTargetSelector
+}
+
+func (unit *Unit) newActionChangeTarget(config *proto.APLActionChangeTarget) APLActionImpl {
+	return &APLActionChangeTarget{
+		unit:   unit,
+		target: unit.newAPLTargetSelector(config.Target, nil),
+	}
+}
+func (action *APLActionChangeTarget) IsAvailable(sim *Simulation) bool {
+	target := action.target.Get(sim)
+	return target != nil && target != action.unit.CurrentTarget
+}
+func (action *APLActionChangeTarget) Execute(sim *Simulation) {
+	target := action.target.Get(sim)
+	if sim.Log != nil {
+		action.unit.Log(sim, "Changing target to %s", target.Label)
+	}
+	action.unit.CurrentTarget = target
+}
+
+type APLActionItemSwap struct {
+	defaultAPLActionImpl
+	character *Character
+	swapSet   proto.APLActionItemSwap_SwapSet
+}
+
+func (unit *Unit) newActionItemSwap(config *proto.APLActionItemSwap) APLActionImpl {
+	if config.SwapSet == proto.APLActionItemSwap_Unknown {
+		validationError("Item swap action requires a swap set")
+	}
+	character := unit.aplGetCharacter()
+	if !character.ItemSwap.IsEnabled() {
+		validationWarning("%s does not have item swap enabled", unit.Label)
+		return nil
+	}
+	return &APLActionItemSwap{
+		character: character,
+		swapSet:   config.SwapSet,
+	}
+}
+func (action *APLActionItemSwap) IsAvailable(sim *Simulation) bool {
+	wantSwapped := action.swapSet == proto.APLActionItemSwap_Swap1
+	return action.character.GCD.IsReady(sim) && action.character.ItemSwap.IsSwapped() != wantSwapped
+}
+func (action *APLActionItemSwap) Execute(sim *Simulation) {
+	if sim.Log != nil {
+		action.character.Log(sim, "Item swap to set %s", action.swapSet)
+	}
+	slots := []proto.ItemSlot{proto.ItemSlot_ItemSlotMainHand, proto.ItemSlot_ItemSlotOffHand, proto.ItemSlot_ItemSlotRanged}
+	action.character.ItemSwap.SwapItems(sim, slots, true)
+}
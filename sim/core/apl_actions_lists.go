@@ -0,0 +1,55 @@
+package core
+
+import (
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+type APLActionCallActionList struct {
+	defaultAPLActionImpl
+	list *aplActionList
+
+	// Action chosen by the last call to IsAvailable.
+	choice aplChoice
+}
+
+func (unit *Unit) newActionCallActionList(config *proto.APLActionCallActionList) APLActionImpl {
+	return &APLActionCallActionList{
+		list: unit.Rotation.getActionList(config.Name),
+	}
+}
+func (action *APLActionCallActionList) Reset(sim *Simulation) {
+	action.choice.reset()
+}
+func (action *APLActionCallActionList) IsAvailable(sim *Simulation) bool {
+	return action.choice.choose(sim, action.list.actions)
+}
+func (action *APLActionCallActionList) Execute(sim *Simulation) {
+	action.choice.execute(sim)
+	action.choice.reset()
+}
+
+type APLActionRunActionList struct {
+	defaultAPLActionImpl
+	unit   *Unit
+	list   *aplActionList
+	choice aplChoice
+}
+
+func (unit *Unit) newActionRunActionList(config *proto.APLActionRunActionList) APLActionImpl {
+	return &APLActionRunActionList{
+		unit: unit,
+		list: unit.Rotation.getActionList(config.Name),
+	}
+}
+
+// Always available, so that lower priority actions are never reached.
+func (action *APLActionRunActionList) IsAvailable(sim *Simulation) bool {
+	return true
+}
+func (action *APLActionRunActionList) Execute(sim *Simulation) {
+	if action.choice.choose(sim, action.list.actions) {
+		action.choice.execute(sim)
+	} else {
+		action.unit.Rotation.ranOutOfActions = true
+	}
+}
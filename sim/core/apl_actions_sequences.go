@@ -0,0 +1,75 @@
+package core
+
+import (
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+type APLActionSequence struct {
+	defaultAPLActionImpl
+	subactions []*APLAction
+	curIdx     int
+}
+
+func (unit *Unit) newActionSequence(config *proto.APLActionSequence) APLActionImpl {
+	subactions := MapSlice(config.Actions, func(action *proto.APLAction) *APLAction {
+		return unit.newAPLAction(action)
+	})
+	subactions = FilterSlice(subactions, func(action *APLAction) bool { return action != nil })
+	return &APLActionSequence{
+		subactions: subactions,
+	}
+}
+func (action *APLActionSequence) Reset(sim *Simulation) {
+	action.curIdx = 0
+	for _, subaction := range action.subactions {
+		subaction.Reset(sim)
+	}
+}
+func (action *APLActionSequence) IsAvailable(sim *Simulation) bool {
+	return action.curIdx < len(action.subactions) && action.subactions[action.curIdx].IsAvailable(sim)
+}
+func (action *APLActionSequence) Execute(sim *Simulation) {
+	action.subactions[action.curIdx].Execute(sim)
+	action.curIdx++
+}
+
+type APLActionStrictSequence struct {
+	defaultAPLActionImpl
+	unit       *Unit
+	subactions []*APLAction
+	curIdx     int
+}
+
+func (unit *Unit) newActionStrictSequence(config *proto.APLActionStrictSequence) APLActionImpl {
+	subactions := MapSlice(config.Actions, func(action *proto.APLAction) *APLAction {
+		return unit.newAPLAction(action)
+	})
+	subactions = FilterSlice(subactions, func(action *APLAction) bool { return action != nil })
+	if len(subactions) == 0 {
+		validationError("Strict sequence must have at least one action")
+	}
+	return &APLActionStrictSequence{
+		unit:       unit,
+		subactions: subactions,
+	}
+}
+func (action *APLActionStrictSequence) Reset(sim *Simulation) {
+	action.curIdx = 0
+	for _, subaction := range action.subactions {
+		subaction.Reset(sim)
+	}
+}
+func (action *APLActionStrictSequence) IsAvailable(sim *Simulation) bool {
+	return action.subactions[action.curIdx].IsAvailable(sim)
+}
+func (action *APLActionStrictSequence) Execute(sim *Simulation) {
+	action.subactions[action.curIdx].Execute(sim)
+	action.curIdx++
+
+	if action.curIdx == len(action.subactions) {
+		action.Reset(sim)
+		action.unit.Rotation.strictSequence = nil
+	} else {
+		action.unit.Rotation.strictSequence = action
+	}
+}
@@ -0,0 +1,282 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// Action which counts how many times it was executed.
+type testAPLAction struct {
+	defaultAPLActionImpl
+	available   bool
+	numExecuted int
+}
+
+func (action *testAPLAction) IsAvailable(sim *Simulation) bool {
+	return action.available
+}
+func (action *testAPLAction) Execute(sim *Simulation) {
+	action.numExecuted++
+}
+
+func TestActionSequence(t *testing.T) {
+	sim := &Simulation{}
+	a1 := &testAPLAction{available: true}
+	a2 := &testAPLAction{available: true}
+	seq := &APLActionSequence{
+		subactions: []*APLAction{{impl: a1}, {impl: a2}},
+	}
+
+	for seq.IsAvailable(sim) {
+		seq.Execute(sim)
+	}
+	if a1.numExecuted != 1 || a2.numExecuted != 1 {
+		t.Fatalf("Expected each sub-action to execute once, got %d and %d", a1.numExecuted, a2.numExecuted)
+	}
+
+	seq.Reset(sim)
+	if !seq.IsAvailable(sim) {
+		t.Fatalf("Expected sequence to be available after reset")
+	}
+}
+
+func TestActionStrictSequence(t *testing.T) {
+	sim := &Simulation{}
+	unit := &Unit{}
+	unit.Rotation = &APLRotation{unit: unit}
+
+	a1 := &testAPLAction{available: true}
+	a2 := &testAPLAction{available: true}
+	seq := &APLActionStrictSequence{
+		unit:       unit,
+		subactions: []*APLAction{{impl: a1}, {impl: a2}},
+	}
+
+	seq.Execute(sim)
+	if unit.Rotation.strictSequence != seq {
+		t.Fatalf("Expected rotation to be locked into the strict sequence")
+	}
+
+	seq.Execute(sim)
+	if unit.Rotation.strictSequence != nil {
+		t.Fatalf("Expected rotation to be released after the strict sequence finished")
+	}
+	if seq.curIdx != 0 {
+		t.Fatalf("Expected strict sequence to reset after finishing, got index %d", seq.curIdx)
+	}
+	if a1.numExecuted != 1 || a2.numExecuted != 1 {
+		t.Fatalf("Expected each sub-action to execute once, got %d and %d", a1.numExecuted, a2.numExecuted)
+	}
+}
+
+func TestActionCallAndRunActionList(t *testing.T) {
+	sim := &Simulation{}
+	unit := &Unit{}
+	unit.Rotation = &APLRotation{unit: unit}
+
+	listAction := &testAPLAction{available: false}
+	list := &aplActionList{name: "list", actions: []*APLAction{{impl: listAction}}}
+
+	call := &APLActionCallActionList{list: list}
+	if call.IsAvailable(sim) {
+		t.Fatalf("Expected call_action_list to fall through when its list has no available actions")
+	}
+
+	run := &APLActionRunActionList{unit: unit, list: list}
+	if !run.IsAvailable(sim) {
+		t.Fatalf("Expected run_action_list to always be available")
+	}
+	run.Execute(sim)
+	if !unit.Rotation.ranOutOfActions {
+		t.Fatalf("Expected run_action_list to stop the rotation step")
+	}
+
+	listAction.available = true
+	if !call.IsAvailable(sim) {
+		t.Fatalf("Expected call_action_list to be available")
+	}
+	call.Execute(sim)
+	if listAction.numExecuted != 1 {
+		t.Fatalf("Expected list action to execute once, got %d", listAction.numExecuted)
+	}
+}
+
+func TestValidateActionLists(t *testing.T) {
+	callList := func(name string) *proto.APLListItem {
+		return &proto.APLListItem{Action: &proto.APLAction{Action: &proto.APLAction_CallActionList{
+			CallActionList: &proto.APLActionCallActionList{Name: name},
+		}}}
+	}
+	expectPanic := func(config *proto.APLRotation, reason string) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatalf("Expected validation error for %s", reason)
+			}
+		}()
+		validateAPLActionLists(config)
+	}
+
+	validateAPLActionLists(&proto.APLRotation{
+		PriorityList: []*proto.APLListItem{callList("a")},
+		ActionLists: []*proto.APLActionList{
+			{Name: "a", PriorityList: []*proto.APLListItem{callList("b")}},
+			{Name: "b"},
+		},
+	})
+
+	expectPanic(&proto.APLRotation{
+		PriorityList: []*proto.APLListItem{callList("missing")},
+	}, "unknown list")
+
+	expectPanic(&proto.APLRotation{
+		ActionLists: []*proto.APLActionList{
+			{Name: "a", PriorityList: []*proto.APLListItem{callList("b")}},
+			{Name: "b", PriorityList: []*proto.APLListItem{callList("a")}},
+		},
+	}, "recursive lists")
+}
+
+func TestValidatePrepullTiming(t *testing.T) {
+	castSpell := func(gcd time.Duration, castTime time.Duration) *APLAction {
+		return &APLAction{impl: &APLActionCastSpell{spell: &Spell{DefaultCast: Cast{GCD: gcd, CastTime: castTime}}}}
+	}
+
+	apl := &APLRotation{collectValidations: true}
+	apl.validatePrepullTiming([]*aplPrepullAction{
+		{location: "prepull[0]", doAt: -time.Second * 3, action: castSpell(time.Millisecond*1500, time.Millisecond*2500)},
+		{location: "prepull[1]", doAt: -time.Second, action: castSpell(0, 0)},
+		{location: "prepull[2]", doAt: -time.Millisecond * 500, action: castSpell(time.Millisecond*1500, 0)},
+		{location: "prepull[3]", doAt: -time.Millisecond * 100, action: castSpell(time.Millisecond*1500, 0)},
+		{location: "prepull[4]", doAt: 0, action: castSpell(0, 0)},
+	})
+
+	if len(apl.validations) != 2 || apl.validations[0].Location != "prepull[1]" || apl.validations[1].Location != "prepull[3]" {
+		t.Fatalf("Expected overlaps for prepull[1] and prepull[3], got %v", apl.validations)
+	}
+}
+
+func TestValidateCombatAction(t *testing.T) {
+	activateAura := &proto.APLAction{Action: &proto.APLAction_ActivateAura{
+		ActivateAura: &proto.APLActionActivateAura{},
+	}}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("Expected validation error for activate_aura within a sequence")
+		}
+	}()
+	validateAPLCombatAction(&proto.APLAction{Action: &proto.APLAction_Sequence{
+		Sequence: &proto.APLActionSequence{Actions: []*proto.APLAction{activateAura}},
+	}})
+}
+
+func TestVariables(t *testing.T) {
+	sim := &Simulation{}
+	unit := &Unit{}
+	unit.Rotation = &APLRotation{
+		unit: unit,
+		variables: map[string]*aplVariable{
+			"casts": {name: "casts", initialValue: 1, value: 1},
+		},
+	}
+
+	add := unit.newActionAddVariable(&proto.APLActionAddVariable{
+		Name:  "casts",
+		Value: &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: "2"}}},
+	})
+	value := unit.newValueVariable(&proto.APLValueVariable{Name: "casts"})
+
+	if !add.IsAvailable(sim) {
+		t.Fatalf("Expected variable actions to be available")
+	}
+	if value.GetFloat(sim) != 1 {
+		t.Fatalf("Expected IsAvailable to leave the variable unchanged, got %f", value.GetFloat(sim))
+	}
+	add.Execute(sim)
+	if value.GetFloat(sim) != 3 {
+		t.Fatalf("Unexpected variable value %f", value.GetFloat(sim))
+	}
+
+	// Variable actions whose condition passes are passed over, and only
+	// applied once the action chosen after them is performed.
+	skipped := &APLAction{
+		condition: &APLValueConst{valType: proto.APLValueType_ValueTypeBool, boolVal: false},
+		impl:      add,
+	}
+	applied := &APLAction{impl: add}
+	next := &APLAction{impl: &testAPLAction{available: true}}
+	choice := &aplChoice{}
+	for i := 0; i < 2; i++ {
+		if !choice.choose(sim, []*APLAction{skipped, applied, next}) || choice.action != next {
+			t.Fatalf("Expected variable actions to be skipped over")
+		}
+	}
+	if value.GetFloat(sim) != 3 {
+		t.Fatalf("Expected choosing an action to leave the variable unchanged, got %f", value.GetFloat(sim))
+	}
+	choice.execute(sim)
+	if value.GetFloat(sim) != 5 {
+		t.Fatalf("Unexpected variable value %f", value.GetFloat(sim))
+	}
+
+	// Checking whether a called list is available doesn't change variables
+	// either, however often it is checked.
+	callList := &APLActionCallActionList{list: &aplActionList{actions: []*APLAction{applied, next}}}
+	if !callList.IsAvailable(sim) || !callList.IsAvailable(sim) || value.GetFloat(sim) != 5 {
+		t.Fatalf("Expected the called list to be available without changing the variable, got %f", value.GetFloat(sim))
+	}
+	callList.Execute(sim)
+	if value.GetFloat(sim) != 7 {
+		t.Fatalf("Expected the called list to add to the variable once, got %f", value.GetFloat(sim))
+	}
+
+	// Nothing is applied if no action is chosen.
+	unavailable := &APLAction{impl: &testAPLAction{available: false}}
+	if choice.choose(sim, []*APLAction{applied, unavailable}) || value.GetFloat(sim) != 7 {
+		t.Fatalf("Expected no action to be chosen, with the variable unchanged at %f", value.GetFloat(sim))
+	}
+
+	unit.Rotation.reset(sim)
+	if value.GetFloat(sim) != 1 {
+		t.Fatalf("Expected variable to reset to its initial value, got %f", value.GetFloat(sim))
+	}
+}
+
+func TestActionMetrics(t *testing.T) {
+	sim := &Simulation{}
+	unit := &Unit{}
+	unit.Rotation = &APLRotation{unit: unit}
+
+	condition := &APLValueConst{valType: proto.APLValueType_ValueTypeBool, boolVal: false}
+	impl := &testAPLAction{available: true}
+	metrics := &APLActionMetrics{Location: "actions[0]", enabled: true}
+	action := &APLAction{condition: condition, impl: impl, metrics: metrics}
+
+	action.IsAvailable(sim)
+	condition.boolVal = true
+	sim.CurrentTime = time.Second
+	if action.IsAvailable(sim) {
+		action.Execute(sim)
+	}
+	sim.CurrentTime = time.Second * 3
+	if action.IsAvailable(sim) {
+		action.Execute(sim)
+	}
+	metrics.doneIteration()
+
+	// A second iteration in which the action is never performed.
+	metrics.reset()
+	condition.boolVal = false
+	action.IsAvailable(sim)
+	metrics.doneIteration()
+
+	result := metrics.ToProto()
+	if result.EvaluationsAvg != 2 || result.ConditionTrueAvg != 1 || result.ExecutionsAvg != 1 || result.UsedIterationsRatio != 0.5 {
+		t.Fatalf("Unexpected counts: %v", result)
+	}
+	if result.FirstUsedSecondsAvg != 1 || result.LastUsedSecondsAvg != 3 {
+		t.Fatalf("Unexpected usage times: %v", result)
+	}
+}
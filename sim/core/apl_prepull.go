@@ -0,0 +1,136 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+	"golang.org/x/exp/slices"
+)
+
+type aplPrepullAction struct {
+	location string
+	doAt     time.Duration
+	action   *APLAction
+}
+
+// Builds the prepull actions, sorted by time. Their metrics are always
+// recorded, so the prepull shows up in the results.
+func (apl *APLRotation) newAPLPrepullActions(configs []*proto.APLPrepullAction) []*aplPrepullAction {
+	var prepullActions []*aplPrepullAction
+	for i, config := range configs {
+		config := config
+		location := fmt.Sprintf("prepull[%d]", i)
+		apl.tryValidate(location, func() {
+			doAt := DurationFromProto(config.DoAt)
+			if doAt > 0 {
+				validationError("Prepull actions must happen before the pull, not at %s", doAt)
+			}
+			validateAPLPrepullAction(config.Action)
+
+			action := apl.unit.newAPLAction(config.Action)
+			if action == nil || action.impl == nil {
+				return
+			}
+			action.metrics = &APLActionMetrics{
+				Location: location,
+				ActionID: aplActionID(action.impl),
+			}
+			apl.actionMetrics = append(apl.actionMetrics, action.metrics)
+			prepullActions = append(prepullActions, &aplPrepullAction{
+				location: location,
+				doAt:     doAt,
+				action:   action,
+			})
+		})
+	}
+
+	slices.SortStableFunc(prepullActions, func(a1, a2 *aplPrepullAction) bool {
+		return a1.doAt < a2.doAt
+	})
+	apl.validatePrepullTiming(prepullActions)
+	return prepullActions
+}
+
+// Rejects actions which only make sense while the rotation is running.
+func validateAPLPrepullAction(config *proto.APLAction) {
+	switch config.GetAction().(type) {
+	case *proto.APLAction_Sequence, *proto.APLAction_StrictSequence,
+		*proto.APLAction_Wait, *proto.APLAction_WaitUntil,
+		*proto.APLAction_CallActionList, *proto.APLAction_RunActionList:
+		validationError("This action cannot be used as a prepull action")
+	}
+}
+
+// Rejects prepull-only actions anywhere within a list item.
+func validateAPLCombatAction(config *proto.APLAction) {
+	if config == nil {
+		return
+	}
+	if _, ok := config.Action.(*proto.APLAction_ActivateAura); ok {
+		validationError("activate_aura can only be used as a prepull action")
+	}
+	for _, subaction := range aplSubactions(config) {
+		validateAPLCombatAction(subaction)
+	}
+}
+
+// Checks that no prepull action starts while an earlier one is still casting,
+// or needs the GCD while it is still running. Cast times are checked without
+// haste, which can only make them shorter.
+func (apl *APLRotation) validatePrepullTiming(prepullActions []*aplPrepullAction) {
+	var lastCast, lastGCD *aplPrepullAction
+	var lastCastSpell, lastGCDSpell *Spell
+	var castEndsAt, gcdReadyAt time.Duration
+
+	for _, prepullAction := range prepullActions {
+		spell := aplActionSpell(prepullAction.action.impl)
+		if spell == nil {
+			continue
+		}
+
+		apl.tryValidate(prepullAction.location, func() {
+			if lastCast != nil && prepullAction.doAt < castEndsAt {
+				validationError("Prepull action at %s overlaps the cast of %s from %s, which ends at %s", prepullAction.doAt, lastCastSpell.ActionID, lastCast.location, castEndsAt)
+			}
+			if spell.DefaultCast.GCD > 0 && lastGCD != nil && prepullAction.doAt < gcdReadyAt {
+				validationError("Prepull action at %s overlaps the GCD of %s from %s, which ends at %s", prepullAction.doAt, lastGCDSpell.ActionID, lastGCD.location, gcdReadyAt)
+			}
+		})
+
+		castTime := spell.DefaultCast.CastTime + spell.DefaultCast.ChannelTime
+		if castTime > 0 {
+			lastCast, lastCastSpell = prepullAction, spell
+			castEndsAt = prepullAction.doAt + castTime
+		}
+		if spell.DefaultCast.GCD > 0 {
+			lastGCD, lastGCDSpell = prepullAction, spell
+			gcdReadyAt = prepullAction.doAt + MaxDuration(spell.DefaultCast.GCD, castTime)
+		}
+	}
+}
+
+// Registers the prepull actions with the environment, so they are performed
+// at their times during each iteration's prepull phase.
+func (apl *APLRotation) registerPrepullActions() {
+	for _, prepullAction := range apl.prepullActions {
+		prepullAction := prepullAction
+		apl.unit.RegisterPrepullAction(prepullAction.doAt, func(sim *Simulation) {
+			apl.doPrepullAction(sim, prepullAction)
+		})
+	}
+}
+
+func (apl *APLRotation) doPrepullAction(sim *Simulation, prepullAction *aplPrepullAction) {
+	if !prepullAction.action.IsAvailable(sim) {
+		if sim.Log != nil {
+			apl.unit.Log(sim, "Skipping prepull action %s, it is not available.", prepullAction.location)
+		}
+		return
+	}
+
+	if sim.Log != nil {
+		apl.unit.Log(sim, "Performing prepull action %s", prepullAction.location)
+	}
+	prepullAction.action.Execute(sim)
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+func TestAPLPrepull(t *testing.T) {
+	player := newTestCaster("Caster", `prepull+=/cast_spell,id=item:40211,at=-3s
+prepull+=/cast_spell,id=1,at=-3s
+actions+=/autocast_cooldowns
+actions+=/cast_spell,id=2
+`)
+	player.Consumes = &proto.Consumes{DefaultPotion: proto.Potions_PotionOfSpeed}
+	rsr := newTestRaidSimRequest([]*proto.Player{player}, &proto.Encounter{
+		Duration: 60,
+		Targets:  []*proto.Target{newTestTarget(0)},
+	}, 5)
+
+	playerMetrics := runTestRaidSim(t, rsr).RaidMetrics.Parties[0].Players[0]
+
+	prepullMetrics := 0
+	for _, metrics := range playerMetrics.AplActions {
+		if !strings.HasPrefix(metrics.Location, "prepull") {
+			continue
+		}
+		prepullMetrics++
+		if metrics.UsedIterationsRatio != 1 || metrics.FirstUsedSecondsAvg != -3 {
+			t.Fatalf("Expected %s to be used at -3s in every iteration, got ratio %0.2f at %0.2fs", metrics.Location, metrics.UsedIterationsRatio, metrics.FirstUsedSecondsAvg)
+		}
+	}
+	if prepullMetrics != 2 {
+		t.Fatalf("Expected metrics for the 2 prepull actions, got %d", prepullMetrics)
+	}
+
+	// The prepull potion still leaves one for combat.
+	casts := map[int32]int32{}
+	for _, action := range playerMetrics.Actions {
+		for _, target := range action.Targets {
+			casts[action.Id.GetItemId()+action.Id.GetSpellId()] += target.Casts
+		}
+	}
+	if expected := 2 * rsr.SimOptions.Iterations; casts[40211] != expected {
+		t.Fatalf("Expected %d potion uses, got %d", expected, casts[40211])
+	}
+	// The rotation only casts the bolt before combat.
+	if expected := rsr.SimOptions.Iterations; casts[testBoltID] != expected {
+		t.Fatalf("Expected %d bolt casts, got %d", expected, casts[testBoltID])
+	}
+}
+
+func TestAPLPrepullOverlap(t *testing.T) {
+	result := ValidateAPL(&proto.ValidateAPLRequest{
+		Raid: SinglePlayerRaidProto(newTestCaster("Caster", `prepull+=/cast_spell,id=1,at=-3s
+prepull+=/cast_spell,id=2,at=-2s
+`), nil, nil, nil),
+	})
+
+	validations := result.Parties[0].Players[0].Validations
+	if len(validations) != 1 || validations[0].Location != "prepull[1]" {
+		t.Fatalf("Expected an overlap error for prepull[1], got %v", validations)
+	}
+}
@@ -0,0 +1,39 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// Built-in APL rotations, by spec and name.
+var aplPresets = make(map[proto.Spec]map[string]*proto.APLRotation)
+
+// Registers a built-in rotation, which players of the given spec can select
+// by name with APLRotation.preset.
+func RegisterAPLPreset(spec proto.Spec, name string, rotation *proto.APLRotation) {
+	if rotation.Preset != "" {
+		panic(fmt.Sprintf("APL preset %s cannot itself refer to a preset", name))
+	}
+	if aplPresets[spec] == nil {
+		aplPresets[spec] = make(map[string]*proto.APLRotation)
+	}
+	if _, ok := aplPresets[spec][name]; ok {
+		panic(fmt.Sprintf("Already registered APL preset %s for %s", name, spec))
+	}
+	aplPresets[spec][name] = rotation
+}
+
+// Returns the built-in rotation with the given name, or nil if there is none.
+func GetAPLPreset(spec proto.Spec, name string) *proto.APLRotation {
+	return aplPresets[spec][name]
+}
+
+// Like APLRotationFromText, but panics on failure. Intended for built-in rotations.
+func APLRotationFromTextString(text string) *proto.APLRotation {
+	rotation, err := APLRotationFromText(text)
+	if err != nil {
+		panic(err)
+	}
+	return rotation
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+func TestTraceAPL(t *testing.T) {
+	rsr := newTestRaidSimRequest([]*proto.Player{newTestCaster("Caster", `actions+=/cast_spell,id=6
+actions+=/cast_spell,id=2,if=remaining_time>30s
+actions+=/cast_spell,id=4
+`)}, &proto.Encounter{
+		Duration: 60,
+		Targets:  []*proto.Target{newTestTarget(0)},
+	}, 3)
+	rsr.SimOptions.TraceApl = true
+	result := runTestRaidSim(t, rsr)
+
+	// The rotation steps every 1.5s, 41 times. Blast comes off cooldown every
+	// 12s, leaving shocks for the first half of the fight and rolls for the
+	// second.
+	expected := []*proto.APLActionMetrics{
+		{Location: "actions[0]", EvaluationsAvg: 41, ConditionTrueAvg: 41, ExecutionsAvg: 6, FirstUsedSecondsAvg: 0, LastUsedSecondsAvg: 60},
+		{Location: "actions[1]", EvaluationsAvg: 35, ConditionTrueAvg: 17, ExecutionsAvg: 17, FirstUsedSecondsAvg: 1.5, LastUsedSecondsAvg: 28.5},
+		{Location: "actions[2]", EvaluationsAvg: 18, ConditionTrueAvg: 18, ExecutionsAvg: 18, FirstUsedSecondsAvg: 30, LastUsedSecondsAvg: 58.5},
+	}
+	aplActions := result.RaidMetrics.Parties[0].Players[0].AplActions
+	if len(aplActions) != len(expected) {
+		t.Fatalf("Expected metrics for %d actions, got %d", len(expected), len(aplActions))
+	}
+	for i, actual := range aplActions {
+		e := expected[i]
+		if actual.Location != e.Location || actual.EvaluationsAvg != e.EvaluationsAvg || actual.ConditionTrueAvg != e.ConditionTrueAvg ||
+			actual.ExecutionsAvg != e.ExecutionsAvg || actual.UsedIterationsRatio != 1 ||
+			actual.FirstUsedSecondsAvg != e.FirstUsedSecondsAvg || actual.LastUsedSecondsAvg != e.LastUsedSecondsAvg {
+			t.Fatalf("Unexpected metrics for %s: %v", e.Location, actual)
+		}
+	}
+
+	// Tracing is off by default.
+	rsr.SimOptions.TraceApl = false
+	if aplActions := runTestRaidSim(t, rsr).RaidMetrics.Parties[0].Players[0].AplActions; len(aplActions) != 0 {
+		t.Fatalf("Expected no APL metrics without tracing, got %v", aplActions)
+	}
+}
@@ -0,0 +1,988 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Text format for APL rotations, modeled after simc action lists. Example:
+//
+//	variable.casts=0
+//	prepull+=/cast_spell,id=48465,at=-2s
+//	# Notes for the next action.
+//	actions+=/call_action_list,name=execute,if=target_health_percent<35%
+//	actions+=/cast_spell,id=47241,if=!dot_is_active(47241)&mana_percent>30%
+//	actions.execute+=/cast_spell,id=47809
+//
+// Action and value names, and their argument names, are the field names from
+// apl.proto, so new actions and values are supported without changes here.
+// Arguments of a value are passed in parentheses, where the first field may
+// also be given without its name. An ActionID in the first field of a message
+// may also be passed as 'id'. A built-in rotation can be selected with a
+// 'preset=<name>' line.
+
+// Error in APL text, with the position where it was found. Lines and columns
+// start at 1.
+type APLTextError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (err *APLTextError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", err.Line, err.Column, err.Message)
+}
+
+var aplActionOneof = (&proto.APLAction{}).ProtoReflect().Descriptor().Oneofs().ByName("action")
+var aplValueOneof = (&proto.APLValue{}).ProtoReflect().Descriptor().Oneofs().ByName("value")
+var aplListItemHideField = (&proto.APLListItem{}).ProtoReflect().Descriptor().Fields().ByName("hide")
+
+// Shorter, simc-style names which may be used instead of the full value name.
+var aplTextValueAliases = map[string]string{
+	"time":         "current_time",
+	"mana":         "current_mana",
+	"mana_percent": "current_mana_percent",
+	"rage":         "current_rage",
+	"energy":       "current_energy",
+	"focus":        "current_focus",
+	"runic_power":  "current_runic_power",
+	"combo_points": "current_combo_points",
+}
+
+var aplTextLineRegex = regexp.MustCompile(`^(actions|prepull|variable|preset)(?:\.([A-Za-z_][A-Za-z0-9_]*))?(\+?=)`)
+var aplTextIdentRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+var aplTextNumberRegex = regexp.MustCompile(`^-?[0-9.][A-Za-z0-9.%]*$`)
+
+// Parses a rotation written in the APL text format. The returned rotation is
+// always enabled.
+func APLRotationFromText(text string) (rotation *proto.APLRotation, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			textErr, ok := r.(*APLTextError)
+			if !ok {
+				panic(r)
+			}
+			rotation = nil
+			err = textErr
+		}
+	}()
+
+	rotation = &proto.APLRotation{Enabled: true}
+	actionLists := make(map[string]*proto.APLActionList)
+	var notes []string
+
+	for lineIdx, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		startCol := strings.Index(line, trimmed) + 1
+
+		if trimmed == "" {
+			notes = nil
+			continue
+		} else if strings.HasPrefix(trimmed, "#") {
+			notes = append(notes, strings.TrimSpace(trimmed[1:]))
+			continue
+		}
+
+		p := &aplTextParser{line: lineIdx + 1}
+		match := aplTextLineRegex.FindStringSubmatch(trimmed)
+		if match == nil {
+			p.fail(startCol, "Expected 'actions', 'prepull', 'variable' or 'preset'")
+		}
+		section, name, op := match[1], match[2], match[3]
+		rest := trimmed[len(match[0]):]
+		restCol := startCol + len(match[0])
+
+		if section == "preset" {
+			if name != "" || op != "=" || !aplTextIdentRegex.MatchString(strings.TrimSpace(rest)) {
+				p.fail(startCol, "Presets must be selected as preset=<name>")
+			}
+			rotation.Preset = strings.TrimSpace(rest)
+			notes = nil
+			continue
+		}
+
+		if section == "variable" {
+			if name == "" || op != "=" {
+				p.fail(startCol, "Variables must be declared as variable.<name>=<value>")
+			}
+			value, parseErr := strconv.ParseFloat(strings.TrimSpace(rest), 64)
+			if parseErr != nil {
+				p.fail(restCol, "Invalid variable value %q", rest)
+			}
+			rotation.Variables = append(rotation.Variables, &proto.APLVariable{
+				Name:         name,
+				InitialValue: value,
+			})
+			notes = nil
+			continue
+		}
+
+		if section == "prepull" && name != "" {
+			p.fail(startCol, "Prepull actions cannot be named")
+		}
+
+		var listItems *[]*proto.APLListItem
+		if section == "actions" {
+			if name == "" {
+				listItems = &rotation.PriorityList
+			} else {
+				list := actionLists[name]
+				if list == nil {
+					list = &proto.APLActionList{Name: name}
+					actionLists[name] = list
+					rotation.ActionLists = append(rotation.ActionLists, list)
+				}
+				listItems = &list.PriorityList
+			}
+			if op == "=" {
+				*listItems = nil
+			}
+		} else if op == "=" {
+			rotation.PrepullActions = nil
+		}
+
+		if rest == "" && op == "=" {
+			notes = nil
+			continue
+		}
+		if !strings.HasPrefix(rest, "/") {
+			p.fail(restCol, "Expected '/' before action")
+		}
+
+		p.tokens = tokenizeAPLText(p.line, rest[1:], restCol+1)
+		if section == "prepull" {
+			prepullAction := &proto.APLPrepullAction{}
+			prepullAction.Action = p.parseAction(func(key string) bool {
+				if key != "at" {
+					return false
+				}
+				prepullAction.DoAt = p.parseDuration()
+				return true
+			})
+			p.expect(aplTokenEOF, "")
+			rotation.PrepullActions = append(rotation.PrepullActions, prepullAction)
+		} else {
+			listItem := &proto.APLListItem{
+				Notes: strings.Join(notes, "\n"),
+			}
+			listItem.Action = p.parseAction(func(key string) bool {
+				if key != "hide" {
+					return false
+				}
+				listItem.Hide = p.parseSingular(aplListItemHideField).Bool()
+				return true
+			})
+			p.expect(aplTokenEOF, "")
+			*listItems = append(*listItems, listItem)
+		}
+		notes = nil
+	}
+
+	return rotation, nil
+}
+
+type aplTokenKind int
+
+const (
+	aplTokenEOF aplTokenKind = iota
+	aplTokenIdent
+	aplTokenNumber
+	aplTokenString
+	aplTokenPunct
+)
+
+type aplToken struct {
+	kind aplTokenKind
+	text string
+	col  int
+}
+
+// Punctuation, longest first so that multi-character operators win.
+var aplTextPuncts = []string{"==", "!=", "<=", ">=", ",", "=", "(", ")", "{", "}", "[", "]", ":", "@", "&", "|", "!", "<", ">", "+", "-", "*", "/"}
+
+func tokenizeAPLText(line int, text string, startCol int) []aplToken {
+	var tokens []aplToken
+	i := 0
+	for i < len(text) {
+		c := text[i]
+		col := startCol + i
+
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+			j := i + 1
+			for j < len(text) && (text[j] == '_' || isAlphaNumeric(text[j])) {
+				j++
+			}
+			tokens = append(tokens, aplToken{kind: aplTokenIdent, text: text[i:j], col: col})
+			i = j
+		case c == '.' || (c >= '0' && c <= '9'):
+			// Numbers may have suffixes, e.g. 1.5s or 30%.
+			j := i + 1
+			for j < len(text) && (text[j] == '.' || text[j] == '%' || isAlphaNumeric(text[j])) {
+				j++
+			}
+			tokens = append(tokens, aplToken{kind: aplTokenNumber, text: text[i:j], col: col})
+			i = j
+		case c == '"':
+			j := i + 1
+			for j < len(text) && text[j] != '"' {
+				if text[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(text) {
+				panic(&APLTextError{Line: line, Column: col, Message: "Unterminated string"})
+			}
+			str, err := strconv.Unquote(text[i : j+1])
+			if err != nil {
+				panic(&APLTextError{Line: line, Column: col, Message: "Invalid string " + text[i:j+1]})
+			}
+			tokens = append(tokens, aplToken{kind: aplTokenString, text: str, col: col})
+			i = j + 1
+		default:
+			found := false
+			for _, punct := range aplTextPuncts {
+				if strings.HasPrefix(text[i:], punct) {
+					tokens = append(tokens, aplToken{kind: aplTokenPunct, text: punct, col: col})
+					i += len(punct)
+					found = true
+					break
+				}
+			}
+			if !found {
+				panic(&APLTextError{Line: line, Column: col, Message: fmt.Sprintf("Unexpected character %q", c)})
+			}
+		}
+	}
+	return append(tokens, aplToken{kind: aplTokenEOF, col: startCol + len(text)})
+}
+
+func isAlphaNumeric(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+type aplTextParser struct {
+	line   int
+	tokens []aplToken
+	pos    int
+}
+
+func (p *aplTextParser) fail(col int, message string, vals ...interface{}) {
+	panic(&APLTextError{Line: p.line, Column: col, Message: fmt.Sprintf(message, vals...)})
+}
+
+func (p *aplTextParser) peek() aplToken {
+	return p.tokens[p.pos]
+}
+
+func (p *aplTextParser) peekIs(kind aplTokenKind, text string) bool {
+	tok := p.peek()
+	return tok.kind == kind && (text == "" || tok.text == text)
+}
+
+func (p *aplTextParser) next() aplToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != aplTokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *aplTextParser) expect(kind aplTokenKind, text string) aplToken {
+	if !p.peekIs(kind, text) {
+		tok := p.peek()
+		var expected string
+		switch {
+		case kind == aplTokenEOF:
+			expected = "end of line"
+		case text != "":
+			expected = "'" + text + "'"
+		case kind == aplTokenIdent:
+			expected = "a name"
+		case kind == aplTokenNumber:
+			expected = "a number"
+		default:
+			expected = "a string"
+		}
+		if tok.kind == aplTokenEOF {
+			p.fail(tok.col, "Expected %s but reached end of line", expected)
+		} else {
+			p.fail(tok.col, "Expected %s but found '%s'", expected, tok.text)
+		}
+	}
+	return p.next()
+}
+
+// Parses an action and its arguments, e.g. 'cast_spell,id=123,if=<expr>'.
+// extraKey is called for argument names which are not action fields, and
+// should parse the argument value and return true if it is handled.
+func (p *aplTextParser) parseAction(extraKey func(key string) bool) *proto.APLAction {
+	nameTok := p.expect(aplTokenIdent, "")
+	fd := aplActionOneof.Fields().ByName(protoreflect.Name(nameTok.text))
+	if fd == nil {
+		p.fail(nameTok.col, "Unknown action '%s'", nameTok.text)
+	}
+
+	action := &proto.APLAction{}
+	impl := action.ProtoReflect().Mutable(fd).Message()
+
+	for p.peekIs(aplTokenPunct, ",") {
+		p.next()
+		keyTok := p.expect(aplTokenIdent, "")
+		p.expect(aplTokenPunct, "=")
+
+		if keyTok.text == "if" {
+			if action.Condition != nil {
+				p.fail(keyTok.col, "Duplicate argument 'if'")
+			}
+			action.Condition = p.parseExpr()
+		} else if extraKey == nil || !extraKey(keyTok.text) {
+			p.parseFieldValue(impl, keyTok)
+		}
+	}
+	return action
+}
+
+// Finds the field with the given argument name, handling the 'id' alias.
+func aplTextField(desc protoreflect.MessageDescriptor, key string) protoreflect.FieldDescriptor {
+	if key == "id" && desc.Fields().Len() > 0 && isActionIDField(desc.Fields().Get(0)) {
+		return desc.Fields().Get(0)
+	}
+	return desc.Fields().ByName(protoreflect.Name(key))
+}
+
+func isActionIDField(fd protoreflect.FieldDescriptor) bool {
+	return fd.Kind() == protoreflect.MessageKind && !fd.IsList() && fd.Message().FullName() == "proto.ActionID"
+}
+
+// Name of the argument for a field. ActionIDs in the first field are always
+// written as 'id'.
+func aplTextFieldKey(fd protoreflect.FieldDescriptor) string {
+	if fd.Index() == 0 && isActionIDField(fd) {
+		return "id"
+	}
+	return string(fd.Name())
+}
+
+// Parses the value for the argument named by keyTok, after the '='.
+func (p *aplTextParser) parseFieldValue(msg protoreflect.Message, keyTok aplToken) {
+	fd := aplTextField(msg.Descriptor(), keyTok.text)
+	if fd == nil {
+		p.fail(keyTok.col, "Unknown argument '%s' for %s", keyTok.text, msg.Descriptor().Name())
+	}
+	if msg.Has(fd) {
+		p.fail(keyTok.col, "Duplicate argument '%s'", keyTok.text)
+	}
+
+	if fd.IsList() {
+		p.expect(aplTokenPunct, "[")
+		list := msg.Mutable(fd).List()
+		for !p.peekIs(aplTokenPunct, "]") {
+			list.Append(p.parseSingular(fd))
+			if !p.peekIs(aplTokenPunct, ",") {
+				break
+			}
+			p.next()
+		}
+		p.expect(aplTokenPunct, "]")
+	} else {
+		msg.Set(fd, p.parseSingular(fd))
+	}
+}
+
+// Parses arguments up to the closing token. If positional is set, arguments
+// without a name are assigned to the first field.
+func (p *aplTextParser) parseArgs(msg protoreflect.Message, closer string, positional bool) {
+	desc := msg.Descriptor()
+	for !p.peekIs(aplTokenPunct, closer) {
+		tok := p.peek()
+		isKeyed := tok.kind == aplTokenIdent &&
+			p.tokens[p.pos+1].kind == aplTokenPunct && p.tokens[p.pos+1].text == "=" &&
+			aplTextField(desc, tok.text) != nil
+		if isKeyed || !positional {
+			keyTok := p.expect(aplTokenIdent, "")
+			p.expect(aplTokenPunct, "=")
+			p.parseFieldValue(msg, keyTok)
+		} else {
+			if desc.Fields().Len() == 0 {
+				p.fail(tok.col, "%s does not take any arguments", desc.Name())
+			}
+			fd := desc.Fields().Get(0)
+			if fd.IsList() {
+				msg.Mutable(fd).List().Append(p.parseSingular(fd))
+			} else if msg.Has(fd) {
+				p.fail(tok.col, "Too many arguments for %s", desc.Name())
+			} else {
+				msg.Set(fd, p.parseSingular(fd))
+			}
+		}
+
+		if !p.peekIs(aplTokenPunct, ",") {
+			break
+		}
+		p.next()
+	}
+	p.expect(aplTokenPunct, closer)
+}
+
+func newAPLTextMessage(desc protoreflect.MessageDescriptor) protoreflect.Message {
+	msgType, err := protoregistry.GlobalTypes.FindMessageByName(desc.FullName())
+	if err != nil {
+		panic(err)
+	}
+	return msgType.New()
+}
+
+// Parses a single value for a field. For list fields, parses one element.
+func (p *aplTextParser) parseSingular(fd protoreflect.FieldDescriptor) protoreflect.Value {
+	tok := p.peek()
+	switch fd.Kind() {
+	case protoreflect.MessageKind:
+		switch fd.Message().FullName() {
+		case "proto.APLValue":
+			return protoreflect.ValueOfMessage(p.parseExpr().ProtoReflect())
+		case "proto.APLAction":
+			p.expect(aplTokenPunct, "{")
+			action := p.parseAction(nil)
+			p.expect(aplTokenPunct, "}")
+			return protoreflect.ValueOfMessage(action.ProtoReflect())
+		case "proto.ActionID":
+			return protoreflect.ValueOfMessage(p.parseActionID().ProtoReflect())
+		case "proto.Duration":
+			return protoreflect.ValueOfMessage(p.parseDuration().ProtoReflect())
+		default:
+			p.expect(aplTokenPunct, "{")
+			msg := newAPLTextMessage(fd.Message())
+			p.parseArgs(msg, "}", false)
+			return protoreflect.ValueOfMessage(msg)
+		}
+	case protoreflect.EnumKind:
+		nameTok := p.expect(aplTokenIdent, "")
+		enumVal := fd.Enum().Values().ByName(protoreflect.Name(nameTok.text))
+		if enumVal == nil {
+			p.fail(nameTok.col, "Unknown %s value '%s'", fd.Enum().Name(), nameTok.text)
+		}
+		return protoreflect.ValueOfEnum(enumVal.Number())
+	case protoreflect.BoolKind:
+		p.next()
+		switch tok.text {
+		case "true", "1":
+			return protoreflect.ValueOfBool(true)
+		case "false", "0":
+			return protoreflect.ValueOfBool(false)
+		}
+		p.fail(tok.col, "Expected true or false but found '%s'", tok.text)
+	case protoreflect.StringKind:
+		if tok.kind != aplTokenIdent && tok.kind != aplTokenString {
+			p.expect(aplTokenString, "")
+		}
+		return protoreflect.ValueOfString(p.next().text)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return protoreflect.ValueOfInt32(int32(p.parseInt(32)))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return protoreflect.ValueOfInt64(p.parseInt(64))
+	case protoreflect.FloatKind:
+		return protoreflect.ValueOfFloat32(float32(p.parseFloat()))
+	case protoreflect.DoubleKind:
+		return protoreflect.ValueOfFloat64(p.parseFloat())
+	}
+	p.fail(tok.col, "Unsupported argument type for '%s'", fd.Name())
+	return protoreflect.Value{}
+}
+
+// Returns the text of a number token, including a leading '-'.
+func (p *aplTextParser) parseSignedNumber() aplToken {
+	sign := ""
+	if p.peekIs(aplTokenPunct, "-") {
+		p.next()
+		sign = "-"
+	}
+	tok := p.expect(aplTokenNumber, "")
+	tok.text = sign + tok.text
+	return tok
+}
+
+func (p *aplTextParser) parseInt(bitSize int) int64 {
+	tok := p.parseSignedNumber()
+	val, err := strconv.ParseInt(tok.text, 10, bitSize)
+	if err != nil {
+		p.fail(tok.col, "Invalid integer '%s'", tok.text)
+	}
+	return val
+}
+
+func (p *aplTextParser) parseFloat() float64 {
+	tok := p.parseSignedNumber()
+	val, err := strconv.ParseFloat(tok.text, 64)
+	if err != nil {
+		p.fail(tok.col, "Invalid number '%s'", tok.text)
+	}
+	return val
+}
+
+func (p *aplTextParser) parseDuration() *proto.Duration {
+	tok := p.parseSignedNumber()
+	dur, err := time.ParseDuration(tok.text)
+	if err != nil {
+		p.fail(tok.col, "Invalid duration '%s', expected e.g. 1.5s or 500ms", tok.text)
+	}
+	return &proto.Duration{Ms: float64(dur) / float64(time.Millisecond)}
+}
+
+// Parses an ActionID: a spell ID, or item:<id> or other:<OtherAction>, with an
+// optional @<tag>.
+func (p *aplTextParser) parseActionID() *proto.ActionID {
+	actionID := &proto.ActionID{}
+	if p.peekIs(aplTokenNumber, "") {
+		actionID.RawId = &proto.ActionID_SpellId{SpellId: int32(p.parseInt(32))}
+	} else {
+		kindTok := p.expect(aplTokenIdent, "")
+		p.expect(aplTokenPunct, ":")
+		switch kindTok.text {
+		case "spell":
+			actionID.RawId = &proto.ActionID_SpellId{SpellId: int32(p.parseInt(32))}
+		case "item":
+			actionID.RawId = &proto.ActionID_ItemId{ItemId: int32(p.parseInt(32))}
+		case "other":
+			nameTok := p.expect(aplTokenIdent, "")
+			otherID, ok := proto.OtherAction_value[nameTok.text]
+			if !ok {
+				p.fail(nameTok.col, "Unknown OtherAction '%s'", nameTok.text)
+			}
+			actionID.RawId = &proto.ActionID_OtherId{OtherId: proto.OtherAction(otherID)}
+		default:
+			p.fail(kindTok.col, "Expected spell, item or other but found '%s'", kindTok.text)
+		}
+	}
+
+	if p.peekIs(aplTokenPunct, "@") {
+		p.next()
+		actionID.Tag = int32(p.parseInt(32))
+	}
+	return actionID
+}
+
+var aplTextCmpOps = map[string]proto.APLValueCompare_ComparisonOperator{
+	"=":  proto.APLValueCompare_OpEq,
+	"==": proto.APLValueCompare_OpEq,
+	"!=": proto.APLValueCompare_OpNe,
+	"<":  proto.APLValueCompare_OpLt,
+	"<=": proto.APLValueCompare_OpLe,
+	">":  proto.APLValueCompare_OpGt,
+	">=": proto.APLValueCompare_OpGe,
+}
+
+var aplTextMathOps = map[string]proto.APLValueMath_MathOperator{
+	"+": proto.APLValueMath_OpAdd,
+	"-": proto.APLValueMath_OpSub,
+	"*": proto.APLValueMath_OpMul,
+	"/": proto.APLValueMath_OpDiv,
+}
+
+// Parses an expression. Precedence, from lowest to highest:
+// '|', '&', comparisons, '+' and '-', '*' and '/', then unary '!' and '-'.
+func (p *aplTextParser) parseExpr() *proto.APLValue {
+	vals := []*proto.APLValue{p.parseAnd()}
+	for p.peekIs(aplTokenPunct, "|") {
+		p.next()
+		vals = append(vals, p.parseAnd())
+	}
+	if len(vals) == 1 {
+		return vals[0]
+	}
+	return &proto.APLValue{Value: &proto.APLValue_Or{Or: &proto.APLValueOr{Vals: vals}}}
+}
+
+func (p *aplTextParser) parseAnd() *proto.APLValue {
+	vals := []*proto.APLValue{p.parseCmp()}
+	for p.peekIs(aplTokenPunct, "&") {
+		p.next()
+		vals = append(vals, p.parseCmp())
+	}
+	if len(vals) == 1 {
+		return vals[0]
+	}
+	return &proto.APLValue{Value: &proto.APLValue_And{And: &proto.APLValueAnd{Vals: vals}}}
+}
+
+func (p *aplTextParser) parseCmp() *proto.APLValue {
+	lhs := p.parseSum()
+	if tok := p.peek(); tok.kind == aplTokenPunct {
+		if op, ok := aplTextCmpOps[tok.text]; ok {
+			p.next()
+			return &proto.APLValue{Value: &proto.APLValue_Cmp{Cmp: &proto.APLValueCompare{
+				Op:  op,
+				Lhs: lhs,
+				Rhs: p.parseSum(),
+			}}}
+		}
+	}
+	return lhs
+}
+
+func (p *aplTextParser) parseSum() *proto.APLValue {
+	lhs := p.parseProduct()
+	for p.peekIs(aplTokenPunct, "+") || p.peekIs(aplTokenPunct, "-") {
+		op := aplTextMathOps[p.next().text]
+		lhs = &proto.APLValue{Value: &proto.APLValue_Math{Math: &proto.APLValueMath{
+			Op:  op,
+			Lhs: lhs,
+			Rhs: p.parseProduct(),
+		}}}
+	}
+	return lhs
+}
+
+func (p *aplTextParser) parseProduct() *proto.APLValue {
+	lhs := p.parseUnary()
+	for p.peekIs(aplTokenPunct, "*") || p.peekIs(aplTokenPunct, "/") {
+		op := aplTextMathOps[p.next().text]
+		lhs = &proto.APLValue{Value: &proto.APLValue_Math{Math: &proto.APLValueMath{
+			Op:  op,
+			Lhs: lhs,
+			Rhs: p.parseUnary(),
+		}}}
+	}
+	return lhs
+}
+
+func (p *aplTextParser) parseUnary() *proto.APLValue {
+	if p.peekIs(aplTokenPunct, "!") {
+		p.next()
+		return &proto.APLValue{Value: &proto.APLValue_Not{Not: &proto.APLValueNot{Val: p.parseUnary()}}}
+	} else if p.peekIs(aplTokenPunct, "-") {
+		return newAPLTextConst(p.parseSignedNumber().text)
+	}
+	return p.parsePrimary()
+}
+
+func newAPLTextConst(val string) *proto.APLValue {
+	return &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: val}}}
+}
+
+func (p *aplTextParser) parsePrimary() *proto.APLValue {
+	tok := p.next()
+	switch tok.kind {
+	case aplTokenNumber, aplTokenString:
+		return newAPLTextConst(tok.text)
+	case aplTokenIdent:
+		name := tok.text
+		if alias, ok := aplTextValueAliases[name]; ok {
+			name = alias
+		}
+		fd := aplValueOneof.Fields().ByName(protoreflect.Name(name))
+		if fd == nil {
+			p.fail(tok.col, "Unknown value '%s'", tok.text)
+		}
+
+		msg := newAPLTextMessage(fd.Message())
+		if p.peekIs(aplTokenPunct, "(") {
+			p.next()
+			p.parseArgs(msg, ")", true)
+		}
+		value := &proto.APLValue{}
+		value.ProtoReflect().Set(fd, protoreflect.ValueOfMessage(msg))
+		return value
+	case aplTokenPunct:
+		if tok.text == "(" {
+			value := p.parseExpr()
+			p.expect(aplTokenPunct, ")")
+			return value
+		}
+		p.fail(tok.col, "Unexpected '%s'", tok.text)
+	}
+	p.fail(tok.col, "Expected a value but reached end of line")
+	return nil
+}
+
+// Writes a rotation in the APL text format. The result parses back into an
+// equivalent rotation with APLRotationFromText.
+func APLRotationToText(rotation *proto.APLRotation) string {
+	var sections []string
+	if rotation.Preset != "" {
+		sections = append(sections, "preset="+rotation.Preset)
+	}
+
+	var lines []string
+	for _, variable := range rotation.Variables {
+		lines = append(lines, fmt.Sprintf("variable.%s=%s", variable.Name, strconv.FormatFloat(variable.InitialValue, 'f', -1, 64)))
+	}
+	if len(lines) > 0 {
+		sections = append(sections, strings.Join(lines, "\n"))
+	}
+
+	lines = nil
+	for _, prepullAction := range rotation.PrepullActions {
+		if prepullAction.Action == nil {
+			continue
+		}
+		line := "prepull+=/" + printAPLTextAction(prepullAction.Action)
+		if prepullAction.DoAt != nil {
+			line += ",at=" + printAPLTextDuration(prepullAction.DoAt)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) > 0 {
+		sections = append(sections, strings.Join(lines, "\n"))
+	}
+
+	if len(rotation.PriorityList) > 0 {
+		sections = append(sections, printAPLTextListItems("actions", rotation.PriorityList))
+	}
+	for _, list := range rotation.ActionLists {
+		if len(list.PriorityList) == 0 {
+			sections = append(sections, "actions."+list.Name+"=")
+		} else {
+			sections = append(sections, printAPLTextListItems("actions."+list.Name, list.PriorityList))
+		}
+	}
+
+	if len(sections) == 0 {
+		return ""
+	}
+	return strings.Join(sections, "\n\n") + "\n"
+}
+
+func printAPLTextListItems(prefix string, listItems []*proto.APLListItem) string {
+	var lines []string
+	for _, listItem := range listItems {
+		if listItem.Action == nil {
+			continue
+		}
+		if listItem.Notes != "" {
+			for _, note := range strings.Split(listItem.Notes, "\n") {
+				lines = append(lines, strings.TrimRight("# "+note, " "))
+			}
+		}
+		line := prefix + "+=/" + printAPLTextAction(listItem.Action)
+		if listItem.Hide {
+			line += ",hide=true"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func printAPLTextAction(action *proto.APLAction) string {
+	msg := action.ProtoReflect()
+	fd := msg.WhichOneof(aplActionOneof)
+	if fd == nil {
+		return ""
+	}
+
+	parts := []string{string(fd.Name())}
+	parts = append(parts, printAPLTextArgs(msg.Get(fd).Message())...)
+	if action.Condition != nil {
+		parts = append(parts, "if="+printAPLTextExpr(action.Condition, 0))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Returns all populated fields of the message as key=value strings.
+func printAPLTextArgs(msg protoreflect.Message) []string {
+	var args []string
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if msg.Has(fd) {
+			args = append(args, aplTextFieldKey(fd)+"="+printAPLTextField(msg, fd))
+		}
+	}
+	return args
+}
+
+func printAPLTextField(msg protoreflect.Message, fd protoreflect.FieldDescriptor) string {
+	if !fd.IsList() {
+		return printAPLTextSingular(fd, msg.Get(fd))
+	}
+
+	list := msg.Get(fd).List()
+	elems := make([]string, list.Len())
+	for i := range elems {
+		elems[i] = printAPLTextSingular(fd, list.Get(i))
+	}
+	return "[" + strings.Join(elems, ",") + "]"
+}
+
+func printAPLTextSingular(fd protoreflect.FieldDescriptor, val protoreflect.Value) string {
+	switch fd.Kind() {
+	case protoreflect.MessageKind:
+		switch m := val.Message().Interface().(type) {
+		case *proto.APLValue:
+			return printAPLTextExpr(m, 0)
+		case *proto.APLAction:
+			return "{" + printAPLTextAction(m) + "}"
+		case *proto.ActionID:
+			return printAPLTextActionID(m)
+		case *proto.Duration:
+			return printAPLTextDuration(m)
+		default:
+			return "{" + strings.Join(printAPLTextArgs(val.Message()), ",") + "}"
+		}
+	case protoreflect.EnumKind:
+		if enumVal := fd.Enum().Values().ByNumber(val.Enum()); enumVal != nil {
+			return string(enumVal.Name())
+		}
+		return strconv.Itoa(int(val.Enum()))
+	case protoreflect.StringKind:
+		return printAPLTextString(val.String())
+	case protoreflect.FloatKind:
+		return strconv.FormatFloat(val.Float(), 'f', -1, 32)
+	case protoreflect.DoubleKind:
+		return strconv.FormatFloat(val.Float(), 'f', -1, 64)
+	default:
+		return val.String()
+	}
+}
+
+func printAPLTextString(str string) string {
+	if aplTextIdentRegex.MatchString(str) {
+		return str
+	}
+	return strconv.Quote(str)
+}
+
+func printAPLTextDuration(dur *proto.Duration) string {
+	return DurationFromProto(dur).String()
+}
+
+func printAPLTextActionID(actionID *proto.ActionID) string {
+	var str string
+	switch id := actionID.RawId.(type) {
+	case *proto.ActionID_ItemId:
+		str = "item:" + strconv.Itoa(int(id.ItemId))
+	case *proto.ActionID_OtherId:
+		str = "other:" + id.OtherId.String()
+	default:
+		str = strconv.Itoa(int(actionID.GetSpellId()))
+	}
+	if actionID.Tag != 0 {
+		str += "@" + strconv.Itoa(int(actionID.Tag))
+	}
+	return str
+}
+
+var aplTextCmpOpStrings = map[proto.APLValueCompare_ComparisonOperator]string{
+	proto.APLValueCompare_OpEq: "=",
+	proto.APLValueCompare_OpNe: "!=",
+	proto.APLValueCompare_OpLt: "<",
+	proto.APLValueCompare_OpLe: "<=",
+	proto.APLValueCompare_OpGt: ">",
+	proto.APLValueCompare_OpGe: ">=",
+}
+
+var aplTextMathOpStrings = map[proto.APLValueMath_MathOperator]string{
+	proto.APLValueMath_OpAdd: "+",
+	proto.APLValueMath_OpSub: "-",
+	proto.APLValueMath_OpMul: "*",
+	proto.APLValueMath_OpDiv: "/",
+}
+
+// Precedence levels used when printing expressions, matching parseExpr.
+const (
+	aplTextPrecOr = iota + 1
+	aplTextPrecAnd
+	aplTextPrecCmp
+	aplTextPrecSum
+	aplTextPrecProduct
+	aplTextPrecUnary
+	aplTextPrecPrimary
+)
+
+// Prints a value, wrapping it in parentheses if its precedence is lower than minPrec.
+func printAPLTextExpr(value *proto.APLValue, minPrec int) string {
+	str, prec := printAPLTextExprHelper(value)
+	if prec < minPrec {
+		return "(" + str + ")"
+	}
+	return str
+}
+
+func printAPLTextExprHelper(value *proto.APLValue) (string, int) {
+	switch v := value.Value.(type) {
+	case *proto.APLValue_Const:
+		if aplTextNumberRegex.MatchString(v.Const.Val) {
+			return v.Const.Val, aplTextPrecPrimary
+		}
+		return strconv.Quote(v.Const.Val), aplTextPrecPrimary
+	case *proto.APLValue_Or:
+		if len(v.Or.Vals) >= 2 {
+			return printAPLTextExprList(v.Or.Vals, "|", aplTextPrecAnd), aplTextPrecOr
+		}
+	case *proto.APLValue_And:
+		if len(v.And.Vals) >= 2 {
+			return printAPLTextExprList(v.And.Vals, "&", aplTextPrecCmp), aplTextPrecAnd
+		}
+	case *proto.APLValue_Not:
+		if v.Not.Val != nil {
+			return "!" + printAPLTextExpr(v.Not.Val, aplTextPrecUnary), aplTextPrecUnary
+		}
+	case *proto.APLValue_Cmp:
+		if op, ok := aplTextCmpOpStrings[v.Cmp.Op]; ok && v.Cmp.Lhs != nil && v.Cmp.Rhs != nil {
+			return printAPLTextExpr(v.Cmp.Lhs, aplTextPrecSum) + op + printAPLTextExpr(v.Cmp.Rhs, aplTextPrecSum), aplTextPrecCmp
+		}
+	case *proto.APLValue_Math:
+		if op, ok := aplTextMathOpStrings[v.Math.Op]; ok && v.Math.Lhs != nil && v.Math.Rhs != nil {
+			prec := aplTextPrecSum
+			if v.Math.Op == proto.APLValueMath_OpMul || v.Math.Op == proto.APLValueMath_OpDiv {
+				prec = aplTextPrecProduct
+			}
+			return printAPLTextExpr(v.Math.Lhs, prec) + op + printAPLTextExpr(v.Math.Rhs, prec+1), prec
+		}
+	}
+
+	// Anything else uses the function call form, e.g. aura_is_active(id=123,on_target=true).
+	msg := value.ProtoReflect()
+	fd := msg.WhichOneof(aplValueOneof)
+	if fd == nil {
+		return "", aplTextPrecPrimary
+	}
+	return string(fd.Name()) + printAPLTextValueArgs(msg.Get(fd).Message()), aplTextPrecPrimary
+}
+
+func printAPLTextExprList(vals []*proto.APLValue, op string, minPrec int) string {
+	strs := make([]string, len(vals))
+	for i, val := range vals {
+		strs[i] = printAPLTextExpr(val, minPrec)
+	}
+	return strings.Join(strs, op)
+}
+
+// Prints the arguments for a value, using the positional form when only the
+// first field is set.
+func printAPLTextValueArgs(msg protoreflect.Message) string {
+	fields := msg.Descriptor().Fields()
+	numSet := 0
+	for i := 0; i < fields.Len(); i++ {
+		if msg.Has(fields.Get(i)) {
+			numSet++
+		}
+	}
+	if numSet == 0 {
+		return ""
+	}
+
+	if first := fields.Get(0); numSet == 1 && msg.Has(first) {
+		if !first.IsList() {
+			return "(" + printAPLTextSingular(first, msg.Get(first)) + ")"
+		}
+		list := msg.Get(first).List()
+		elems := make([]string, list.Len())
+		for i := range elems {
+			elems[i] = printAPLTextSingular(first, list.Get(i))
+		}
+		return "(" + strings.Join(elems, ",") + ")"
+	}
+	return "(" + strings.Join(printAPLTextArgs(msg), ",") + ")"
+}
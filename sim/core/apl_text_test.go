@@ -0,0 +1,133 @@
+package core
+
+import (
+	"testing"
+
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+const testAPLText = `variable.casts=0
+variable.mode=1.5
+
+prepull+=/cast_spell,id=48465,at=-2s
+
+# Execute phase.
+actions+=/call_action_list,name=execute,if=target_health_percent<35%
+actions+=/cast_spell,id=47241,target={type=SelectMissingDot},if=!dot_is_active(47241)&current_mana_percent>30%|current_time>=10s
+actions+=/channel_spell,id=48156,interrupt_if=aura_remaining_time(id=other:OtherActionAttack@2,on_target=true)<1s
+actions+=/sequence,actions=[{activate_cooldown,id=item:40211},{wait,duration=500ms}]
+actions+=/add_variable,name=casts,value=max(1,variable(mode))*(2-abs(-3))
+actions+=/wait_until,condition="foo bar"!=current_rune_count(RuneBlood),hide=true
+
+actions.execute+=/cast_spell,id=47809
+
+actions.empty=
+`
+
+func TestAPLTextRoundTrip(t *testing.T) {
+	rotation, err := APLRotationFromText(testAPLText)
+	if err != nil {
+		t.Fatalf("Failed to parse APL text: %s", err)
+	}
+
+	if len(rotation.Variables) != 2 || len(rotation.PrepullActions) != 1 || len(rotation.PriorityList) != 6 || len(rotation.ActionLists) != 2 {
+		t.Fatalf("Unexpected rotation structure: %v", rotation)
+	}
+	if rotation.PriorityList[0].Notes != "Execute phase." {
+		t.Fatalf("Unexpected notes %q", rotation.PriorityList[0].Notes)
+	}
+	if !rotation.PriorityList[5].Hide {
+		t.Fatalf("Expected last action to be hidden")
+	}
+	if rotation.PrepullActions[0].DoAt.Ms != -2000 {
+		t.Fatalf("Unexpected prepull time %f", rotation.PrepullActions[0].DoAt.Ms)
+	}
+
+	// Or has lower precedence than and.
+	condition := rotation.PriorityList[1].Action.Condition
+	if condition.GetOr() == nil || len(condition.GetOr().Vals) != 2 || condition.GetOr().Vals[0].GetAnd() == nil {
+		t.Fatalf("Unexpected condition %v", condition)
+	}
+
+	text := APLRotationToText(rotation)
+	if text != testAPLText {
+		t.Fatalf("Printed text does not match input:\n%s", text)
+	}
+
+	reparsed, err := APLRotationFromText(text)
+	if err != nil {
+		t.Fatalf("Failed to parse printed APL text: %s", err)
+	}
+	if !googleProto.Equal(rotation, reparsed) {
+		t.Fatalf("Rotation changed after round trip")
+	}
+}
+
+func TestAPLTextFromJson(t *testing.T) {
+	rotation := APLRotationFromJsonString(`{
+		"enabled": true,
+		"priorityList": [
+			{"action": {"condition": {"and": {"vals": [
+				{"not": {"val": {"or": {"vals": [{"const": {"val": "1"}}, {"const": {"val": "2"}}]}}}},
+				{"cmp": {"op": "OpLt", "lhs": {"math": {"op": "OpSub", "lhs": {"const": {"val": "1"}}, "rhs": {"math": {"op": "OpSub", "lhs": {"const": {"val": "2"}}, "rhs": {"const": {"val": "3"}}}}}}, "rhs": {"const": {"val": "-5"}}}},
+				{"and": {"vals": [{"const": {"val": "true"}}]}}
+			]}}, "castSpell": {"spellId": {"spellId": 49001}}}}
+		]
+	}`)
+
+	reparsed, err := APLRotationFromText(APLRotationToText(rotation))
+	if err != nil {
+		t.Fatalf("Failed to parse printed APL text: %s", err)
+	}
+	if !googleProto.Equal(rotation, reparsed) {
+		t.Fatalf("Rotation changed after round trip:\n%s", APLRotationToText(reparsed))
+	}
+}
+
+func TestAPLTextErrors(t *testing.T) {
+	testCases := []struct {
+		text   string
+		line   int
+		column int
+	}{
+		{"actions+=/cast_spell,id=123\n  actions+=/fireball", 2, 13},
+		{"actions+=/cast_spell,id=123,if=current_mana>", 1, 45},
+		{"actions+=/cast_spell,spell=123", 1, 22},
+		{"# comment\nrotation+=/wait", 2, 1},
+		{"actions+=/wait,duration=5", 1, 25},
+	}
+
+	for _, testCase := range testCases {
+		_, err := APLRotationFromText(testCase.text)
+		textErr, ok := err.(*APLTextError)
+		if !ok {
+			t.Fatalf("Expected APLTextError for %q, got %v", testCase.text, err)
+		}
+		if textErr.Line != testCase.line || textErr.Column != testCase.column {
+			t.Fatalf("Expected error at %d:%d for %q, got %s", testCase.line, testCase.column, testCase.text, textErr)
+		}
+	}
+}
+
+// Makes sure printing and parsing cover every action and value type.
+func TestAPLTextAllTypes(t *testing.T) {
+	fields := aplActionOneof.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		text := "actions+=/" + string(fields.Get(i).Name())
+		if _, err := APLRotationFromText(text); err != nil {
+			t.Fatalf("Failed to parse %q: %s", text, err)
+		}
+	}
+
+	fields = aplValueOneof.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		text := "actions+=/wait,if=" + string(fields.Get(i).Name())
+		rotation, err := APLRotationFromText(text)
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %s", text, err)
+		}
+		if rotation.PriorityList[0].Action.Condition.Value == nil {
+			t.Fatalf("Missing value for %q", text)
+		}
+	}
+}
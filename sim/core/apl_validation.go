@@ -0,0 +1,196 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// Runs f, recording any failure at the given location if validations are
+// being collected. Returns whether f completed.
+func (apl *APLRotation) tryValidate(location string, f func()) (ok bool) {
+	if !apl.collectValidations {
+		f()
+		return true
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			apl.addValidationFromPanic(location, r)
+			ok = false
+		}
+	}()
+	f()
+	return true
+}
+
+func (apl *APLRotation) addValidationFromPanic(location string, r interface{}) {
+	message := fmt.Sprint(r)
+	validationType := proto.APLValidation_ValidationTypeError
+	if strings.HasPrefix(message, validationErrorPrefix) {
+		message = strings.TrimPrefix(message, validationErrorPrefix)
+	} else if strings.HasPrefix(message, validationWarningPrefix) {
+		message = strings.TrimPrefix(message, validationWarningPrefix)
+		validationType = proto.APLValidation_ValidationTypeWarning
+	}
+	apl.addValidation(validationType, location, "%s", message)
+}
+
+func (apl *APLRotation) addValidation(validationType proto.APLValidation_ValidationType, location string, message string, vals ...interface{}) {
+	apl.validations = append(apl.validations, &proto.APLValidation{
+		ValidationType: validationType,
+		Message:        fmt.Sprintf(message, vals...),
+		Location:       location,
+	})
+}
+
+// Checks for actions which can never be performed. actions holds the built
+// action for each item in listItems, or nil for hidden and invalid items.
+func (apl *APLRotation) lintActionList(listName string, listItems []*proto.APLListItem, actions []*APLAction) {
+	blockedBy := ""
+	for i, action := range actions {
+		if listItems[i].Hide {
+			continue
+		}
+		location := fmt.Sprintf("%s[%d]", listName, i)
+
+		if blockedBy != "" {
+			apl.addValidation(proto.APLValidation_ValidationTypeWarning, location, "Action is unreachable, because %s is always performed first", blockedBy)
+		}
+		if action == nil {
+			continue
+		}
+
+		if action.condition != nil && aplValueIsConst(action.condition) && !action.condition.GetBool(nil) {
+			apl.addValidation(proto.APLValidation_ValidationTypeWarning, location, "Condition is always false, so this action is never performed")
+			continue
+		}
+
+		if blockedBy == "" && action.isAlwaysAvailable() {
+			blockedBy = location
+		}
+	}
+}
+
+// Whether an action is performed whenever it is reached: its condition is
+// missing or always true, and nothing but the GCD, an ongoing cast or movement
+// keeps it from being used.
+func (action *APLAction) isAlwaysAvailable() bool {
+	if action.condition != nil && !(aplValueIsConst(action.condition) && action.condition.GetBool(nil)) {
+		return false
+	}
+
+	switch impl := action.impl.(type) {
+	case *APLActionWait, *APLActionRunActionList:
+		return true
+	case *APLActionCastSpell:
+		spell := impl.spell
+		return impl.target.config.Type == proto.APLTargetSelector_SelectCurrentTarget &&
+			spell.ExtraCastCondition == nil && spell.CD.Timer == nil && spell.SharedCD.Timer == nil &&
+			(spell.Cost == nil || spell.DefaultCast.Cost == 0)
+	case *APLActionCallActionList:
+		for _, listAction := range impl.list.actions {
+			if !listAction.isVariableAction() && listAction.isAlwaysAvailable() {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// Whether a value never changes, i.e. is built only from constants.
+func aplValueIsConst(value APLValue) bool {
+	allConst := func(vals []APLValue) bool {
+		for _, val := range vals {
+			if !aplValueIsConst(val) {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch v := value.(type) {
+	case *APLValueConst:
+		return true
+	case *APLValueCoerced:
+		return aplValueIsConst(v.inner)
+	case *APLValueAnd:
+		return allConst(v.vals)
+	case *APLValueOr:
+		return allConst(v.vals)
+	case *APLValueNot:
+		return aplValueIsConst(v.val)
+	case *APLValueCompare:
+		return aplValueIsConst(v.lhs) && aplValueIsConst(v.rhs)
+	case *APLValueMath:
+		return aplValueIsConst(v.lhs) && aplValueIsConst(v.rhs)
+	case *APLValueMax:
+		return allConst(v.vals)
+	case *APLValueMin:
+		return allConst(v.vals)
+	case *APLValueAbs:
+		return aplValueIsConst(v.val)
+	default:
+		return false
+	}
+}
+
+// Builds the rotation for this unit without running the sim, and returns
+// all problems found.
+func (unit *Unit) validateAPLRotation(config *proto.APLRotation) []*proto.APLValidation {
+	prevRotation := unit.Rotation
+	defer func() {
+		unit.Rotation = prevRotation
+	}()
+
+	rotation := unit.buildAPLRotation(config, true)
+	if rotation == nil {
+		return nil
+	}
+	return rotation.validations
+}
+
+// Checks the APL rotations of all players in the raid, without running the sim.
+func validateAPLRotations(request *proto.ValidateAPLRequest) *proto.ValidateAPLResult {
+	// Rotations are left out when constructing the environment, so that
+	// invalid rotations don't panic. They are validated separately afterwards.
+	raidProto := googleProto.Clone(request.Raid).(*proto.Raid)
+	for _, partyProto := range raidProto.Parties {
+		for _, playerProto := range partyProto.Players {
+			if playerProto != nil {
+				playerProto.Rotation = nil
+			}
+		}
+	}
+
+	encounterProto := request.Encounter
+	if encounterProto == nil {
+		encounterProto = &proto.Encounter{}
+	}
+	env, _ := NewEnvironment(raidProto, encounterProto)
+
+	result := &proto.ValidateAPLResult{}
+	for partyIdx, party := range env.Raid.Parties {
+		if partyIdx >= len(request.Raid.Parties) {
+			break
+		}
+		partyResult := &proto.PartyAPLValidations{}
+		partyProto := request.Raid.Parties[partyIdx]
+		for playerIdx, player := range party.Players {
+			if playerIdx >= len(partyProto.Players) {
+				// This happens for target dummies.
+				continue
+			}
+			character := player.GetCharacter()
+			partyResult.Players = append(partyResult.Players, &proto.PlayerAPLValidations{
+				Validations: character.validateAPLRotation(partyProto.Players[playerIdx].Rotation),
+			})
+		}
+		result.Parties = append(result.Parties, partyResult)
+	}
+	return result
+}
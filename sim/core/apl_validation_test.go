@@ -0,0 +1,80 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+func TestValidateAPLRotation(t *testing.T) {
+	rotation, err := APLRotationFromText(`
+actions+=/wait,duration=1s,if=1>2
+actions+=/cast_spell,id=12345
+actions+=/wait,duration=1s
+actions+=/wait,duration=1s,if="str"=5
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse rotation: %s", err)
+	}
+
+	unit := &Unit{}
+	validations := unit.validateAPLRotation(rotation)
+
+	type expectedValidation struct {
+		validationType proto.APLValidation_ValidationType
+		location       string
+	}
+	expected := []expectedValidation{
+		{proto.APLValidation_ValidationTypeWarning, "actions[1]"}, // Unknown spell.
+		{proto.APLValidation_ValidationTypeError, "actions[3]"},   // String comparison.
+		{proto.APLValidation_ValidationTypeWarning, "actions[0]"}, // Always false.
+		{proto.APLValidation_ValidationTypeWarning, "actions[3]"}, // Unreachable.
+	}
+	if len(validations) != len(expected) {
+		t.Fatalf("Expected %d validations, got %v", len(expected), validations)
+	}
+	for i, validation := range validations {
+		if validation.ValidationType != expected[i].validationType || validation.Location != expected[i].location {
+			t.Fatalf("Unexpected validation %d: %v", i, validation)
+		}
+	}
+	if unit.Rotation != nil {
+		t.Fatalf("Validation should not change the unit's rotation")
+	}
+}
+
+func TestValidateAPL(t *testing.T) {
+	// Blast has a cooldown, so it doesn't hide what comes after it, but the
+	// called list always has a shock to cast.
+	rotation := `actions+=/cast_spell,id=6
+actions+=/call_action_list,name=filler
+actions+=/cast_spell,id=1
+actions+=/cast_spell,id=48441
+actions.filler+=/cast_spell,id=1,if=remaining_time>10s
+actions.filler+=/cast_spell,id=2
+actions.filler+=/cast_spell,id=4
+`
+	result := ValidateAPL(&proto.ValidateAPLRequest{
+		Raid: SinglePlayerRaidProto(newTestCaster("Caster", rotation), nil, nil, nil),
+	})
+
+	type expectedValidation struct {
+		validationType proto.APLValidation_ValidationType
+		location       string
+	}
+	expected := []expectedValidation{
+		{proto.APLValidation_ValidationTypeWarning, "actions.filler[2]"}, // Unreachable after the shock.
+		{proto.APLValidation_ValidationTypeWarning, "actions[3]"},        // Unknown spell.
+		{proto.APLValidation_ValidationTypeWarning, "actions[2]"},        // Unreachable after the called list.
+		{proto.APLValidation_ValidationTypeWarning, "actions[3]"},        // Unreachable.
+	}
+	validations := result.Parties[0].Players[0].Validations
+	if len(validations) != len(expected) {
+		t.Fatalf("Expected %d validations, got %v", len(expected), validations)
+	}
+	for i, validation := range validations {
+		if validation.ValidationType != expected[i].validationType || validation.Location != expected[i].location {
+			t.Fatalf("Unexpected validation %d: %v", i, validation)
+		}
+	}
+}
@@ -56,10 +56,80 @@ func (unit *Unit) newAPLValue(config *proto.APLValue) APLValue {
 		return unit.newValueNot(config.GetNot())
 	case *proto.APLValue_Cmp:
 		return unit.newValueCompare(config.GetCmp())
+	case *proto.APLValue_Math:
+		return unit.newValueMath(config.GetMath())
+	case *proto.APLValue_Max:
+		return unit.newValueMax(config.GetMax())
+	case *proto.APLValue_Min:
+		return unit.newValueMin(config.GetMin())
+	case *proto.APLValue_Abs:
+		return unit.newValueAbs(config.GetAbs())
+
+	// Variables
+	case *proto.APLValue_Variable:
+		return unit.newValueVariable(config.GetVariable())
+
+	// Encounter
+	case *proto.APLValue_CurrentTime:
+		return unit.newValueCurrentTime(config.GetCurrentTime())
+	case *proto.APLValue_RemainingTime:
+		return unit.newValueRemainingTime(config.GetRemainingTime())
+	case *proto.APLValue_TargetHealthPercent:
+		return unit.newValueTargetHealthPercent(config.GetTargetHealthPercent())
+	case *proto.APLValue_CurrentPhase:
+		return unit.newValueCurrentPhase(config.GetCurrentPhase())
+	case *proto.APLValue_TargetIsTargetable:
+		return unit.newValueTargetIsTargetable(config.GetTargetIsTargetable())
+	case *proto.APLValue_IsMoving:
+		return unit.newValueIsMoving(config.GetIsMoving())
+	case *proto.APLValue_TimeUntilMove:
+		return unit.newValueTimeUntilMove(config.GetTimeUntilMove())
+
+	// Resources
+	case *proto.APLValue_CurrentMana:
+		return unit.newValueCurrentMana(config.GetCurrentMana())
+	case *proto.APLValue_CurrentManaPercent:
+		return unit.newValueCurrentManaPercent(config.GetCurrentManaPercent())
+	case *proto.APLValue_CurrentRage:
+		return unit.newValueCurrentRage(config.GetCurrentRage())
+	case *proto.APLValue_CurrentEnergy:
+		return unit.newValueCurrentEnergy(config.GetCurrentEnergy())
+	case *proto.APLValue_CurrentFocus:
+		return unit.newValueCurrentFocus(config.GetCurrentFocus())
+	case *proto.APLValue_CurrentRunicPower:
+		return unit.newValueCurrentRunicPower(config.GetCurrentRunicPower())
+	case *proto.APLValue_CurrentComboPoints:
+		return unit.newValueCurrentComboPoints(config.GetCurrentComboPoints())
+	case *proto.APLValue_CurrentRuneCount:
+		return unit.newValueCurrentRuneCount(config.GetCurrentRuneCount())
+
+	// GCD
+	case *proto.APLValue_GcdIsReady:
+		return unit.newValueGCDIsReady(config.GetGcdIsReady())
+	case *proto.APLValue_GcdTimeToReady:
+		return unit.newValueGCDTimeToReady(config.GetGcdTimeToReady())
+
+	// Spells
+	case *proto.APLValue_SpellIsReady:
+		return unit.newValueSpellIsReady(config.GetSpellIsReady())
+	case *proto.APLValue_SpellTimeToReady:
+		return unit.newValueSpellTimeToReady(config.GetSpellTimeToReady())
+	case *proto.APLValue_SpellCastTime:
+		return unit.newValueSpellCastTime(config.GetSpellCastTime())
+
+	// Auras
+	case *proto.APLValue_AuraIsActive:
+		return unit.newValueAuraIsActive(config.GetAuraIsActive())
+	case *proto.APLValue_AuraRemainingTime:
+		return unit.newValueAuraRemainingTime(config.GetAuraRemainingTime())
+	case *proto.APLValue_AuraNumStacks:
+		return unit.newValueAuraNumStacks(config.GetAuraNumStacks())
 
 	// Dots
 	case *proto.APLValue_DotIsActive:
 		return unit.newValueDotIsActive(config.GetDotIsActive())
+	case *proto.APLValue_DotRemainingTime:
+		return unit.newValueDotRemainingTime(config.GetDotRemainingTime())
 
 	default:
 		validationError("Unimplemented value type")
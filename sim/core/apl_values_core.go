@@ -1,11 +1,47 @@
 package core
 
 import (
+	"time"
+
 	"github.com/wowsims/wotlk/sim/core/proto"
 )
 
+func (unit *Unit) aplGetSpell(spellId *proto.ActionID) *Spell {
+	if spellId == nil || ProtoToActionID(spellId).IsEmptyAction() {
+		validationError("Missing spell ID")
+	}
+	spell := unit.aplLookupSpell(ProtoToActionID(spellId))
+	if spell == nil {
+		validationWarning("No spell found for id: %s", ProtoToActionID(spellId).String())
+	}
+	return spell
+}
+
+// Spells often register inner spells with the same ID for their hits or dot
+// ticks, so prefer the spell which owns the dot, and then a spell which is
+// actually cast by the player (one with a GCD, cooldown or cost), over the
+// first match.
+func (unit *Unit) aplLookupSpell(actionID ActionID) *Spell {
+	var found *Spell
+	foundCastable := false
+	for _, spell := range unit.Spellbook {
+		if !spell.ActionID.SameAction(actionID) {
+			continue
+		}
+		if len(spell.dots) > 0 || spell.aoeDot != nil {
+			return spell
+		}
+		castable := spell.DefaultCast.GCD > 0 || spell.CD.Timer != nil || spell.Cost != nil
+		if found == nil || (castable && !foundCastable) {
+			found = spell
+			foundCastable = castable
+		}
+	}
+	return found
+}
+
 func (unit *Unit) aplGetDot(spellId *proto.ActionID) *Dot {
-	spell := unit.GetSpell(ProtoToActionID(spellId))
+	spell := unit.aplLookupSpell(ProtoToActionID(spellId))
 	if spell == nil {
 		return nil
 	}
@@ -17,6 +53,509 @@ func (unit *Unit) aplGetDot(spellId *proto.ActionID) *Dot {
 	}
 }
 
+// Looks up an aura on either the unit itself or its current target. Target
+// auras are resolved for every target up front, so that the lookup keeps
+// working if the unit changes targets mid-fight.
+type aplAuraRef struct {
+	unit        *Unit
+	aura        *Aura
+	targetAuras []*Aura
+}
+
+func (unit *Unit) aplGetAura(auraId *proto.ActionID, onTarget bool) *aplAuraRef {
+	actionID := ProtoToActionID(auraId)
+	ref := &aplAuraRef{unit: unit}
+
+	if !onTarget {
+		ref.aura = unit.GetAuraByID(actionID)
+		if ref.aura == nil {
+			validationWarning("No aura found on %s for id: %s", unit.Label, actionID)
+		}
+		return ref
+	}
+
+	ref.targetAuras = MapSlice(unit.Env.Encounter.TargetUnits, func(target *Unit) *Aura {
+		return target.GetAuraByID(actionID)
+	})
+	if len(FilterSlice(ref.targetAuras, func(aura *Aura) bool { return aura != nil })) == 0 {
+		validationWarning("No aura found on any target for id: %s", actionID)
+	}
+	return ref
+}
+
+func (ref *aplAuraRef) Get() *Aura {
+	if ref.targetAuras == nil {
+		return ref.aura
+	}
+	return ref.targetAuras[ref.unit.CurrentTarget.Index]
+}
+
+// Encounter values
+
+type APLValueCurrentTime struct {
+	defaultAPLValueImpl
+}
+
+func (unit *Unit) newValueCurrentTime(config *proto.APLValueCurrentTime) APLValue {
+	return &APLValueCurrentTime{}
+}
+func (value *APLValueCurrentTime) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeDuration
+}
+func (value *APLValueCurrentTime) GetDuration(sim *Simulation) time.Duration {
+	return sim.CurrentTime
+}
+
+type APLValueRemainingTime struct {
+	defaultAPLValueImpl
+}
+
+func (unit *Unit) newValueRemainingTime(config *proto.APLValueRemainingTime) APLValue {
+	return &APLValueRemainingTime{}
+}
+func (value *APLValueRemainingTime) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeDuration
+}
+func (value *APLValueRemainingTime) GetDuration(sim *Simulation) time.Duration {
+	return MaxDuration(0, sim.GetRemainingDuration())
+}
+
+type APLValueTargetHealthPercent struct {
+	defaultAPLValueImpl
+	unit *Unit
+}
+
+func (unit *Unit) newValueTargetHealthPercent(config *proto.APLValueTargetHealthPercent) APLValue {
+	return &APLValueTargetHealthPercent{
+		unit: unit,
+	}
+}
+func (value *APLValueTargetHealthPercent) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeFloat
+}
+func (value *APLValueTargetHealthPercent) GetFloat(sim *Simulation) float64 {
+	target := value.unit.CurrentTarget
+	if target == nil || target.Type != EnemyUnit {
+		return sim.GetRemainingDurationPercent()
+	}
+	return sim.Encounter.Targets[target.Index].RemainingHealthPercent(sim)
+}
+
+type APLValueCurrentPhase struct {
+	defaultAPLValueImpl
+}
+
+func (unit *Unit) newValueCurrentPhase(config *proto.APLValueCurrentPhase) APLValue {
+	return &APLValueCurrentPhase{}
+}
+func (value *APLValueCurrentPhase) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeInt
+}
+func (value *APLValueCurrentPhase) GetInt(sim *Simulation) int32 {
+	return int32(sim.CurrentPhase())
+}
+
+type APLValueTargetIsTargetable struct {
+	defaultAPLValueImpl
+	unit *Unit
+}
+
+func (unit *Unit) newValueTargetIsTargetable(config *proto.APLValueTargetIsTargetable) APLValue {
+	return &APLValueTargetIsTargetable{
+		unit: unit,
+	}
+}
+func (value *APLValueTargetIsTargetable) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeBool
+}
+func (value *APLValueTargetIsTargetable) GetBool(sim *Simulation) bool {
+	return value.unit.CurrentTarget.IsTargetable()
+}
+
+type APLValueIsMoving struct {
+	defaultAPLValueImpl
+	unit *Unit
+}
+
+func (unit *Unit) newValueIsMoving(config *proto.APLValueIsMoving) APLValue {
+	return &APLValueIsMoving{
+		unit: unit,
+	}
+}
+func (value *APLValueIsMoving) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeBool
+}
+func (value *APLValueIsMoving) GetBool(sim *Simulation) bool {
+	return value.unit.IsMoving()
+}
+
+type APLValueTimeUntilMove struct {
+	defaultAPLValueImpl
+	unit *Unit
+}
+
+func (unit *Unit) newValueTimeUntilMove(config *proto.APLValueTimeUntilMove) APLValue {
+	return &APLValueTimeUntilMove{
+		unit: unit,
+	}
+}
+func (value *APLValueTimeUntilMove) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeDuration
+}
+func (value *APLValueTimeUntilMove) GetDuration(sim *Simulation) time.Duration {
+	return value.unit.TimeUntilMove(sim)
+}
+
+// Resource values
+
+type APLValueCurrentMana struct {
+	defaultAPLValueImpl
+	unit *Unit
+}
+
+func (unit *Unit) newValueCurrentMana(config *proto.APLValueCurrentMana) APLValue {
+	if !unit.HasManaBar() {
+		validationWarning("%s does not use Mana", unit.Label)
+		return nil
+	}
+	return &APLValueCurrentMana{
+		unit: unit,
+	}
+}
+func (value *APLValueCurrentMana) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeFloat
+}
+func (value *APLValueCurrentMana) GetFloat(sim *Simulation) float64 {
+	return value.unit.CurrentMana()
+}
+
+type APLValueCurrentManaPercent struct {
+	defaultAPLValueImpl
+	unit *Unit
+}
+
+func (unit *Unit) newValueCurrentManaPercent(config *proto.APLValueCurrentManaPercent) APLValue {
+	if !unit.HasManaBar() {
+		validationWarning("%s does not use Mana", unit.Label)
+		return nil
+	}
+	return &APLValueCurrentManaPercent{
+		unit: unit,
+	}
+}
+func (value *APLValueCurrentManaPercent) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeFloat
+}
+func (value *APLValueCurrentManaPercent) GetFloat(sim *Simulation) float64 {
+	return value.unit.CurrentManaPercent()
+}
+
+type APLValueCurrentRage struct {
+	defaultAPLValueImpl
+	unit *Unit
+}
+
+func (unit *Unit) newValueCurrentRage(config *proto.APLValueCurrentRage) APLValue {
+	if !unit.HasRageBar() {
+		validationWarning("%s does not use Rage", unit.Label)
+		return nil
+	}
+	return &APLValueCurrentRage{
+		unit: unit,
+	}
+}
+func (value *APLValueCurrentRage) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeFloat
+}
+func (value *APLValueCurrentRage) GetFloat(sim *Simulation) float64 {
+	return value.unit.CurrentRage()
+}
+
+type APLValueCurrentEnergy struct {
+	defaultAPLValueImpl
+	unit *Unit
+}
+
+func (unit *Unit) newValueCurrentEnergy(config *proto.APLValueCurrentEnergy) APLValue {
+	if !unit.HasEnergyBar() {
+		validationWarning("%s does not use Energy", unit.Label)
+		return nil
+	}
+	return &APLValueCurrentEnergy{
+		unit: unit,
+	}
+}
+func (value *APLValueCurrentEnergy) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeFloat
+}
+func (value *APLValueCurrentEnergy) GetFloat(sim *Simulation) float64 {
+	return value.unit.CurrentEnergy()
+}
+
+type APLValueCurrentFocus struct {
+	defaultAPLValueImpl
+	unit *Unit
+}
+
+func (unit *Unit) newValueCurrentFocus(config *proto.APLValueCurrentFocus) APLValue {
+	if !unit.HasFocusBar() {
+		validationWarning("%s does not use Focus", unit.Label)
+		return nil
+	}
+	return &APLValueCurrentFocus{
+		unit: unit,
+	}
+}
+func (value *APLValueCurrentFocus) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeFloat
+}
+func (value *APLValueCurrentFocus) GetFloat(sim *Simulation) float64 {
+	return value.unit.CurrentFocus()
+}
+
+type APLValueCurrentRunicPower struct {
+	defaultAPLValueImpl
+	unit *Unit
+}
+
+func (unit *Unit) newValueCurrentRunicPower(config *proto.APLValueCurrentRunicPower) APLValue {
+	if !unit.HasRunicPowerBar() {
+		validationWarning("%s does not use Runic Power", unit.Label)
+		return nil
+	}
+	return &APLValueCurrentRunicPower{
+		unit: unit,
+	}
+}
+func (value *APLValueCurrentRunicPower) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeFloat
+}
+func (value *APLValueCurrentRunicPower) GetFloat(sim *Simulation) float64 {
+	return value.unit.CurrentRunicPower()
+}
+
+type APLValueCurrentComboPoints struct {
+	defaultAPLValueImpl
+	unit *Unit
+}
+
+func (unit *Unit) newValueCurrentComboPoints(config *proto.APLValueCurrentComboPoints) APLValue {
+	if !unit.HasEnergyBar() {
+		validationWarning("%s does not use Combo Points", unit.Label)
+		return nil
+	}
+	return &APLValueCurrentComboPoints{
+		unit: unit,
+	}
+}
+func (value *APLValueCurrentComboPoints) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeInt
+}
+func (value *APLValueCurrentComboPoints) GetInt(sim *Simulation) int32 {
+	return value.unit.ComboPoints()
+}
+
+type APLValueCurrentRuneCount struct {
+	defaultAPLValueImpl
+	unit     *Unit
+	runeType proto.APLValueCurrentRuneCount_RuneType
+}
+
+func (unit *Unit) newValueCurrentRuneCount(config *proto.APLValueCurrentRuneCount) APLValue {
+	if !unit.HasRunicPowerBar() {
+		validationWarning("%s does not use Runes", unit.Label)
+		return nil
+	}
+	if config.RuneType == proto.APLValueCurrentRuneCount_RuneUnknown {
+		validationError("Rune count value requires a rune type")
+	}
+	return &APLValueCurrentRuneCount{
+		unit:     unit,
+		runeType: config.RuneType,
+	}
+}
+func (value *APLValueCurrentRuneCount) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeInt
+}
+func (value *APLValueCurrentRuneCount) GetInt(sim *Simulation) int32 {
+	switch value.runeType {
+	case proto.APLValueCurrentRuneCount_RuneBlood:
+		return int32(value.unit.CurrentBloodRunes())
+	case proto.APLValueCurrentRuneCount_RuneFrost:
+		return int32(value.unit.CurrentFrostRunes())
+	case proto.APLValueCurrentRuneCount_RuneUnholy:
+		return int32(value.unit.CurrentUnholyRunes())
+	case proto.APLValueCurrentRuneCount_RuneDeath:
+		return int32(value.unit.CurrentDeathRunes())
+	}
+	return 0
+}
+
+// GCD values
+
+type APLValueGCDIsReady struct {
+	defaultAPLValueImpl
+	unit *Unit
+}
+
+func (unit *Unit) newValueGCDIsReady(config *proto.APLValueGCDIsReady) APLValue {
+	return &APLValueGCDIsReady{
+		unit: unit,
+	}
+}
+func (value *APLValueGCDIsReady) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeBool
+}
+func (value *APLValueGCDIsReady) GetBool(sim *Simulation) bool {
+	return value.unit.GCD.IsReady(sim)
+}
+
+type APLValueGCDTimeToReady struct {
+	defaultAPLValueImpl
+	unit *Unit
+}
+
+func (unit *Unit) newValueGCDTimeToReady(config *proto.APLValueGCDTimeToReady) APLValue {
+	return &APLValueGCDTimeToReady{
+		unit: unit,
+	}
+}
+func (value *APLValueGCDTimeToReady) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeDuration
+}
+func (value *APLValueGCDTimeToReady) GetDuration(sim *Simulation) time.Duration {
+	return value.unit.GCD.TimeToReady(sim)
+}
+
+// Spell values
+
+type APLValueSpellIsReady struct {
+	defaultAPLValueImpl
+	spell *Spell
+}
+
+func (unit *Unit) newValueSpellIsReady(config *proto.APLValueSpellIsReady) APLValue {
+	spell := unit.aplGetSpell(config.SpellId)
+	if spell == nil {
+		return nil
+	}
+	return &APLValueSpellIsReady{
+		spell: spell,
+	}
+}
+func (value *APLValueSpellIsReady) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeBool
+}
+func (value *APLValueSpellIsReady) GetBool(sim *Simulation) bool {
+	return value.spell.IsReady(sim)
+}
+
+type APLValueSpellTimeToReady struct {
+	defaultAPLValueImpl
+	spell *Spell
+}
+
+func (unit *Unit) newValueSpellTimeToReady(config *proto.APLValueSpellTimeToReady) APLValue {
+	spell := unit.aplGetSpell(config.SpellId)
+	if spell == nil {
+		return nil
+	}
+	return &APLValueSpellTimeToReady{
+		spell: spell,
+	}
+}
+func (value *APLValueSpellTimeToReady) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeDuration
+}
+func (value *APLValueSpellTimeToReady) GetDuration(sim *Simulation) time.Duration {
+	return value.spell.TimeToReady(sim)
+}
+
+type APLValueSpellCastTime struct {
+	defaultAPLValueImpl
+	spell *Spell
+}
+
+func (unit *Unit) newValueSpellCastTime(config *proto.APLValueSpellCastTime) APLValue {
+	spell := unit.aplGetSpell(config.SpellId)
+	if spell == nil {
+		return nil
+	}
+	return &APLValueSpellCastTime{
+		spell: spell,
+	}
+}
+func (value *APLValueSpellCastTime) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeDuration
+}
+func (value *APLValueSpellCastTime) GetDuration(sim *Simulation) time.Duration {
+	return value.spell.CastTime()
+}
+
+// Aura values
+
+type APLValueAuraIsActive struct {
+	defaultAPLValueImpl
+	aura *aplAuraRef
+}
+
+func (unit *Unit) newValueAuraIsActive(config *proto.APLValueAuraIsActive) APLValue {
+	return &APLValueAuraIsActive{
+		aura: unit.aplGetAura(config.AuraId, config.OnTarget),
+	}
+}
+func (value *APLValueAuraIsActive) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeBool
+}
+func (value *APLValueAuraIsActive) GetBool(sim *Simulation) bool {
+	aura := value.aura.Get()
+	return aura != nil && aura.IsActive()
+}
+
+type APLValueAuraRemainingTime struct {
+	defaultAPLValueImpl
+	aura *aplAuraRef
+}
+
+func (unit *Unit) newValueAuraRemainingTime(config *proto.APLValueAuraRemainingTime) APLValue {
+	return &APLValueAuraRemainingTime{
+		aura: unit.aplGetAura(config.AuraId, config.OnTarget),
+	}
+}
+func (value *APLValueAuraRemainingTime) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeDuration
+}
+func (value *APLValueAuraRemainingTime) GetDuration(sim *Simulation) time.Duration {
+	aura := value.aura.Get()
+	if aura == nil || !aura.IsActive() {
+		return 0
+	}
+	return aura.RemainingDuration(sim)
+}
+
+type APLValueAuraNumStacks struct {
+	defaultAPLValueImpl
+	aura *aplAuraRef
+}
+
+func (unit *Unit) newValueAuraNumStacks(config *proto.APLValueAuraNumStacks) APLValue {
+	return &APLValueAuraNumStacks{
+		aura: unit.aplGetAura(config.AuraId, config.OnTarget),
+	}
+}
+func (value *APLValueAuraNumStacks) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeInt
+}
+func (value *APLValueAuraNumStacks) GetInt(sim *Simulation) int32 {
+	aura := value.aura.Get()
+	if aura == nil || !aura.IsActive() {
+		return 0
+	}
+	return aura.GetStacks()
+}
+
+// Dot values
+
 type APLValueDotIsActive struct {
 	defaultAPLValueImpl
 	dot *Dot
@@ -33,3 +572,23 @@ func (value *APLValueDotIsActive) Type() proto.APLValueType {
 func (value *APLValueDotIsActive) GetBool(sim *Simulation) bool {
 	return value.dot.IsActive()
 }
+
+type APLValueDotRemainingTime struct {
+	defaultAPLValueImpl
+	dot *Dot
+}
+
+func (unit *Unit) newValueDotRemainingTime(config *proto.APLValueDotRemainingTime) APLValue {
+	return &APLValueDotRemainingTime{
+		dot: unit.aplGetDot(config.SpellId),
+	}
+}
+func (value *APLValueDotRemainingTime) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeDuration
+}
+func (value *APLValueDotRemainingTime) GetDuration(sim *Simulation) time.Duration {
+	if !value.dot.IsActive() {
+		return 0
+	}
+	return value.dot.RemainingDuration(sim)
+}
@@ -2,7 +2,9 @@ package core
 
 import (
 	"fmt"
+	"math"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/wowsims/wotlk/sim/core/proto"
@@ -25,12 +27,6 @@ func (unit *Unit) newValueConst(config *proto.APLValueConst) APLValue {
 		boolVal:   config.Val != "",
 	}
 
-	if durVal, err := time.ParseDuration(config.Val); err == nil {
-		result.durationVal = durVal
-		result.valType = proto.APLValueType_ValueTypeDuration
-		return result
-	}
-
 	if intVal, err := strconv.Atoi(config.Val); err == nil {
 		result.intVal = int32(intVal)
 		result.floatVal = float64(result.intVal)
@@ -45,6 +41,23 @@ func (unit *Unit) newValueConst(config *proto.APLValueConst) APLValue {
 		result.valType = proto.APLValueType_ValueTypeFloat
 		return result
 	}
+
+	// Checked after numbers, so that "0" is treated as an int.
+	if durVal, err := time.ParseDuration(config.Val); err == nil {
+		result.durationVal = durVal
+		result.valType = proto.APLValueType_ValueTypeDuration
+		return result
+	}
+
+	// Percentages are converted to 0-1 floats, to match values like mana percent.
+	if strings.HasSuffix(config.Val, "%") {
+		if percentVal, err := strconv.ParseFloat(strings.TrimSuffix(config.Val, "%"), 64); err == nil {
+			result.floatVal = percentVal / 100
+			result.durationVal = DurationFromSeconds(result.floatVal)
+			result.valType = proto.APLValueType_ValueTypeFloat
+			return result
+		}
+	}
 	return result
 }
 func (value *APLValueConst) Type() proto.APLValueType {
@@ -190,13 +203,30 @@ var aplValueTypeOrder = []proto.APLValueType{
 
 // Coerces 2 values into the same type, returning the two new values.
 func (unit *Unit) coerceToSameType(value1 APLValue, value2 APLValue) (APLValue, APLValue) {
+	vals := unit.coerceAllToSameType([]APLValue{value1, value2})
+	return vals[0], vals[1]
+}
+
+// Coerces any number of values into the same type, returning the new values.
+func (unit *Unit) coerceAllToSameType(vals []APLValue) []APLValue {
 	var coercionType proto.APLValueType
 	for _, listType := range aplValueTypeOrder {
-		if value1.Type() == listType || value2.Type() == listType {
-			coercionType = listType
+		for _, val := range vals {
+			if val.Type() == listType {
+				coercionType = listType
+			}
 		}
 	}
-	return unit.coerceTo(value1, coercionType), unit.coerceTo(value2, coercionType)
+	return MapSlice(vals, func(val APLValue) APLValue {
+		return unit.coerceTo(val, coercionType)
+	})
+}
+
+// Whether values of this type can be used in arithmetic.
+func isNumericAPLValueType(valType proto.APLValueType) bool {
+	return valType == proto.APLValueType_ValueTypeInt ||
+		valType == proto.APLValueType_ValueTypeFloat ||
+		valType == proto.APLValueType_ValueTypeDuration
 }
 
 type APLValueCompare struct {
@@ -207,7 +237,14 @@ type APLValueCompare struct {
 }
 
 func (unit *Unit) newValueCompare(config *proto.APLValueCompare) APLValue {
-	lhs, rhs := unit.coerceToSameType(unit.newAPLValue(config.Lhs), unit.newAPLValue(config.Rhs))
+	lhs, rhs := unit.newAPLValue(config.Lhs), unit.newAPLValue(config.Rhs)
+	if lhs == nil || rhs == nil {
+		validationError("Comparisons require both a left and right value")
+	}
+	if (lhs.Type() == proto.APLValueType_ValueTypeString) != (rhs.Type() == proto.APLValueType_ValueTypeString) {
+		validationError("Cannot compare %s with %s", lhs.Type(), rhs.Type())
+	}
+	lhs, rhs = unit.coerceToSameType(lhs, rhs)
 	if lhs.Type() == proto.APLValueType_ValueTypeBool && !(config.Op == proto.APLValueCompare_OpEq || config.Op == proto.APLValueCompare_OpNe) {
 		validationError("Bool types only allow Equals and NotEquals comparisons!")
 	}
@@ -371,3 +408,256 @@ func (value *APLValueNot) Type() proto.APLValueType {
 func (value *APLValueNot) GetBool(sim *Simulation) bool {
 	return !value.val.GetBool(sim)
 }
+
+type APLValueMath struct {
+	defaultAPLValueImpl
+	op      proto.APLValueMath_MathOperator
+	lhs     APLValue
+	rhs     APLValue
+	valType proto.APLValueType
+}
+
+// Durations may be added to or subtracted from other durations, and scaled by
+// numbers. Dividing two durations gives their ratio as a float. Dividing two
+// ints gives a float, to avoid surprising truncation.
+func (unit *Unit) newValueMath(config *proto.APLValueMath) APLValue {
+	lhs, rhs := unit.newAPLValue(config.Lhs), unit.newAPLValue(config.Rhs)
+	if lhs == nil || rhs == nil {
+		validationError("Math operations require both a lhs and rhs value!")
+		return nil
+	}
+	if !isNumericAPLValueType(lhs.Type()) || !isNumericAPLValueType(rhs.Type()) {
+		validationError("Math operations only allow int, float and duration values, got %s and %s!", lhs.Type(), rhs.Type())
+		return nil
+	}
+
+	lhsIsDuration := lhs.Type() == proto.APLValueType_ValueTypeDuration
+	rhsIsDuration := rhs.Type() == proto.APLValueType_ValueTypeDuration
+
+	var valType proto.APLValueType
+	switch config.Op {
+	case proto.APLValueMath_OpAdd, proto.APLValueMath_OpSub:
+		lhs, rhs = unit.coerceToSameType(lhs, rhs)
+		valType = lhs.Type()
+	case proto.APLValueMath_OpMul:
+		if lhsIsDuration && rhsIsDuration {
+			validationError("Cannot multiply two durations!")
+			return nil
+		} else if lhsIsDuration || rhsIsDuration {
+			// Keep the duration on the left, so evaluation only needs to handle one order.
+			if rhsIsDuration {
+				lhs, rhs = rhs, lhs
+			}
+			rhs = unit.coerceTo(rhs, proto.APLValueType_ValueTypeFloat)
+			valType = proto.APLValueType_ValueTypeDuration
+		} else {
+			lhs, rhs = unit.coerceToSameType(lhs, rhs)
+			valType = lhs.Type()
+		}
+	case proto.APLValueMath_OpDiv:
+		if lhsIsDuration && rhsIsDuration {
+			valType = proto.APLValueType_ValueTypeFloat
+		} else if lhsIsDuration {
+			rhs = unit.coerceTo(rhs, proto.APLValueType_ValueTypeFloat)
+			valType = proto.APLValueType_ValueTypeDuration
+		} else if rhsIsDuration {
+			validationError("Cannot divide a number by a duration!")
+			return nil
+		} else {
+			lhs = unit.coerceTo(lhs, proto.APLValueType_ValueTypeFloat)
+			rhs = unit.coerceTo(rhs, proto.APLValueType_ValueTypeFloat)
+			valType = proto.APLValueType_ValueTypeFloat
+		}
+	default:
+		validationError("Unknown math operator: %s", config.Op)
+		return nil
+	}
+
+	return &APLValueMath{
+		op:      config.Op,
+		lhs:     lhs,
+		rhs:     rhs,
+		valType: valType,
+	}
+}
+func (value *APLValueMath) Type() proto.APLValueType {
+	return value.valType
+}
+func (value *APLValueMath) GetInt(sim *Simulation) int32 {
+	left, right := value.lhs.GetInt(sim), value.rhs.GetInt(sim)
+	switch value.op {
+	case proto.APLValueMath_OpAdd:
+		return left + right
+	case proto.APLValueMath_OpSub:
+		return left - right
+	case proto.APLValueMath_OpMul:
+		return left * right
+	}
+	return 0
+}
+func (value *APLValueMath) GetFloat(sim *Simulation) float64 {
+	if value.lhs.Type() == proto.APLValueType_ValueTypeDuration {
+		// Only possible for duration / duration.
+		left, right := value.lhs.GetDuration(sim), value.rhs.GetDuration(sim)
+		if right == 0 {
+			return 0
+		}
+		return float64(left) / float64(right)
+	}
+
+	left, right := value.lhs.GetFloat(sim), value.rhs.GetFloat(sim)
+	switch value.op {
+	case proto.APLValueMath_OpAdd:
+		return left + right
+	case proto.APLValueMath_OpSub:
+		return left - right
+	case proto.APLValueMath_OpMul:
+		return left * right
+	case proto.APLValueMath_OpDiv:
+		if right == 0 {
+			return 0
+		}
+		return left / right
+	}
+	return 0
+}
+func (value *APLValueMath) GetDuration(sim *Simulation) time.Duration {
+	switch value.op {
+	case proto.APLValueMath_OpAdd:
+		return value.lhs.GetDuration(sim) + value.rhs.GetDuration(sim)
+	case proto.APLValueMath_OpSub:
+		return value.lhs.GetDuration(sim) - value.rhs.GetDuration(sim)
+	case proto.APLValueMath_OpMul:
+		return time.Duration(float64(value.lhs.GetDuration(sim)) * value.rhs.GetFloat(sim))
+	case proto.APLValueMath_OpDiv:
+		right := value.rhs.GetFloat(sim)
+		if right == 0 {
+			return 0
+		}
+		return time.Duration(float64(value.lhs.GetDuration(sim)) / right)
+	}
+	return 0
+}
+
+func (unit *Unit) newNumericAPLValues(opName string, configs []*proto.APLValue) []APLValue {
+	vals := MapSlice(configs, func(val *proto.APLValue) APLValue {
+		return unit.newAPLValue(val)
+	})
+	vals = FilterSlice(vals, func(val APLValue) bool { return val != nil })
+	for _, val := range vals {
+		if !isNumericAPLValueType(val.Type()) {
+			validationError("%s only allows int, float and duration values, got %s!", opName, val.Type())
+		}
+	}
+	return unit.coerceAllToSameType(vals)
+}
+
+type APLValueMax struct {
+	defaultAPLValueImpl
+	vals []APLValue
+}
+
+func (unit *Unit) newValueMax(config *proto.APLValueMax) APLValue {
+	vals := unit.newNumericAPLValues("Max", config.Vals)
+	if len(vals) == 0 {
+		return nil
+	}
+	return &APLValueMax{
+		vals: vals,
+	}
+}
+func (value *APLValueMax) Type() proto.APLValueType {
+	return value.vals[0].Type()
+}
+func (value *APLValueMax) GetInt(sim *Simulation) int32 {
+	result := value.vals[0].GetInt(sim)
+	for _, val := range value.vals[1:] {
+		result = MaxInt32(result, val.GetInt(sim))
+	}
+	return result
+}
+func (value *APLValueMax) GetFloat(sim *Simulation) float64 {
+	result := value.vals[0].GetFloat(sim)
+	for _, val := range value.vals[1:] {
+		result = MaxFloat(result, val.GetFloat(sim))
+	}
+	return result
+}
+func (value *APLValueMax) GetDuration(sim *Simulation) time.Duration {
+	result := value.vals[0].GetDuration(sim)
+	for _, val := range value.vals[1:] {
+		result = MaxDuration(result, val.GetDuration(sim))
+	}
+	return result
+}
+
+type APLValueMin struct {
+	defaultAPLValueImpl
+	vals []APLValue
+}
+
+func (unit *Unit) newValueMin(config *proto.APLValueMin) APLValue {
+	vals := unit.newNumericAPLValues("Min", config.Vals)
+	if len(vals) == 0 {
+		return nil
+	}
+	return &APLValueMin{
+		vals: vals,
+	}
+}
+func (value *APLValueMin) Type() proto.APLValueType {
+	return value.vals[0].Type()
+}
+func (value *APLValueMin) GetInt(sim *Simulation) int32 {
+	result := value.vals[0].GetInt(sim)
+	for _, val := range value.vals[1:] {
+		result = MinInt32(result, val.GetInt(sim))
+	}
+	return result
+}
+func (value *APLValueMin) GetFloat(sim *Simulation) float64 {
+	result := value.vals[0].GetFloat(sim)
+	for _, val := range value.vals[1:] {
+		result = MinFloat(result, val.GetFloat(sim))
+	}
+	return result
+}
+func (value *APLValueMin) GetDuration(sim *Simulation) time.Duration {
+	result := value.vals[0].GetDuration(sim)
+	for _, val := range value.vals[1:] {
+		result = MinDuration(result, val.GetDuration(sim))
+	}
+	return result
+}
+
+type APLValueAbs struct {
+	defaultAPLValueImpl
+	val APLValue
+}
+
+func (unit *Unit) newValueAbs(config *proto.APLValueAbs) APLValue {
+	val := unit.newAPLValue(config.Val)
+	if val == nil {
+		return nil
+	}
+	if !isNumericAPLValueType(val.Type()) {
+		validationError("Abs only allows int, float and duration values, got %s!", val.Type())
+	}
+	return &APLValueAbs{
+		val: val,
+	}
+}
+func (value *APLValueAbs) Type() proto.APLValueType {
+	return value.val.Type()
+}
+func (value *APLValueAbs) GetInt(sim *Simulation) int32 {
+	val := value.val.GetInt(sim)
+	return TernaryInt32(val < 0, -val, val)
+}
+func (value *APLValueAbs) GetFloat(sim *Simulation) float64 {
+	return math.Abs(value.val.GetFloat(sim))
+}
+func (value *APLValueAbs) GetDuration(sim *Simulation) time.Duration {
+	val := value.val.GetDuration(sim)
+	return TernaryDuration(val < 0, -val, val)
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/wowsims/wotlk/sim/core/proto"
+	"github.com/wowsims/wotlk/sim/core/stats"
 )
 
 func TestValueConst(t *testing.T) {
@@ -31,6 +32,11 @@ func TestValueConst(t *testing.T) {
 		t.Fatalf("Unexpected duration value %s", durVal.GetDuration(sim))
 	}
 
+	percentVal := unit.newValueConst(&proto.APLValueConst{Val: "30%"})
+	if percentVal.Type() != proto.APLValueType_ValueTypeFloat || percentVal.GetFloat(sim) != 0.3 {
+		t.Fatalf("Unexpected percent value %f", percentVal.GetFloat(sim))
+	}
+
 	coercedDurVal := unit.coerceTo(floatVal, proto.APLValueType_ValueTypeDuration)
 	if _, ok := coercedDurVal.(*APLValueConst); !ok {
 		t.Fatalf("Failed to skip coerce wrapper for duration value")
@@ -39,3 +45,139 @@ func TestValueConst(t *testing.T) {
 		t.Fatalf("Unexpected coerced duration value %s", coercedDurVal.GetDuration(sim))
 	}
 }
+
+func TestValueMath(t *testing.T) {
+	sim := &Simulation{}
+	unit := &Unit{}
+
+	constVal := func(val string) *proto.APLValue {
+		return &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: val}}}
+	}
+	mathVal := func(op proto.APLValueMath_MathOperator, lhs string, rhs string) APLValue {
+		return unit.newValueMath(&proto.APLValueMath{Op: op, Lhs: constVal(lhs), Rhs: constVal(rhs)})
+	}
+
+	intSum := mathVal(proto.APLValueMath_OpAdd, "3", "4")
+	if intSum.Type() != proto.APLValueType_ValueTypeInt || intSum.GetInt(sim) != 7 {
+		t.Fatalf("Unexpected int sum %d", intSum.GetInt(sim))
+	}
+
+	intQuotient := mathVal(proto.APLValueMath_OpDiv, "3", "2")
+	if intQuotient.Type() != proto.APLValueType_ValueTypeFloat || intQuotient.GetFloat(sim) != 1.5 {
+		t.Fatalf("Unexpected int quotient %f", intQuotient.GetFloat(sim))
+	}
+
+	zeroQuotient := mathVal(proto.APLValueMath_OpDiv, "3", "0")
+	if zeroQuotient.GetFloat(sim) != 0 {
+		t.Fatalf("Unexpected division by zero result %f", zeroQuotient.GetFloat(sim))
+	}
+
+	scaledDur := mathVal(proto.APLValueMath_OpMul, "2", "1.5s")
+	if scaledDur.Type() != proto.APLValueType_ValueTypeDuration || scaledDur.GetDuration(sim) != time.Second*3 {
+		t.Fatalf("Unexpected scaled duration %s", scaledDur.GetDuration(sim))
+	}
+
+	durRatio := mathVal(proto.APLValueMath_OpDiv, "3s", "2s")
+	if durRatio.Type() != proto.APLValueType_ValueTypeFloat || durRatio.GetFloat(sim) != 1.5 {
+		t.Fatalf("Unexpected duration ratio %f", durRatio.GetFloat(sim))
+	}
+
+	maxVal := unit.newValueMax(&proto.APLValueMax{Vals: []*proto.APLValue{constVal("1s"), constVal("2"), constVal("500ms")}})
+	if maxVal.Type() != proto.APLValueType_ValueTypeDuration || maxVal.GetDuration(sim) != time.Second*2 {
+		t.Fatalf("Unexpected max value %s", maxVal.GetDuration(sim))
+	}
+
+	absVal := unit.newValueAbs(&proto.APLValueAbs{Val: constVal("-2.5")})
+	if absVal.GetFloat(sim) != 2.5 {
+		t.Fatalf("Unexpected abs value %f", absVal.GetFloat(sim))
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Expected validation error when multiplying durations")
+		}
+	}()
+	mathVal(proto.APLValueMath_OpMul, "1s", "2s")
+}
+
+func TestValueEncounterPhase(t *testing.T) {
+	sim := &Simulation{phase: 1}
+	target := &Unit{Type: EnemyUnit}
+	unit := &Unit{CurrentTarget: target}
+
+	currentPhase := unit.newValueCurrentPhase(&proto.APLValueCurrentPhase{})
+	targetIsTargetable := unit.newValueTargetIsTargetable(&proto.APLValueTargetIsTargetable{})
+	if currentPhase.GetInt(sim) != 1 || !targetIsTargetable.GetBool(sim) {
+		t.Fatalf("Expected phase 1 with a targetable target")
+	}
+
+	callbackPhase := 0
+	sim.RegisterPhaseCallback(func(sim *Simulation, phase int) {
+		callbackPhase = phase
+	})
+	sim.startPhase(2)
+	if currentPhase.GetInt(sim) != 2 || callbackPhase != 2 {
+		t.Fatalf("Expected phase 2, got %d with callback for %d", currentPhase.GetInt(sim), callbackPhase)
+	}
+
+	target.untargetable++
+	if targetIsTargetable.GetBool(sim) {
+		t.Fatalf("Expected an untargetable target")
+	}
+}
+
+func TestValueTimeUntilMove(t *testing.T) {
+	sim := &Simulation{Environment: &Environment{}, Duration: time.Minute}
+	unit := &Unit{
+		movementEvents: []*proto.MovementEvent{
+			{StartSeconds: 10, DurationSeconds: 2, PeriodSeconds: 20},
+			{StartSeconds: 45, DurationSeconds: 5},
+		},
+	}
+
+	isMoving := unit.newValueIsMoving(&proto.APLValueIsMoving{})
+	timeUntilMove := unit.newValueTimeUntilMove(&proto.APLValueTimeUntilMove{})
+
+	expectTimeUntilMove := func(currentTime time.Duration, expected time.Duration) {
+		sim.CurrentTime = currentTime
+		if actual := timeUntilMove.GetDuration(sim); actual != expected {
+			t.Fatalf("Expected to move in %s at %s, got %s", expected, currentTime, actual)
+		}
+	}
+	expectTimeUntilMove(0, time.Second*10)
+	expectTimeUntilMove(time.Second*15, time.Second*15)
+	expectTimeUntilMove(time.Second*40, time.Second*5)
+	// Only the periodic event is left, at 50s.
+	expectTimeUntilMove(time.Second*46, time.Second*4)
+	// No more movement before the end of the fight.
+	sim.Duration = time.Second * 55
+	expectTimeUntilMove(time.Second*51, time.Second*4)
+
+	unit.moving++
+	if !isMoving.GetBool(sim) || timeUntilMove.GetDuration(sim) != 0 {
+		t.Fatalf("Expected a moving unit")
+	}
+}
+
+func TestValueTargetHealthPercent(t *testing.T) {
+	target := &Target{Unit: Unit{Type: EnemyUnit}}
+	target.stats[stats.Health] = 1000
+	target.DamageTaken = 250
+	sim := &Simulation{
+		Environment: &Environment{Encounter: Encounter{Targets: []*Target{target}}},
+		Duration:    time.Minute,
+		CurrentTime: time.Second * 30,
+	}
+	unit := &Unit{CurrentTarget: &target.Unit}
+
+	healthPercent := unit.newValueTargetHealthPercent(&proto.APLValueTargetHealthPercent{})
+	if actual := healthPercent.GetFloat(sim); actual != 0.75 {
+		t.Fatalf("Expected 75%% health from damage taken, got %0.3f", actual)
+	}
+
+	// Without a Health stat, health drops with the fight duration.
+	target.stats[stats.Health] = 0
+	if actual := healthPercent.GetFloat(sim); actual != 0.5 {
+		t.Fatalf("Expected 50%% health from the time elapsed, got %0.3f", actual)
+	}
+}
@@ -0,0 +1,111 @@
+package core
+
+import (
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// A user-defined numeric variable, shared by all actions and values in a rotation.
+type aplVariable struct {
+	name         string
+	initialValue float64
+	value        float64
+}
+
+func (apl *APLRotation) getVariable(name string) *aplVariable {
+	variable := apl.variables[name]
+	if variable == nil {
+		validationError("No variable named %s", name)
+	}
+	return variable
+}
+
+func (unit *Unit) aplGetVariable(name string) *aplVariable {
+	if unit.Rotation == nil {
+		validationError("Variables may only be used within a rotation")
+	}
+	return unit.Rotation.getVariable(name)
+}
+
+type APLActionSetVariable struct {
+	defaultAPLActionImpl
+	variable *aplVariable
+	value    APLValue
+}
+
+func (unit *Unit) newActionSetVariable(config *proto.APLActionSetVariable) APLActionImpl {
+	value := unit.coerceTo(unit.newAPLValue(config.Value), proto.APLValueType_ValueTypeFloat)
+	if value == nil {
+		validationError("Set variable action requires a value")
+	}
+	return &APLActionSetVariable{
+		variable: unit.aplGetVariable(config.Name),
+		value:    value,
+	}
+}
+
+// Variable actions are always available once their condition passes. Priority
+// lists pass over them, and apply them when the action chosen after them is
+// performed, see aplChoice.
+func (action *APLActionSetVariable) IsAvailable(sim *Simulation) bool {
+	return true
+}
+func (action *APLActionSetVariable) Execute(sim *Simulation) {
+	action.variable.value = action.value.GetFloat(sim)
+}
+
+type APLActionAddVariable struct {
+	defaultAPLActionImpl
+	variable *aplVariable
+	value    APLValue
+}
+
+func (unit *Unit) newActionAddVariable(config *proto.APLActionAddVariable) APLActionImpl {
+	value := unit.coerceTo(unit.newAPLValue(config.Value), proto.APLValueType_ValueTypeFloat)
+	if value == nil {
+		validationError("Add variable action requires a value")
+	}
+	return &APLActionAddVariable{
+		variable: unit.aplGetVariable(config.Name),
+		value:    value,
+	}
+}
+func (action *APLActionAddVariable) IsAvailable(sim *Simulation) bool {
+	return true
+}
+func (action *APLActionAddVariable) Execute(sim *Simulation) {
+	action.variable.value += action.value.GetFloat(sim)
+}
+
+type APLActionResetVariable struct {
+	defaultAPLActionImpl
+	variable *aplVariable
+}
+
+func (unit *Unit) newActionResetVariable(config *proto.APLActionResetVariable) APLActionImpl {
+	return &APLActionResetVariable{
+		variable: unit.aplGetVariable(config.Name),
+	}
+}
+func (action *APLActionResetVariable) IsAvailable(sim *Simulation) bool {
+	return true
+}
+func (action *APLActionResetVariable) Execute(sim *Simulation) {
+	action.variable.value = action.variable.initialValue
+}
+
+type APLValueVariable struct {
+	defaultAPLValueImpl
+	variable *aplVariable
+}
+
+func (unit *Unit) newValueVariable(config *proto.APLValueVariable) APLValue {
+	return &APLValueVariable{
+		variable: unit.aplGetVariable(config.Name),
+	}
+}
+func (value *APLValueVariable) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeFloat
+}
+func (value *APLValueVariable) GetFloat(sim *Simulation) float64 {
+	return value.variable.value
+}
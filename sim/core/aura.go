@@ -190,6 +190,9 @@ func (aura *Aura) SetStacks(sim *Simulation, newStacks int32) {
 		aura.Unit.Log(sim, "%s stacks: %d --> %d", aura.ActionID, oldStacks, newStacks)
 	}
 	aura.stacks = newStacks
+	if sim.CombatLog != nil && !aura.ActionID.IsEmptyAction() {
+		sim.CombatLog.addAura(sim, aura, CombatLogAuraStacks)
+	}
 	if aura.OnStacksChange != nil {
 		aura.OnStacksChange(aura, sim, oldStacks, newStacks)
 	}
@@ -323,6 +326,14 @@ func (at *auraTracker) GetAura(label string) *Aura {
 	}
 	return nil
 }
+func (at *auraTracker) GetAuraByID(actionID ActionID) *Aura {
+	for _, aura := range at.auras {
+		if aura.ActionID.SameAction(actionID) {
+			return aura
+		}
+	}
+	return nil
+}
 func (at *auraTracker) HasAura(label string) bool {
 	aura := at.GetAura(label)
 	return aura != nil
@@ -506,6 +517,18 @@ func (at *auraTracker) doneIteration(sim *Simulation) {
 	}
 }
 
+// Adds the aura metrics of another auraTracker, for the same unit in a
+// Simulation built from the same request.
+func (at *auraTracker) mergeMetrics(other *auraTracker) {
+	for i, aura := range at.auras {
+		otherAura := other.auras[i]
+		if aura.Label != otherAura.Label {
+			panic(fmt.Sprintf("Mismatched auras %s and %s", aura.Label, otherAura.Label))
+		}
+		aura.metrics.merge(&otherAura.metrics)
+	}
+}
+
 // Adds a new aura to the simulation. If an aura with the same ID already
 // exists it will be replaced with the new one.
 func (aura *Aura) Activate(sim *Simulation) {
@@ -514,6 +537,9 @@ func (aura *Aura) Activate(sim *Simulation) {
 		if sim.Log != nil && !aura.ActionID.IsEmptyAction() {
 			aura.Unit.Log(sim, "Aura refreshed: %s", aura.ActionID)
 		}
+		if sim.CombatLog != nil && !aura.ActionID.IsEmptyAction() {
+			sim.CombatLog.addAura(sim, aura, CombatLogAuraRefresh)
+		}
 		aura.Refresh(sim)
 		return
 	}
@@ -594,6 +620,9 @@ func (aura *Aura) Activate(sim *Simulation) {
 	if sim.Log != nil && !aura.ActionID.IsEmptyAction() {
 		aura.Unit.Log(sim, "Aura gained: %s", aura.ActionID)
 	}
+	if sim.CombatLog != nil && !aura.ActionID.IsEmptyAction() {
+		sim.CombatLog.addAura(sim, aura, CombatLogAuraApplied)
+	}
 
 	if aura.OnGain != nil {
 		aura.OnGain(aura, sim)
@@ -625,11 +654,17 @@ func (aura *Aura) Deactivate(sim *Simulation) {
 		} else {
 			aura.metrics.Uptime += sim.CurrentTime - aura.startTime
 		}
+		if timeline := aura.Unit.Metrics.timeline; timeline != nil {
+			timeline.addAuraUptime(aura.ActionID, aura.startTime, MinDuration(sim.CurrentTime, aura.expires))
+		}
 	}
 
 	if sim.Log != nil && !aura.ActionID.IsEmptyAction() {
 		aura.Unit.Log(sim, "Aura faded: %s", aura.ActionID)
 	}
+	if sim.CombatLog != nil && !aura.ActionID.IsEmptyAction() {
+		sim.CombatLog.addAura(sim, aura, CombatLogAuraRemoved)
+	}
 
 	aura.expires = 0
 	if aura.activeIndex != Inactive {
@@ -35,8 +35,11 @@ type bulkSimRunner struct {
 
 func BulkSim(ctx context.Context, request *proto.BulkSimRequest, progress chan *proto.ProgressMetrics) *proto.BulkSimResult {
 	bulk := &bulkSimRunner{
-		SingleRaidSimRunner: runSim,
-		Request:             request,
+		SingleRaidSimRunner: func(rsr *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, skipPresim bool) *proto.RaidSimResult {
+			// Bulk sims already run their raid sims concurrently.
+			return runSim(rsr, progress, skipPresim, numSimWorkers(rsr.SimOptions, 1))
+		},
+		Request: request,
 	}
 
 	result, err := bulk.Run(ctx, progress)
@@ -236,9 +239,14 @@ func (b *bulkSimRunner) Run(pctx context.Context, progress chan *proto.ProgressM
 
 		// Increase accuracy
 		newIters *= 2
-		newNumCombos := len(rankedResults) / 2
-		validCombos = validCombos[:newNumCombos]
-		rankedResults = rankedResults[:newNumCombos]
+		if b.Request.GetBaseSettings().GetSimOptions().GetConvergence() != nil {
+			// Each combo already stopped once its DPS was precise enough, so
+			// only rerun those which could still make the results.
+			rankedResults = unsettledResults(rankedResults, maxResults)
+		} else {
+			rankedResults = rankedResults[:len(rankedResults)/2]
+		}
+		validCombos = validCombos[:len(rankedResults)]
 		for i, comb := range rankedResults {
 			validCombos[i] = singleBulkSim{
 				req: comb.Request,
@@ -386,6 +394,19 @@ func (b *bulkSimRunner) getRankedResults(pctx context.Context, validCombos []sin
 	return rankedResults, baseResult, nil
 }
 
+// Returns the results, sorted by score, which might still be among the best
+// numResults: those whose 95% confidence interval reaches that of the
+// numResults-th best result.
+func unsettledResults(rankedResults []*itemSubstitutionSimResult, numResults int) []*itemSubstitutionSimResult {
+	if len(rankedResults) <= numResults {
+		return rankedResults
+	}
+	cutoff := rankedResults[numResults-1].Result.RaidMetrics.Dps.Ci95Low
+	return FilterSlice(rankedResults, func(result *itemSubstitutionSimResult) bool {
+		return result.Result.RaidMetrics.Dps.Ci95High >= cutoff
+	})
+}
+
 // itemSubstitutionSimResult stores the request and response of a simulation, along with the used
 // equipment susbstitution and a changelog of which items were added and removed from the base
 // equipment set.
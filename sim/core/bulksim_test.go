@@ -380,3 +380,35 @@ func TestGenerateAllEquipmentSubstitutions(t *testing.T) {
 		})
 	}
 }
+
+func TestUnsettledResults(t *testing.T) {
+	newResult := func(ci95Low float64, ci95High float64) *itemSubstitutionSimResult {
+		return &itemSubstitutionSimResult{
+			Result: &proto.RaidSimResult{
+				RaidMetrics: &proto.RaidMetrics{
+					Dps: &proto.DistributionMetrics{
+						Avg:      (ci95Low + ci95High) / 2,
+						Ci95Low:  ci95Low,
+						Ci95High: ci95High,
+					},
+				},
+			},
+		}
+	}
+	rankedResults := []*itemSubstitutionSimResult{
+		newResult(1000, 1100),
+		newResult(980, 1060),
+		newResult(990, 1010),
+		newResult(970, 1030),
+		newResult(900, 970),
+	}
+
+	// Only the last result is settled below the second best.
+	got := unsettledResults(rankedResults, 2)
+	if len(got) != 4 || got[3] != rankedResults[3] {
+		t.Fatalf("Expected the first 4 results, got %d", len(got))
+	}
+	if got := unsettledResults(rankedResults, 5); len(got) != 5 {
+		t.Fatalf("Expected all results when there are at most numResults, got %d", len(got))
+	}
+}
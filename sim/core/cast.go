@@ -74,13 +74,15 @@ type CastSuccessFunc func(*Simulation, *Unit) bool
 func (spell *Spell) makeCastFunc(config CastConfig, onCastComplete CastFunc) CastSuccessFunc {
 	return spell.wrapCastFuncInit(config,
 		spell.wrapCastFuncExtraCond(config,
-			spell.wrapCastFuncCDsReady(config,
-				spell.wrapCastFuncResources(config,
-					spell.wrapCastFuncHaste(config,
-						spell.wrapCastFuncGCD(config,
-							spell.wrapCastFuncCooldown(config,
-								spell.wrapCastFuncSharedCooldown(config,
-									spell.makeCastFuncWait(config, onCastComplete)))))))))
+			spell.wrapCastFuncMovement(config,
+				spell.wrapCastFuncTargetable(config,
+					spell.wrapCastFuncCDsReady(config,
+						spell.wrapCastFuncResources(config,
+							spell.wrapCastFuncHaste(config,
+								spell.wrapCastFuncGCD(config,
+									spell.wrapCastFuncCooldown(config,
+										spell.wrapCastFuncSharedCooldown(config,
+											spell.makeCastFuncWait(config, onCastComplete)))))))))))
 }
 
 func (spell *Spell) ApplyCostModifiers(cost float64) float64 {
@@ -133,6 +135,38 @@ func (spell *Spell) wrapCastFuncExtraCond(config CastConfig, onCastComplete Cast
 	}
 }
 
+func (spell *Spell) wrapCastFuncMovement(config CastConfig, onCastComplete CastSuccessFunc) CastSuccessFunc {
+	if spell.DefaultCast.CastTime == 0 && spell.DefaultCast.ChannelTime == 0 {
+		return onCastComplete
+	}
+
+	return func(sim *Simulation, target *Unit) bool {
+		if spell.castBlockedByMovement(spell.CurCast) {
+			if sim.Log != nil {
+				sim.Log("Failed cast because of movement")
+			}
+			return false
+		}
+		return onCastComplete(sim, target)
+	}
+}
+
+func (spell *Spell) wrapCastFuncTargetable(config CastConfig, onCastComplete CastSuccessFunc) CastSuccessFunc {
+	if spell.ProcMask == ProcMaskEmpty || spell.Flags.Matches(SpellFlagHelpful) {
+		return onCastComplete
+	}
+
+	return func(sim *Simulation, target *Unit) bool {
+		if spell.castBlockedByUntargetable(target) {
+			if sim.Log != nil {
+				sim.Log("Failed cast because the target is untargetable")
+			}
+			return false
+		}
+		return onCastComplete(sim, target)
+	}
+}
+
 func (spell *Spell) wrapCastFuncCDsReady(config CastConfig, onCastComplete CastSuccessFunc) CastSuccessFunc {
 	if spell.Unit.PseudoStats.GracefulCastCDFailures {
 		return func(sim *Simulation, target *Unit) bool {
@@ -283,12 +317,16 @@ func (spell *Spell) makeCastFuncWait(config CastConfig, onCastComplete CastFunc)
 
 	if spell.DefaultCast.ChannelTime > 0 {
 		return func(sim *Simulation, target *Unit) {
-			spell.Unit.Hardcast = Hardcast{Expires: sim.CurrentTime + spell.CurCast.ChannelTime, ActionID: spell.ActionID}
+			spell.Unit.Hardcast = Hardcast{Expires: sim.CurrentTime + spell.CurCast.ChannelTime, ActionID: spell.ActionID, Target: target}
 			if sim.Log != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
 				spell.Unit.Log(sim, "Casting %s (Cost = %0.03f, Cast Time = %s, Effective Time = %s)",
 					spell.ActionID, MaxFloat(0, spell.CurCast.Cost), spell.CurCast.CastTime, spell.CurCast.EffectiveTime())
 				spell.Unit.Log(sim, "Completed cast %s", spell.ActionID)
 			}
+			if sim.CombatLog != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
+				sim.CombatLog.addCast(sim, spell, target, CombatLogCastStart)
+				sim.CombatLog.addCast(sim, spell, target, CombatLogCastSuccess)
+			}
 			onCastComplete(sim, target)
 		}
 	}
@@ -303,6 +341,10 @@ func (spell *Spell) makeCastFuncWait(config CastConfig, onCastComplete CastFunc)
 						spell.ActionID, MaxFloat(0, spell.CurCast.Cost), spell.CurCast.CastTime, spell.CurCast.EffectiveTime())
 					spell.Unit.Log(sim, "Completed cast %s", spell.ActionID)
 				}
+				if sim.CombatLog != nil {
+					sim.CombatLog.addCast(sim, spell, target, CombatLogCastStart)
+					sim.CombatLog.addCast(sim, spell, target, CombatLogCastSuccess)
+				}
 				onCastComplete(sim, target)
 			}
 		}
@@ -313,6 +355,9 @@ func (spell *Spell) makeCastFuncWait(config CastConfig, onCastComplete CastFunc)
 				if sim.Log != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
 					spell.Unit.Log(sim, "Completed cast %s", spell.ActionID)
 				}
+				if sim.CombatLog != nil {
+					sim.CombatLog.addCast(sim, spell, target, CombatLogCastSuccess)
+				}
 				oldOnCastComplete3(sim, target)
 			}
 		}
@@ -322,6 +367,9 @@ func (spell *Spell) makeCastFuncWait(config CastConfig, onCastComplete CastFunc)
 				spell.Unit.Log(sim, "Casting %s (Cost = %0.03f, Cast Time = %s, Effective Time = %s)",
 					spell.ActionID, MaxFloat(0, spell.CurCast.Cost), spell.CurCast.CastTime, spell.CurCast.EffectiveTime())
 			}
+			if sim.CombatLog != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
+				sim.CombatLog.addCast(sim, spell, target, CombatLogCastStart)
+			}
 
 			// For instant-cast spells we can skip creating an aura.
 			if spell.CurCast.CastTime == 0 {
@@ -38,6 +38,7 @@ type Character struct {
 	Name  string // Different from Label, needed for returned results.
 	Race  proto.Race
 	Class proto.Class
+	Spec  proto.Spec
 
 	// Current gear.
 	Equip Equipment
@@ -91,6 +92,7 @@ func NewCharacter(party *Party, partyIndex int, player *proto.Player) Character
 		Name:  player.Name,
 		Race:  player.Race,
 		Class: player.Class,
+		Spec:  PlayerProtoToSpec(player),
 		Equip: ProtoToEquipment(player.Equipment),
 		professions: [2]proto.Profession{
 			player.Profession1,
@@ -394,6 +396,14 @@ func (character *Character) initialize(agent Agent) {
 
 			character.TryUseCooldowns(sim)
 			if character.GCD.IsReady(sim) {
+				// Many rotations treat failed casts as running out of mana, so
+				// they're held while their target can't be attacked instead.
+				// They're picked up again once the raid moves them to another
+				// target, or their target can be attacked again.
+				if target := character.CurrentTarget; target != nil && character.IsOpponent(target) && !target.IsTargetable() {
+					return
+				}
+
 				agent.OnGCDReady(sim)
 
 				if !character.doNothing && character.GCD.IsReady(sim) && (!character.IsWaiting() && !character.IsWaitingForMana()) {
@@ -457,6 +467,9 @@ func (character *Character) reset(sim *Simulation, agent Agent) {
 	character.majorCooldownManager.reset(sim)
 	character.ItemSwap.reset(sim)
 	character.CurrentTarget = character.defaultTarget
+	if character.Rotation != nil {
+		character.Rotation.reset(sim)
+	}
 
 	agent.Reset(sim)
 
@@ -479,6 +492,12 @@ func (character *Character) advance(sim *Simulation, elapsedTime time.Duration)
 	}
 }
 
+// Whether this character is controlled by an APL rotation instead of its
+// hand-written rotation.
+func (character *Character) IsUsingAPL() bool {
+	return character.Rotation != nil
+}
+
 func (character *Character) HasProfession(prof proto.Profession) bool {
 	return prof == character.professions[0] || prof == character.professions[1]
 }
@@ -0,0 +1,262 @@
+package core
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// Event types in the structured combat log. Names follow the Warcraft Logs
+// conventions where there is an equivalent.
+type CombatLogEventType string
+
+const (
+	CombatLogCastStart      CombatLogEventType = "SPELL_CAST_START"
+	CombatLogCastSuccess    CombatLogEventType = "SPELL_CAST_SUCCESS"
+	CombatLogDamage         CombatLogEventType = "SPELL_DAMAGE"
+	CombatLogPeriodicDamage CombatLogEventType = "SPELL_PERIODIC_DAMAGE"
+	CombatLogHeal           CombatLogEventType = "SPELL_HEAL"
+	CombatLogPeriodicHeal   CombatLogEventType = "SPELL_PERIODIC_HEAL"
+	CombatLogShield         CombatLogEventType = "SPELL_SHIELD"
+	CombatLogAuraApplied    CombatLogEventType = "SPELL_AURA_APPLIED"
+	CombatLogAuraRefresh    CombatLogEventType = "SPELL_AURA_REFRESH"
+	CombatLogAuraRemoved    CombatLogEventType = "SPELL_AURA_REMOVED"
+	CombatLogAuraStacks     CombatLogEventType = "SPELL_AURA_STACKS"
+	CombatLogResourceGain   CombatLogEventType = "SPELL_ENERGIZE"
+	CombatLogResourceSpend  CombatLogEventType = "SPELL_DRAIN"
+	CombatLogSummon         CombatLogEventType = "SPELL_SUMMON"
+	CombatLogDismiss        CombatLogEventType = "UNIT_DISMISSED"
+	CombatLogDeath          CombatLogEventType = "UNIT_DIED"
+)
+
+// A single entry in the structured combat log.
+type CombatLogEvent struct {
+	Timestamp time.Duration
+	Type      CombatLogEventType
+
+	// Either may be nil, e.g. auras only have a target.
+	Source *Unit
+	Target *Unit
+
+	ActionID ActionID
+	Outcome  HitOutcome
+
+	// Damage, healing, shielding or resource amount.
+	Amount float64
+	Threat float64
+
+	// For resource events, the type and the new value after the change.
+	Resource      proto.ResourceType
+	ResourceValue float64
+
+	// For aura events, the number of stacks after the change.
+	Stacks int32
+}
+
+// Field names shared by the JSON and CSV formats.
+var combatLogColumns = []string{
+	"timestamp", "event", "source", "target",
+	"spell_id", "item_id", "other_id", "tag",
+	"outcome", "amount", "threat",
+	"resource", "resource_value", "stacks",
+}
+
+type combatLogJSONEvent struct {
+	Timestamp     float64  `json:"timestamp"`
+	Event         string   `json:"event"`
+	Source        string   `json:"source,omitempty"`
+	Target        string   `json:"target,omitempty"`
+	SpellID       int32    `json:"spell_id,omitempty"`
+	ItemID        int32    `json:"item_id,omitempty"`
+	OtherID       string   `json:"other_id,omitempty"`
+	Tag           int32    `json:"tag,omitempty"`
+	Outcome       string   `json:"outcome,omitempty"`
+	Amount        float64  `json:"amount,omitempty"`
+	Threat        float64  `json:"threat,omitempty"`
+	Resource      string   `json:"resource,omitempty"`
+	ResourceValue *float64 `json:"resource_value,omitempty"`
+	Stacks        int32    `json:"stacks,omitempty"`
+}
+
+// Writes structured combat log events as they happen, in the format chosen by
+// SimOptions.combat_log_format.
+type CombatLog struct {
+	format    proto.CombatLogFormat
+	buffer    strings.Builder
+	csvWriter *csv.Writer
+}
+
+// Returns nil if the format is CombatLogFormatNone.
+func NewCombatLog(format proto.CombatLogFormat) *CombatLog {
+	if format == proto.CombatLogFormat_CombatLogFormatNone {
+		return nil
+	}
+
+	combatLog := &CombatLog{
+		format: format,
+	}
+	if format == proto.CombatLogFormat_CombatLogFormatCsv {
+		combatLog.csvWriter = csv.NewWriter(&combatLog.buffer)
+		combatLog.csvWriter.Write(combatLogColumns)
+	}
+	return combatLog
+}
+
+func (combatLog *CombatLog) Add(event *CombatLogEvent) {
+	jsonEvent := event.toJSONEvent()
+
+	switch combatLog.format {
+	case proto.CombatLogFormat_CombatLogFormatJsonLines:
+		line, err := json.Marshal(jsonEvent)
+		if err != nil {
+			panic(err)
+		}
+		combatLog.buffer.Write(line)
+		combatLog.buffer.WriteByte('\n')
+	case proto.CombatLogFormat_CombatLogFormatCsv:
+		combatLog.csvWriter.Write([]string{
+			strconv.FormatFloat(jsonEvent.Timestamp, 'f', 3, 64),
+			jsonEvent.Event,
+			jsonEvent.Source,
+			jsonEvent.Target,
+			formatCombatLogInt(jsonEvent.SpellID),
+			formatCombatLogInt(jsonEvent.ItemID),
+			jsonEvent.OtherID,
+			formatCombatLogInt(jsonEvent.Tag),
+			jsonEvent.Outcome,
+			formatCombatLogFloat(jsonEvent.Amount),
+			formatCombatLogFloat(jsonEvent.Threat),
+			jsonEvent.Resource,
+			formatCombatLogResourceValue(jsonEvent.ResourceValue),
+			formatCombatLogInt(jsonEvent.Stacks),
+		})
+	}
+}
+
+// Returns the events written so far, or "" if the log is disabled.
+func (combatLog *CombatLog) String() string {
+	if combatLog == nil {
+		return ""
+	}
+	if combatLog.csvWriter != nil {
+		combatLog.csvWriter.Flush()
+	}
+	return combatLog.buffer.String()
+}
+
+func (event *CombatLogEvent) toJSONEvent() *combatLogJSONEvent {
+	jsonEvent := &combatLogJSONEvent{
+		Timestamp: event.Timestamp.Seconds(),
+		Event:     string(event.Type),
+		SpellID:   event.ActionID.SpellID,
+		ItemID:    event.ActionID.ItemID,
+		Tag:       event.ActionID.Tag,
+		Amount:    event.Amount,
+		Threat:    event.Threat,
+		Stacks:    event.Stacks,
+	}
+	if event.Source != nil {
+		jsonEvent.Source = event.Source.Label
+	}
+	if event.Target != nil {
+		jsonEvent.Target = event.Target.Label
+	}
+	if event.ActionID.OtherID != proto.OtherAction_OtherActionNone {
+		jsonEvent.OtherID = event.ActionID.OtherID.String()
+	}
+	if event.Outcome != OutcomeEmpty {
+		jsonEvent.Outcome = event.Outcome.String()
+	}
+	if event.Resource != proto.ResourceType_ResourceTypeNone {
+		jsonEvent.Resource = strings.TrimPrefix(event.Resource.String(), "ResourceType")
+		jsonEvent.ResourceValue = &event.ResourceValue
+	}
+	return jsonEvent
+}
+
+func formatCombatLogFloat(value float64) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(value, 'f', 3, 64)
+}
+
+func formatCombatLogResourceValue(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', 3, 64)
+}
+
+func formatCombatLogInt(value int32) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.Itoa(int(value))
+}
+
+// Helpers for the common events, called from the central spell, aura,
+// resource and pet code paths.
+
+func (combatLog *CombatLog) addCast(sim *Simulation, spell *Spell, target *Unit, eventType CombatLogEventType) {
+	combatLog.Add(&CombatLogEvent{
+		Timestamp: sim.CurrentTime,
+		Type:      eventType,
+		Source:    spell.Unit,
+		Target:    target,
+		ActionID:  spell.ActionID,
+	})
+}
+
+func (combatLog *CombatLog) addSpellResult(sim *Simulation, spell *Spell, result *SpellResult, eventType CombatLogEventType) {
+	combatLog.Add(&CombatLogEvent{
+		Timestamp: sim.CurrentTime,
+		Type:      eventType,
+		Source:    spell.Unit,
+		Target:    result.Target,
+		ActionID:  spell.ActionID,
+		Outcome:   result.Outcome,
+		Amount:    result.Damage,
+		Threat:    result.Threat,
+	})
+}
+
+func (combatLog *CombatLog) addAura(sim *Simulation, aura *Aura, eventType CombatLogEventType) {
+	combatLog.Add(&CombatLogEvent{
+		Timestamp: sim.CurrentTime,
+		Type:      eventType,
+		Target:    aura.Unit,
+		ActionID:  aura.ActionID,
+		Stacks:    aura.stacks,
+	})
+}
+
+func (combatLog *CombatLog) addResource(sim *Simulation, unit *Unit, actionID ActionID, resourceType proto.ResourceType, amount float64, newValue float64) {
+	eventType := CombatLogResourceGain
+	if amount < 0 {
+		eventType = CombatLogResourceSpend
+		amount = -amount
+	}
+	combatLog.Add(&CombatLogEvent{
+		Timestamp:     sim.CurrentTime,
+		Type:          eventType,
+		Target:        unit,
+		ActionID:      actionID,
+		Amount:        amount,
+		Resource:      resourceType,
+		ResourceValue: newValue,
+	})
+}
+
+func (combatLog *CombatLog) addUnit(sim *Simulation, source *Unit, target *Unit, eventType CombatLogEventType) {
+	combatLog.Add(&CombatLogEvent{
+		Timestamp: sim.CurrentTime,
+		Type:      eventType,
+		Source:    source,
+		Target:    target,
+	})
+}
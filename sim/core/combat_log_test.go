@@ -0,0 +1,86 @@
+package core
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+func TestCombatLog(t *testing.T) {
+	rsr := newTestRaidSimRequest([]*proto.Player{
+		newTestCaster("Caster", ""),
+		newTestHealer("Healer"),
+	}, &proto.Encounter{
+		Duration: 10,
+		Targets:  []*proto.Target{newTestTarget(0)},
+	}, 3)
+	rsr.SimOptions.CombatLogFormat = proto.CombatLogFormat_CombatLogFormatJsonLines
+
+	result := runTestRaidSim(t, rsr)
+
+	eventCounts := make(map[string]int)
+	totalDamage := 0.0
+	totalHealing := 0.0
+	lastTimestamp := 0.0
+	lines := strings.Split(strings.TrimSpace(result.CombatLog), "\n")
+	for _, line := range lines {
+		event := struct {
+			Timestamp float64 `json:"timestamp"`
+			Event     string  `json:"event"`
+			Target    string  `json:"target"`
+			Amount    float64 `json:"amount"`
+		}{}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("Failed to parse combat log line %q: %s", line, err)
+		}
+		if event.Timestamp < lastTimestamp {
+			t.Fatalf("Combat log went back in time: %s", line)
+		}
+		lastTimestamp = event.Timestamp
+		eventCounts[event.Event]++
+		switch event.Event {
+		case "SPELL_DAMAGE":
+			totalDamage += event.Amount
+		case "SPELL_HEAL":
+			totalHealing += event.Amount
+		}
+	}
+
+	// Only the first iteration is logged. The caster starts a bolt every 2s,
+	// and lands 5 by the end of the fight, while the healer casts a heal
+	// every 1.5s.
+	expectedCounts := map[string]int{
+		"SPELL_CAST_START":   6 + 7,
+		"SPELL_CAST_SUCCESS": 5 + 7,
+		"SPELL_DAMAGE":       5,
+		"SPELL_HEAL":         7,
+		"SPELL_ENERGIZE":     7,
+	}
+	for eventType, expected := range expectedCounts {
+		if eventCounts[eventType] != expected {
+			t.Errorf("Expected %d %s events, got %d", expected, eventType, eventCounts[eventType])
+		}
+	}
+	if len(eventCounts) != len(expectedCounts) {
+		t.Errorf("Unexpected combat log events: %v", eventCounts)
+	}
+	if totalDamage != 5000 || totalHealing != 7000 {
+		t.Fatalf("Expected 5000 damage and 7000 healing, got %0.3f and %0.3f", totalDamage, totalHealing)
+	}
+
+	rsr.SimOptions.CombatLogFormat = proto.CombatLogFormat_CombatLogFormatCsv
+	result = runTestRaidSim(t, rsr)
+	records, err := csv.NewReader(strings.NewReader(result.CombatLog)).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse csv combat log: %s", err)
+	}
+	if len(records) != len(lines)+1 {
+		t.Fatalf("Expected %d csv rows and a header, got %d rows", len(lines), len(records))
+	}
+	if records[0][0] != "timestamp" || records[0][1] != "event" {
+		t.Fatalf("Unexpected csv header %v", records[0])
+	}
+}
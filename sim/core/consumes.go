@@ -454,21 +454,36 @@ func registerPotionCD(agent Agent, consumes *proto.Consumes) {
 
 	potionCD := character.NewTimer()
 
+	// A potion used before the pull only locks out the next one until its
+	// regular cooldown is over, leaving one more for combat.
+	setPrepopCD := func(sim *Simulation, potionType proto.Potions) {
+		if potionType == proto.Potions_IndestructiblePotion {
+			potionCD.Set(sim.CurrentTime + 2*time.Minute)
+		} else {
+			potionCD.Set(sim.CurrentTime + time.Minute)
+		}
+		character.UpdateMajorCooldowns()
+	}
+
 	startingMCD := makePotionActivation(startingPotion, character, potionCD)
 	if startingMCD.Spell != nil {
 		character.RegisterPrepullAction(-1*time.Second, func(sim *Simulation) {
 			startingMCD.Spell.Cast(sim, nil)
-			if startingPotion == proto.Potions_IndestructiblePotion {
-				potionCD.Set(sim.CurrentTime + 2*time.Minute)
-			} else {
-				potionCD.Set(sim.CurrentTime + time.Minute)
-			}
-			character.UpdateMajorCooldowns()
+			setPrepopCD(sim, startingPotion)
 		})
 	}
 
 	defaultMCD := makePotionActivation(defaultPotion, character, potionCD)
 	if defaultMCD.Spell != nil {
+		// The default potion can also be used before the pull, e.g. by an APL
+		// prepull action.
+		applyEffects := defaultMCD.Spell.ApplyEffects
+		defaultMCD.Spell.ApplyEffects = func(sim *Simulation, target *Unit, spell *Spell) {
+			applyEffects(sim, target, spell)
+			if sim.CurrentTime < 0 {
+				setPrepopCD(sim, defaultPotion)
+			}
+		}
 		character.AddMajorCooldown(defaultMCD)
 	}
 }
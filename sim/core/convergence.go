@@ -0,0 +1,56 @@
+package core
+
+import (
+	"math"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+const defaultConvergenceCheckInterval = 100
+
+// Returns the number of iterations to run between convergence checks. When
+// convergence isn't enabled, this is all of them.
+func convergenceBatchSize(options *proto.SimOptions) int32 {
+	convergence := options.Convergence
+	if convergence == nil || (convergence.MaxStderr <= 0 && convergence.MaxStderrPercent <= 0) {
+		return MaxInt32(1, options.Iterations)
+	}
+	if convergence.CheckInterval > 0 {
+		return convergence.CheckInterval
+	}
+	return defaultConvergenceCheckInterval
+}
+
+// Returns whether the raid DPS, aggregated over all the given Simulations, is
+// precise enough to stop. Raids that deal no damage use HPS instead.
+func simsConverged(options *proto.ConvergenceOptions, sims []*Simulation) bool {
+	n, sum, sumSq := 0, 0.0, 0.0
+	addSums := func(distMetrics *DistributionMetrics) {
+		n += distMetrics.n
+		sum += distMetrics.sum
+		sumSq += distMetrics.sumSq
+	}
+
+	for _, sim := range sims {
+		addSums(&sim.Raid.dpsMetrics)
+	}
+	if sum == 0 {
+		n, sum, sumSq = 0, 0, 0
+		for _, sim := range sims {
+			addSums(&sim.Raid.hpsMetrics)
+		}
+	}
+	if n < 2 {
+		return false
+	}
+
+	mean, _ := calcMeanAndStdevFromSums(n, sum, sumSq)
+	stderr := calcStderrFromSums(n, sum, sumSq)
+	if options.MaxStderr > 0 && stderr > options.MaxStderr {
+		return false
+	}
+	if options.MaxStderrPercent > 0 && stderr > math.Abs(mean)*options.MaxStderrPercent/100 {
+		return false
+	}
+	return true
+}
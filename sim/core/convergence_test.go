@@ -0,0 +1,50 @@
+package core
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+func TestConvergence(t *testing.T) {
+	rsr := newTestRaidSimRequest([]*proto.Player{newTestCaster("Caster", "actions+=/cast_spell,id=4\n")}, &proto.Encounter{
+		Duration: 10,
+		Targets:  []*proto.Target{newTestTarget(0)},
+	}, 5000)
+	rsr.SimOptions.Convergence = &proto.ConvergenceOptions{
+		MaxStderrPercent: 1,
+		// Every batch after the first starts past the logged iteration.
+		CheckInterval: 1,
+	}
+	rsr.SimOptions.CombatLogFormat = proto.CombatLogFormat_CombatLogFormatJsonLines
+
+	result := runTestRaidSim(t, rsr)
+	dps := result.RaidMetrics.Dps
+	if dps.Iterations >= rsr.SimOptions.Iterations {
+		t.Fatalf("Expected the sim to stop early, got %d iterations", dps.Iterations)
+	}
+	if dps.Stderr > dps.Avg*0.01 {
+		t.Fatalf("Stopped with a standard error of %0.3f, above 1%% of %0.3f", dps.Stderr, dps.Avg)
+	}
+	if dps.Ci95Low >= dps.Avg || dps.Ci95High <= dps.Avg || math.Abs(dps.Ci95High-dps.Avg-1.96*dps.Stderr) > 1e-9 {
+		t.Fatalf("Bad 95%% confidence interval %0.3f-%0.3f for %0.3f +- %0.3f", dps.Ci95Low, dps.Ci95High, dps.Avg, dps.Stderr)
+	}
+
+	// Only the first iteration is logged, with a cast every 1.5s.
+	if damageEvents := strings.Count(result.CombatLog, `"event":"SPELL_DAMAGE"`); damageEvents != 7 {
+		t.Fatalf("Expected 7 damage events in the combat log, got %d", damageEvents)
+	}
+
+	// Checks only happen between batches, so parallel sims stop at the same point.
+	rsr.SimOptions.NumWorkers = 3
+	parallelResult := runTestRaidSim(t, rsr)
+	parallelDps := parallelResult.RaidMetrics.Dps
+	if parallelDps.Iterations != dps.Iterations || math.Abs(parallelDps.Avg-dps.Avg) > 0.001 {
+		t.Fatalf("Parallel sim stopped after %d iterations with %0.3f dps, sequential after %d with %0.3f", parallelDps.Iterations, parallelDps.Avg, dps.Iterations, dps.Avg)
+	}
+	if parallelResult.CombatLog != result.CombatLog {
+		t.Fatalf("Expected the parallel sim to log the same iteration")
+	}
+}
@@ -0,0 +1,189 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// Makes the target untargetable until a matching call to EndUntargetable.
+// Calls may overlap, e.g. for a target window during encounter downtime.
+func (target *Target) StartUntargetable(sim *Simulation) {
+	target.untargetable++
+	if target.untargetable > 1 {
+		return
+	}
+
+	if sim.Log != nil {
+		target.Log(sim, "Became untargetable")
+	}
+	target.untargetableSince = sim.CurrentTime
+
+	encounter := &sim.Encounter
+	encounter.numUntargetable++
+	if encounter.numUntargetable == len(encounter.Targets) {
+		encounter.downtimeSince = sim.CurrentTime
+	}
+	encounter.updateRaidTarget(sim)
+}
+
+func (target *Target) EndUntargetable(sim *Simulation) {
+	if target.untargetable == 0 {
+		panic("EndUntargetable without StartUntargetable for " + target.Label)
+	}
+	target.untargetable--
+	if target.untargetable > 0 {
+		return
+	}
+
+	if sim.Log != nil {
+		target.Log(sim, "Became targetable")
+	}
+	target.untargetableTime += sim.CurrentTime - target.untargetableSince
+
+	encounter := &sim.Encounter
+	if encounter.numUntargetable == len(encounter.Targets) {
+		encounter.downtimeTime += sim.CurrentTime - encounter.downtimeSince
+	}
+	encounter.numUntargetable--
+	encounter.updateRaidTarget(sim)
+
+	// Players who stayed on the target, like its tank, can attack it again.
+	if encounter.raidTarget != nil {
+		for _, unit := range sim.Raid.AllUnits {
+			if unit.CurrentTarget == &target.Unit {
+				unit.wakeUpRotation(sim)
+			}
+		}
+	}
+}
+
+// Time in the current iteration so far in which the target could be attacked.
+func (target *Target) TargetableTime(sim *Simulation) time.Duration {
+	untargetableTime := target.untargetableTime
+	if target.untargetable > 0 {
+		untargetableTime += sim.CurrentTime - target.untargetableSince
+	}
+	return sim.CurrentTime - untargetableTime
+}
+
+// Time in the current iteration so far in which at least one target could be
+// attacked.
+func (encounter *Encounter) UptimeDuration(sim *Simulation) time.Duration {
+	downtime := encounter.downtimeTime
+	if encounter.numUntargetable == len(encounter.Targets) {
+		downtime += sim.CurrentTime - encounter.downtimeSince
+	}
+	return sim.CurrentTime - downtime
+}
+
+// Spells which affect their target can't be cast at an opponent who can't be
+// attacked. Helpful spells and spells without a proc mask, like most
+// cooldowns, are still usable.
+func (spell *Spell) castBlockedByUntargetable(target *Unit) bool {
+	if target == nil {
+		target = spell.Unit.CurrentTarget
+	}
+	return target != nil && spell.ProcMask != ProcMaskEmpty && !spell.Flags.Matches(SpellFlagHelpful) &&
+		spell.targetIsUntargetable(target)
+}
+
+func validateTimeWindows(windows []*proto.TimeWindow, label string) {
+	for _, window := range windows {
+		if window.StartSeconds < 0 || window.DurationSeconds < 0 {
+			panic(fmt.Sprintf("Invalid %s window at %0.2fs for %0.2fs", label, window.StartSeconds, window.DurationSeconds))
+		}
+	}
+}
+
+// Makes the targets untargetable during each window.
+func scheduleUntargetableWindows(sim *Simulation, windows []*proto.TimeWindow, targets []*Target) {
+	for _, window := range windows {
+		start := DurationFromSeconds(window.StartSeconds)
+		startWindow := func(sim *Simulation) {
+			for _, target := range targets {
+				target.StartUntargetable(sim)
+			}
+		}
+
+		// Windows from the pull apply right away, so they are already in
+		// effect when players reset.
+		if start == 0 {
+			startWindow(sim)
+		} else {
+			StartDelayedAction(sim, DelayedActionOptions{
+				DoAt: start,
+				// Ahead of anything else at the same time, like window ends.
+				Priority: ActionPriorityDOT,
+				OnAction: startWindow,
+			})
+		}
+
+		if window.DurationSeconds > 0 {
+			StartDelayedAction(sim, DelayedActionOptions{
+				DoAt:     start + DurationFromSeconds(window.DurationSeconds),
+				Priority: ActionPriorityDOT,
+				OnAction: func(sim *Simulation) {
+					for _, target := range targets {
+						target.EndUntargetable(sim)
+					}
+				},
+			})
+		}
+	}
+}
+
+func (encounter *Encounter) reset(sim *Simulation) {
+	encounter.numUntargetable = 0
+	encounter.downtimeTime = 0
+	// Adds and windows from the pull don't move the raid, which hasn't reset
+	// yet.
+	encounter.raidTarget = nil
+
+	encounter.resetAdds(sim)
+	encounter.resetRaidTarget(sim)
+	scheduleUntargetableWindows(sim, encounter.downtime, encounter.Targets)
+	for _, target := range encounter.Targets {
+		scheduleUntargetableWindows(sim, target.untargetableWindows, []*Target{target})
+	}
+	for _, event := range encounter.movement {
+		event.schedule(sim)
+	}
+
+	for i, phaseStart := range encounter.PhaseStarts {
+		phase := i + 2
+		StartDelayedAction(sim, DelayedActionOptions{
+			DoAt:     phaseStart,
+			Priority: ActionPriorityDOT,
+			OnAction: func(sim *Simulation) {
+				sim.startPhase(phase)
+			},
+		})
+	}
+
+	// Players start out on the first target.
+	encounter.raidTarget = encounter.Targets[0]
+}
+
+func (sim *Simulation) startPhase(phase int) {
+	if sim.Log != nil {
+		sim.Log("Encounter phase %d begins", phase)
+	}
+	sim.phase = phase
+	for _, callback := range sim.phaseCallbacks {
+		callback(sim, phase)
+	}
+}
+
+// The current encounter phase, starting at 1.
+func (sim *Simulation) CurrentPhase() int {
+	return sim.phase
+}
+
+// Registers a callback for the start of each encounter phase after the first.
+// Like execute phase callbacks these are cleared on reset, so they should be
+// registered from reset handlers.
+func (sim *Simulation) RegisterPhaseCallback(callback func(*Simulation, int)) {
+	sim.phaseCallbacks = append(sim.phaseCallbacks, callback)
+}
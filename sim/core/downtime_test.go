@@ -0,0 +1,61 @@
+package core
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+func TestEncounterDowntime(t *testing.T) {
+	rsr := newTestRaidSimRequest([]*proto.Player{
+		newTestCaster("Legacy", ""),
+		newTestCaster("APL", "actions+=/cast_spell,id=2"),
+	}, &proto.Encounter{
+		Duration:          60,
+		Targets:           []*proto.Target{newTestTarget(0)},
+		PhaseStartSeconds: []float64{20, 35},
+		// An intermission between the two phase changes.
+		Downtime: []*proto.TimeWindow{{StartSeconds: 20, DurationSeconds: 15}},
+	}, 6)
+	rsr.SimOptions.TimelineBucketSeconds = 5
+	result := runTestRaidSim(t, rsr)
+
+	if targetable := result.EncounterMetrics.Targets[0].TargetablePercent; math.Abs(targetable-75) > 0.001 {
+		t.Fatalf("Expected the target to be targetable 75%% of the fight, got %0.3f%%", targetable)
+	}
+
+	// Nothing is cast at the target during downtime. Bolts take 2s: 10 land
+	// by 20s, and 12 of those from 35s on before the end of the fight. Shocks
+	// go out every 1.5s: 14 before 20s and 17 from 35s on.
+	expectedCasts := map[string]int32{
+		"Legacy": 22 * rsr.SimOptions.Iterations,
+		"APL":    31 * rsr.SimOptions.Iterations,
+	}
+	for _, player := range result.RaidMetrics.Parties[0].Players {
+		casts, hits := int32(0), int32(0)
+		for _, action := range player.Actions {
+			for _, target := range action.Targets {
+				casts += target.Casts
+				hits += target.Hits
+			}
+		}
+		if casts != expectedCasts[player.Name] || hits != casts {
+			t.Fatalf("%s: expected %d casts which all hit, got %d casts and %d hits", player.Name, expectedCasts[player.Name], casts, hits)
+		}
+
+		for bucket := 4; bucket < 6; bucket++ {
+			if damage := player.Timeline.Damage[bucket]; damage != 0 {
+				t.Fatalf("%s: expected no damage during downtime, got %0.3f at %ds", player.Name, damage, bucket*5)
+			}
+		}
+
+		uptimeDps := player.Dps.Avg * 60 / 45
+		if math.Abs(player.UptimeDps.Avg-uptimeDps) > 0.001 {
+			t.Fatalf("%s: uptime dps is %0.3f, expected %0.3f", player.Name, player.UptimeDps.Avg, uptimeDps)
+		}
+		if math.Abs(player.TargetDps[0].TargetableDps.Avg-uptimeDps) > 0.001 {
+			t.Fatalf("%s: targetable dps is %0.3f, expected %0.3f", player.Name, player.TargetDps[0].TargetableDps.Avg, uptimeDps)
+		}
+	}
+}
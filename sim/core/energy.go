@@ -40,9 +40,15 @@ func (unit *Unit) EnableEnergyBar(maxEnergy float64, onEnergyGain OnEnergyGain)
 		unit:      unit,
 		maxEnergy: MaxFloat(100, maxEnergy),
 		onEnergyGain: func(sim *Simulation) {
-			if !sim.Options.Interactive && (!unit.IsWaitingForEnergy() || unit.DoneWaitingForEnergy(sim)) {
+			if sim.Options.Interactive {
+				return
+			}
+			if !unit.IsWaitingForEnergy() || unit.DoneWaitingForEnergy(sim) {
 				onEnergyGain(sim)
 			}
+			if unit.Rotation != nil {
+				unit.Rotation.onIdleWakeup(sim)
+			}
 		},
 		EnergyTickMultiplier: 1,
 		regenMetrics:         unit.NewEnergyMetrics(ActionID{OtherID: proto.OtherAction_OtherActionEnergyRegen}),
@@ -73,6 +79,12 @@ func (eb *energyBar) addEnergyInternal(sim *Simulation, amount float64, metrics
 	if sim.Log != nil {
 		eb.unit.Log(sim, "Gained %0.3f energy from %s (%0.3f --> %0.3f).", amount, metrics.ActionID, eb.currentEnergy, newEnergy)
 	}
+	if timeline := eb.unit.Metrics.timeline; timeline != nil {
+		timeline.beforeResourceChange(sim, proto.ResourceType_ResourceTypeEnergy)
+	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.addResource(sim, eb.unit, metrics.ActionID, proto.ResourceType_ResourceTypeEnergy, amount, newEnergy)
+	}
 
 	eb.currentEnergy = newEnergy
 }
@@ -92,6 +104,12 @@ func (eb *energyBar) SpendEnergy(sim *Simulation, amount float64, metrics *Resou
 	if sim.Log != nil {
 		eb.unit.Log(sim, "Spent %0.3f energy from %s (%0.3f --> %0.3f).", amount, metrics.ActionID, eb.currentEnergy, newEnergy)
 	}
+	if timeline := eb.unit.Metrics.timeline; timeline != nil {
+		timeline.beforeResourceChange(sim, proto.ResourceType_ResourceTypeEnergy)
+	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.addResource(sim, eb.unit, metrics.ActionID, proto.ResourceType_ResourceTypeEnergy, -amount, newEnergy)
+	}
 
 	eb.currentEnergy = newEnergy
 }
@@ -118,6 +136,9 @@ func (eb *energyBar) AddComboPoints(sim *Simulation, pointsToAdd int32, metrics
 	if sim.Log != nil {
 		eb.unit.Log(sim, "Gained %d combo points from %s (%d --> %d)", pointsToAdd, metrics.ActionID, eb.comboPoints, newComboPoints)
 	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.addResource(sim, eb.unit, metrics.ActionID, proto.ResourceType_ResourceTypeComboPoints, float64(pointsToAdd), float64(newComboPoints))
+	}
 
 	eb.comboPoints = newComboPoints
 }
@@ -126,6 +147,9 @@ func (eb *energyBar) SpendComboPoints(sim *Simulation, metrics *ResourceMetrics)
 	if sim.Log != nil {
 		eb.unit.Log(sim, "Spent %d combo points from %s (%d --> %d).", eb.comboPoints, metrics.ActionID, eb.comboPoints, 0)
 	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.addResource(sim, eb.unit, metrics.ActionID, proto.ResourceType_ResourceTypeComboPoints, float64(-eb.comboPoints), 0)
+	}
 	metrics.AddEvent(float64(-eb.comboPoints), float64(-eb.comboPoints))
 	eb.comboPoints = 0
 }
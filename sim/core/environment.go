@@ -100,6 +100,8 @@ func (env *Environment) construct(raidProto *proto.Raid, encounterProto *proto.E
 		}
 	}
 
+	env.Encounter.movement = newMovementEvents(encounterProto.Movement, env.Raid)
+
 	env.State = Constructed
 }
 
@@ -208,6 +210,7 @@ func (env *Environment) reset(sim *Simulation) {
 	for _, target := range env.Encounter.Targets {
 		target.Reset(sim)
 	}
+	env.Encounter.reset(sim)
 
 	env.Raid.reset(sim)
 }
@@ -217,15 +220,18 @@ func (env *Environment) GetMaxDuration() time.Duration {
 	return env.BaseDuration + env.DurationVariation
 }
 
+// Number of targets in the fight, which changes as adds spawn and despawn.
 func (env *Environment) GetNumTargets() int32 {
-	return int32(len(env.Encounter.Targets))
+	return int32(len(env.Encounter.ActiveTargets))
 }
 
+// The target in the fight at the given index, which isn't the encounter index
+// once adds are involved.
 func (env *Environment) GetTarget(index int32) *Target {
-	return env.Encounter.Targets[index]
+	return env.Encounter.ActiveTargets[index]
 }
 func (env *Environment) GetTargetUnit(index int32) *Unit {
-	return &env.Encounter.Targets[index].Unit
+	return &env.Encounter.ActiveTargets[index].Unit
 }
 func (env *Environment) NextTarget(target *Unit) *Target {
 	return env.Encounter.Targets[target.Index].NextTarget()
@@ -51,6 +51,12 @@ func (fb *focusBar) AddFocus(sim *Simulation, amount float64, actionID ActionID)
 	if sim.Log != nil {
 		fb.unit.Log(sim, "Gained %0.3f focus from %s (%0.3f --> %0.3f).", amount, actionID, fb.currentFocus, newFocus)
 	}
+	if timeline := fb.unit.Metrics.timeline; timeline != nil {
+		timeline.beforeResourceChange(sim, proto.ResourceType_ResourceTypeFocus)
+	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.addResource(sim, fb.unit, actionID, proto.ResourceType_ResourceTypeFocus, amount, newFocus)
+	}
 
 	fb.currentFocus = newFocus
 
@@ -70,6 +76,12 @@ func (fb *focusBar) SpendFocus(sim *Simulation, amount float64, metrics *Resourc
 	if sim.Log != nil {
 		fb.unit.Log(sim, "Spent %0.3f focus from %s (%0.3f --> %0.3f).", amount, metrics.ActionID, fb.currentFocus, newFocus)
 	}
+	if timeline := fb.unit.Metrics.timeline; timeline != nil {
+		timeline.beforeResourceChange(sim, proto.ResourceType_ResourceTypeFocus)
+	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.addResource(sim, fb.unit, metrics.ActionID, proto.ResourceType_ResourceTypeFocus, -amount, newFocus)
+	}
 
 	fb.currentFocus = newFocus
 }
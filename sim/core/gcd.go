@@ -60,6 +60,20 @@ func (unit *Unit) CancelGCDTimer(sim *Simulation) {
 	unit.gcdAction.Cancel(sim)
 }
 
+// Picks the unit's rotation up again right away, if it's stalled. This covers
+// rotations which didn't schedule another action, e.g. after a failed cast or
+// while their target couldn't be attacked, and idle APL rotations.
+func (unit *Unit) wakeUpRotation(sim *Simulation) {
+	if unit.gcdAction == nil || !unit.IsEnabled() || unit.Hardcast.Expires > sim.CurrentTime {
+		return
+	}
+	if unit.gcdAction.consumed {
+		unit.SetGCDTimer(sim, MaxDuration(sim.CurrentTime, unit.GCD.ReadyAt()))
+	} else if unit.Rotation != nil {
+		unit.Rotation.onIdleWakeup(sim)
+	}
+}
+
 func (unit *Unit) IsWaiting() bool {
 	return unit.waitStartTime != 0
 }
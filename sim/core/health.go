@@ -45,7 +45,8 @@ func (hb *healthBar) CurrentHealthPercent() float64 {
 	return hb.currentHealth / hb.unit.stats[stats.Health]
 }
 
-func (hb *healthBar) GainHealth(sim *Simulation, amount float64, metrics *ResourceMetrics) {
+// Returns the health actually gained, i.e. amount without overhealing.
+func (hb *healthBar) GainHealth(sim *Simulation, amount float64, metrics *ResourceMetrics) float64 {
 	if amount < 0 {
 		panic("Trying to gain negative health!")
 	}
@@ -53,12 +54,17 @@ func (hb *healthBar) GainHealth(sim *Simulation, amount float64, metrics *Resour
 	oldHealth := hb.currentHealth
 	newHealth := MinFloat(oldHealth+amount, hb.unit.MaxHealth())
 	metrics.AddEvent(amount, newHealth-oldHealth)
+	hb.unit.Metrics.healingReceived.Total += newHealth - oldHealth
 
 	if sim.Log != nil {
 		hb.unit.Log(sim, "Gained %0.3f health from %s (%0.3f --> %0.3f).", amount, metrics.ActionID, oldHealth, newHealth)
 	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.addResource(sim, hb.unit, metrics.ActionID, proto.ResourceType_ResourceTypeHealth, amount, newHealth)
+	}
 
 	hb.currentHealth = newHealth
+	return newHealth - oldHealth
 }
 
 func (hb *healthBar) RemoveHealth(sim *Simulation, amount float64) {
@@ -83,6 +89,9 @@ func (hb *healthBar) RemoveHealth(sim *Simulation, amount float64) {
 	if sim.Log != nil {
 		hb.unit.Log(sim, "Spent %0.3f health from %s (%0.3f --> %0.3f).", amount, metrics.ActionID, oldHealth, newHealth)
 	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.addResource(sim, hb.unit, metrics.ActionID, proto.ResourceType_ResourceTypeHealth, -amount, newHealth)
+	}
 
 	hb.currentHealth = newHealth
 }
@@ -121,6 +130,9 @@ func (character *Character) trackChanceOfDeath(healingModel *proto.HealingModel)
 					if sim.Log != nil {
 						character.Log(sim, "Dead")
 					}
+					if sim.CombatLog != nil {
+						sim.CombatLog.addUnit(sim, spell.Unit, &character.Unit, CombatLogDeath)
+					}
 				}
 			}
 		},
@@ -133,6 +145,9 @@ func (character *Character) trackChanceOfDeath(healingModel *proto.HealingModel)
 					if sim.Log != nil {
 						character.Log(sim, "Dead")
 					}
+					if sim.CombatLog != nil {
+						sim.CombatLog.addUnit(sim, spell.Unit, &character.Unit, CombatLogDeath)
+					}
 				}
 			}
 		},
@@ -143,16 +158,51 @@ func (character *Character) trackChanceOfDeath(healingModel *proto.HealingModel)
 	}
 }
 
-func (character *Character) applyHealingModel(healingModel *proto.HealingModel) {
-	// Store variance parameters for healing cadence. Note that low rolls on
+// Randomized time between modeled heals or hits.
+type cadenceModel struct {
+	median       float64
+	min          float64
+	variation    float64
+	variationLow float64
+
+	signLabel      string
+	magnitudeLabel string
+}
+
+func newCadenceModel(label string, cadenceSeconds float64, cadenceVariation float64) cadenceModel {
+	// Store variance parameters for the cadence. Note that low rolls on
 	// cadence are special cased here so that the model is still well-behaved
 	// when CadenceVariation exceeds CadenceSeconds.
-	medianCadence := healingModel.CadenceSeconds
+	medianCadence := cadenceSeconds
 	if medianCadence == 0 {
 		medianCadence = 2.0
 	}
-	minCadence := MaxFloat(0.0, medianCadence-healingModel.CadenceVariation)
-	cadenceVariationLow := medianCadence - minCadence
+	minCadence := MaxFloat(0.0, medianCadence-cadenceVariation)
+	return cadenceModel{
+		median:         medianCadence,
+		min:            minCadence,
+		variation:      cadenceVariation,
+		variationLow:   medianCadence - minCadence,
+		signLabel:      label + " Cadence Variation Sign",
+		magnitudeLabel: label + " Cadence Variation Magnitude",
+	}
+}
+
+// Random roll for time to next event. In the case where CadenceVariation exceeds CadenceSeconds, then
+// CadenceSeconds is treated as the median, with two separate uniform distributions to the left and right
+// of it.
+func (cadence *cadenceModel) next(sim *Simulation) time.Duration {
+	signRoll := sim.RandomFloat(cadence.signLabel)
+	magnitudeRoll := sim.RandomFloat(cadence.magnitudeLabel)
+
+	if signRoll < 0.5 {
+		return DurationFromSeconds(cadence.min + magnitudeRoll*cadence.variationLow)
+	}
+	return DurationFromSeconds(cadence.median + magnitudeRoll*cadence.variation)
+}
+
+func (character *Character) applyHealingModel(healingModel *proto.HealingModel) {
+	cadence := newCadenceModel("Healing", healingModel.CadenceSeconds, healingModel.CadenceVariation)
 
 	healthMetrics := character.NewHealthMetrics(ActionID{OtherID: proto.OtherAction_OtherActionHealingModel})
 
@@ -184,17 +234,7 @@ func (character *Character) applyHealingModel(healingModel *proto.HealingModel)
 				willOfTheNecropolisAura.Deactivate(sim)
 			}
 
-			// Random roll for time to next heal. In the case where CadenceVariation exceeds CadenceSeconds, then
-			// CadenceSeconds is treated as the median, with two separate uniform distributions to the left and right
-			// of it.
-			signRoll := sim.RandomFloat("Healing Cadence Variation Sign")
-			magnitudeRoll := sim.RandomFloat("Healing Cadence Variation Magnitude")
-
-			if signRoll < 0.5 {
-				timeToNextHeal = DurationFromSeconds(minCadence + magnitudeRoll*cadenceVariationLow)
-			} else {
-				timeToNextHeal = DurationFromSeconds(medianCadence + magnitudeRoll*healingModel.CadenceVariation)
-			}
+			timeToNextHeal = cadence.next(sim)
 
 			// Refresh action
 			pa.NextActionAt = sim.CurrentTime + timeToNextHeal
@@ -90,6 +90,22 @@ func (swap *ItemSwap) IsEnabled() bool {
 	return swap.character != nil
 }
 
+// Whether any of the swappable slots currently hold a different item than at
+// the start of the iteration.
+func (swap *ItemSwap) IsSwapped() bool {
+	if !swap.IsEnabled() {
+		return false
+	}
+
+	slots := [3]proto.ItemSlot{proto.ItemSlot_ItemSlotMainHand, proto.ItemSlot_ItemSlotOffHand, proto.ItemSlot_ItemSlotRanged}
+	for i, slot := range slots {
+		if swap.character.Equip[slot].ID != swap.initialEquippedItems[i].ID {
+			return true
+		}
+	}
+	return false
+}
+
 func (swap *ItemSwap) GetItem(slot proto.ItemSlot) *Item {
 	if slot-offset < 0 {
 		panic("Not able to swap Item " + slot.String() + " not supported")
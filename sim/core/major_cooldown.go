@@ -126,6 +126,16 @@ func (mcd *MajorCooldown) tryActivateInternal(sim *Simulation, character *Charac
 // Activates this MCD, if all the conditions pass.
 // Returns whether the MCD was activated.
 func (mcd *MajorCooldown) tryActivateHelper(sim *Simulation, character *Character) bool {
+	if !mcd.shouldActivateHelper(sim, character) {
+		return false
+	}
+
+	mcd.activate(sim, character)
+	return true
+}
+
+// Whether all the conditions for activating this MCD pass.
+func (mcd *MajorCooldown) shouldActivateHelper(sim *Simulation, character *Character) bool {
 	if mcd.Type.Matches(CooldownTypeSurvival) && character.cooldownConfigs.HpPercentForDefensives != 0 {
 		if character.CurrentHealthPercent() > character.cooldownConfigs.HpPercentForDefensives {
 			return false
@@ -136,27 +146,25 @@ func (mcd *MajorCooldown) tryActivateHelper(sim *Simulation, character *Characte
 		return false
 	}
 
-	var shouldActivate bool
 	if mcd.numUsages < len(mcd.timings) {
-		shouldActivate = sim.CurrentTime >= mcd.timings[mcd.numUsages]
+		return sim.CurrentTime >= mcd.timings[mcd.numUsages]
 	} else {
-		shouldActivate = mcd.ShouldActivate(sim, character)
+		return mcd.ShouldActivate(sim, character)
 	}
+}
 
-	if shouldActivate {
-		if mcd.Spell.Flags.Matches(SpellFlagHelpful) {
-			mcd.Spell.Cast(sim, &character.Unit)
-		} else {
-			mcd.Spell.Cast(sim, character.CurrentTarget)
-		}
-
-		mcd.numUsages++
-		if sim.Log != nil {
-			character.Log(sim, "Major cooldown used: %s", mcd.Spell.ActionID)
-		}
+// Casts the MCD spell, without checking any conditions.
+func (mcd *MajorCooldown) activate(sim *Simulation, character *Character) {
+	if mcd.Spell.Flags.Matches(SpellFlagHelpful) {
+		mcd.Spell.Cast(sim, &character.Unit)
+	} else {
+		mcd.Spell.Cast(sim, character.CurrentTarget)
 	}
 
-	return shouldActivate
+	mcd.numUsages++
+	if sim.Log != nil {
+		character.Log(sim, "Major cooldown used: %s", mcd.Spell.ActionID)
+	}
 }
 
 type cooldownConfigs struct {
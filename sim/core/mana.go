@@ -100,6 +100,12 @@ func (unit *Unit) AddMana(sim *Simulation, amount float64, metrics *ResourceMetr
 	if sim.Log != nil {
 		unit.Log(sim, "Gained %0.3f mana from %s (%0.3f --> %0.3f).", amount, metrics.ActionID, oldMana, newMana)
 	}
+	if timeline := unit.Metrics.timeline; timeline != nil {
+		timeline.beforeResourceChange(sim, proto.ResourceType_ResourceTypeMana)
+	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.addResource(sim, unit, metrics.ActionID, proto.ResourceType_ResourceTypeMana, amount, newMana)
+	}
 
 	unit.currentMana = newMana
 	unit.Metrics.ManaGained += newMana - oldMana
@@ -116,6 +122,12 @@ func (unit *Unit) SpendMana(sim *Simulation, amount float64, metrics *ResourceMe
 	if sim.Log != nil {
 		unit.Log(sim, "Spent %0.3f mana from %s (%0.3f --> %0.3f).", amount, metrics.ActionID, unit.CurrentMana(), newMana)
 	}
+	if timeline := unit.Metrics.timeline; timeline != nil {
+		timeline.beforeResourceChange(sim, proto.ResourceType_ResourceTypeMana)
+	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.addResource(sim, unit, metrics.ActionID, proto.ResourceType_ResourceTypeMana, -amount, newMana)
+	}
 
 	unit.currentMana = newMana
 	unit.Metrics.ManaSpent += amount
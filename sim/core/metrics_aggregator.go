@@ -24,7 +24,7 @@ type DistributionMetrics struct {
 	min     float64
 	maxSeed int64
 	minSeed int64
-	hist    map[int32]int32 // rounded DPS to count
+	hist    map[int32]int64 // rounded DPS to count
 	sample  []float64
 }
 
@@ -58,24 +58,67 @@ func (distMetrics *DistributionMetrics) doneIteration(sim *Simulation) {
 	distMetrics.hist[dpsRounded]++
 }
 
+// Adds the aggregate values of another DistributionMetrics, e.g. from another
+// worker of a parallel sim.
+func (distMetrics *DistributionMetrics) merge(other *DistributionMetrics) {
+	if other.n == 0 {
+		return
+	}
+	distMetrics.n += other.n
+	distMetrics.sum += other.sum
+	distMetrics.sumSq += other.sumSq
+	distMetrics.sample = append(distMetrics.sample, other.sample...)
+
+	if other.max > distMetrics.max {
+		distMetrics.max = other.max
+		distMetrics.maxSeed = other.maxSeed
+	}
+	if other.min <= distMetrics.min || distMetrics.min < 0 {
+		distMetrics.min = other.min
+		distMetrics.minSeed = other.minSeed
+	}
+
+	for dpsRounded, count := range other.hist {
+		distMetrics.hist[dpsRounded] += count
+	}
+}
+
 func (distMetrics *DistributionMetrics) ToProto() *proto.DistributionMetrics {
 	mean, stdev := calcMeanAndStdevFromSums(distMetrics.n, distMetrics.sum, distMetrics.sumSq)
+	stderr := calcStderrFromSums(distMetrics.n, distMetrics.sum, distMetrics.sumSq)
 
 	return &proto.DistributionMetrics{
-		Avg:       mean,
-		Stdev:     stdev,
-		Max:       distMetrics.max,
-		Min:       distMetrics.min,
-		MaxSeed:   distMetrics.maxSeed,
-		MinSeed:   distMetrics.minSeed,
-		Hist:      distMetrics.hist,
-		AllValues: distMetrics.sample,
+		Avg:        mean,
+		Stdev:      stdev,
+		Max:        distMetrics.max,
+		Min:        distMetrics.min,
+		MaxSeed:    distMetrics.maxSeed,
+		MinSeed:    distMetrics.minSeed,
+		Hist:       distMetrics.histToProto(),
+		AllValues:  distMetrics.sample,
+		Stderr:     stderr,
+		Ci95Low:    mean - 1.96*stderr,
+		Ci95High:   mean + 1.96*stderr,
+		Iterations: int32(distMetrics.n),
 	}
 }
 
+// Counts are kept as int64 so they can't overflow on large iteration counts,
+// and only narrowed for the proto.
+func (distMetrics *DistributionMetrics) histToProto() map[int32]int32 {
+	hist := make(map[int32]int32, len(distMetrics.hist))
+	for dpsRounded, count := range distMetrics.hist {
+		if count > math.MaxInt32 {
+			count = math.MaxInt32
+		}
+		hist[dpsRounded] = int32(count)
+	}
+	return hist
+}
+
 func NewDistributionMetrics() DistributionMetrics {
 	return DistributionMetrics{
-		hist: make(map[int32]int32),
+		hist: make(map[int32]int64),
 		min:  -1,
 	}
 }
@@ -87,8 +130,12 @@ type UnitMetrics struct {
 	dtps   DistributionMetrics
 	tmi    DistributionMetrics
 	hps    DistributionMetrics
+	ehps   DistributionMetrics
 	tto    DistributionMetrics
 
+	// Only updated for units with a health bar.
+	healingReceived DistributionMetrics
+
 	tmiList   []tmiListItem
 	isTanking bool
 	tmiBin    int32
@@ -100,6 +147,29 @@ type UnitMetrics struct {
 	oomTimeSum   float64
 	actions      map[ActionID]*ActionMetrics
 	resources    []*ResourceMetrics
+	aplActions   []*APLActionMetrics
+
+	// Only set when SimOptions.timeline_bucket_seconds is set.
+	timeline *timelineMetrics
+
+	// DPS against each encounter target. Not tracked for enemy units.
+	targetDps         []DistributionMetrics
+	priorityTargetDps DistributionMetrics
+	deadTargetDps     DistributionMetrics
+
+	// DPS over the time each target, or any target, could be attacked.
+	targetableDps []DistributionMetrics
+	uptimeDps     DistributionMetrics
+
+	// Sum over iterations of the percent of the fight an enemy unit could be
+	// attacked.
+	targetablePercentSum float64
+
+	// Damage taken from enemies, in the order each source ability first hit.
+	damageTaken      []*DamageTakenMetrics
+	damageTakenIndex map[damageTakenKey]int
+
+	mitigation MitigationMetrics
 }
 
 // Metrics for the current iteration, for 1 agent. Keep this as a separate
@@ -160,6 +230,9 @@ type SpellMetrics struct {
 	TotalHealing   float64 // Healing done by all casts of this spell.
 	TotalShielding float64 // Shielding done by all casts of this spell.
 	TotalCastTime  time.Duration
+
+	TotalOverhealing float64 // Healing over the target's max health.
+	TotalAbsorbed    float64 // Damage absorbed by shields from this spell.
 }
 
 type TargetedActionMetrics struct {
@@ -174,30 +247,176 @@ type TargetedActionMetrics struct {
 	Blocks  int32
 	Glances int32
 
-	Damage    float64
-	Threat    float64
-	Healing   float64
-	Shielding float64
-	CastTime  time.Duration
+	Damage      float64
+	Threat      float64
+	Healing     float64
+	Shielding   float64
+	Overhealing float64
+	Absorbed    float64
+	CastTime    time.Duration
 }
 
 func (tam *TargetedActionMetrics) ToProto() *proto.TargetedActionMetrics {
 	return &proto.TargetedActionMetrics{
 		UnitIndex: tam.UnitIndex,
 
-		Casts:      tam.Casts,
-		Hits:       tam.Hits,
-		Crits:      tam.Crits,
-		Misses:     tam.Misses,
-		Dodges:     tam.Dodges,
-		Parries:    tam.Parries,
-		Blocks:     tam.Blocks,
-		Glances:    tam.Glances,
-		Damage:     tam.Damage,
-		Threat:     tam.Threat,
-		Healing:    tam.Healing,
-		Shielding:  tam.Shielding,
-		CastTimeMs: float64(tam.CastTime.Milliseconds()),
+		Casts:       tam.Casts,
+		Hits:        tam.Hits,
+		Crits:       tam.Crits,
+		Misses:      tam.Misses,
+		Dodges:      tam.Dodges,
+		Parries:     tam.Parries,
+		Blocks:      tam.Blocks,
+		Glances:     tam.Glances,
+		Damage:      tam.Damage,
+		Threat:      tam.Threat,
+		Healing:     tam.Healing,
+		Shielding:   tam.Shielding,
+		Overhealing: tam.Overhealing,
+		Absorbed:    tam.Absorbed,
+		CastTimeMs:  float64(tam.CastTime.Milliseconds()),
+	}
+}
+
+func (tam *TargetedActionMetrics) merge(other *TargetedActionMetrics) {
+	tam.Casts += other.Casts
+	tam.Hits += other.Hits
+	tam.Crits += other.Crits
+	tam.Misses += other.Misses
+	tam.Dodges += other.Dodges
+	tam.Parries += other.Parries
+	tam.Blocks += other.Blocks
+	tam.Glances += other.Glances
+	tam.Damage += other.Damage
+	tam.Threat += other.Threat
+	tam.Healing += other.Healing
+	tam.Shielding += other.Shielding
+	tam.Overhealing += other.Overhealing
+	tam.Absorbed += other.Absorbed
+	tam.CastTime += other.CastTime
+}
+
+type damageTakenKey struct {
+	SourceIndex int32
+	ActionID    ActionID
+}
+
+// Damage taken from a single ability of a single enemy, summed over all
+// iterations.
+type DamageTakenMetrics struct {
+	SourceIndex int32
+	ActionID    ActionID
+
+	Hits       int32
+	Crits      int32
+	Crushes    int32
+	Blocks     int32
+	CritBlocks int32
+	Glances    int32
+	Dodges     int32
+	Parries    int32
+	Misses     int32
+
+	Damage    float64
+	Blocked   float64
+	Mitigated float64
+}
+
+func (dtm *DamageTakenMetrics) addResult(result *SpellResult) {
+	switch outcome := result.Outcome; {
+	case outcome.Matches(OutcomeMiss):
+		dtm.Misses++
+	case outcome.Matches(OutcomeDodge):
+		dtm.Dodges++
+	case outcome.Matches(OutcomeParry):
+		dtm.Parries++
+	case outcome.Matches(OutcomeBlock) && (result.criticalBlock || outcome.Matches(OutcomeCrit)):
+		dtm.CritBlocks++
+	case outcome.Matches(OutcomeBlock):
+		dtm.Blocks++
+	case outcome.Matches(OutcomeCrush):
+		dtm.Crushes++
+	case outcome.Matches(OutcomeCrit):
+		dtm.Crits++
+	case outcome.Matches(OutcomeGlance):
+		dtm.Glances++
+	case outcome.Matches(OutcomeHit):
+		dtm.Hits++
+	}
+
+	dtm.Damage += result.Damage
+	dtm.Blocked += result.Blocked
+	dtm.Mitigated += result.mitigatedDamage()
+}
+
+func (dtm *DamageTakenMetrics) merge(other *DamageTakenMetrics) {
+	dtm.Hits += other.Hits
+	dtm.Crits += other.Crits
+	dtm.Crushes += other.Crushes
+	dtm.Blocks += other.Blocks
+	dtm.CritBlocks += other.CritBlocks
+	dtm.Glances += other.Glances
+	dtm.Dodges += other.Dodges
+	dtm.Parries += other.Parries
+	dtm.Misses += other.Misses
+	dtm.Damage += other.Damage
+	dtm.Blocked += other.Blocked
+	dtm.Mitigated += other.Mitigated
+}
+
+func (dtm *DamageTakenMetrics) ToProto() *proto.DamageTakenMetrics {
+	return &proto.DamageTakenMetrics{
+		Id:          dtm.ActionID.ToProto(),
+		SourceIndex: dtm.SourceIndex,
+
+		Hits:       dtm.Hits,
+		Crits:      dtm.Crits,
+		Crushes:    dtm.Crushes,
+		Blocks:     dtm.Blocks,
+		CritBlocks: dtm.CritBlocks,
+		Glances:    dtm.Glances,
+		Dodges:     dtm.Dodges,
+		Parries:    dtm.Parries,
+		Misses:     dtm.Misses,
+		Damage:     dtm.Damage,
+		Blocked:    dtm.Blocked,
+		Mitigated:  dtm.Mitigated,
+	}
+}
+
+// Damage prevented for a unit, by mechanism. Values are totals over all
+// iterations.
+type MitigationMetrics struct {
+	Armor       float64
+	Resistances float64
+	Blocked     float64
+	Absorbed    float64 // Recorded separately, by Unit.AddAbsorb().
+}
+
+func (mm *MitigationMetrics) addResult(spell *Spell, result *SpellResult) {
+	if resisted := result.resistedDamage(); resisted > 0 {
+		if spell.SpellSchool.Matches(SpellSchoolPhysical) {
+			mm.Armor += resisted
+		} else {
+			mm.Resistances += resisted
+		}
+	}
+	mm.Blocked += result.Blocked
+}
+
+func (mm *MitigationMetrics) merge(other *MitigationMetrics) {
+	mm.Armor += other.Armor
+	mm.Resistances += other.Resistances
+	mm.Blocked += other.Blocked
+	mm.Absorbed += other.Absorbed
+}
+
+func (mm *MitigationMetrics) ToProto() *proto.MitigationMetrics {
+	return &proto.MitigationMetrics{
+		Armor:       mm.Armor,
+		Resistances: mm.Resistances,
+		Blocked:     mm.Blocked,
+		Absorbed:    mm.Absorbed,
 	}
 }
 
@@ -209,8 +428,44 @@ func NewUnitMetrics() UnitMetrics {
 		dtps:    NewDistributionMetrics(),
 		tmi:     NewDistributionMetrics(),
 		hps:     NewDistributionMetrics(),
+		ehps:    NewDistributionMetrics(),
 		tto:     NewDistributionMetrics(),
 		actions: make(map[ActionID]*ActionMetrics),
+
+		healingReceived: NewDistributionMetrics(),
+
+		priorityTargetDps: NewDistributionMetrics(),
+		deadTargetDps:     NewDistributionMetrics(),
+		uptimeDps:         NewDistributionMetrics(),
+
+		damageTakenIndex: make(map[damageTakenKey]int),
+	}
+}
+
+func (unitMetrics *UnitMetrics) getDamageTakenMetrics(key damageTakenKey) *DamageTakenMetrics {
+	index, ok := unitMetrics.damageTakenIndex[key]
+	if !ok {
+		index = len(unitMetrics.damageTaken)
+		unitMetrics.damageTakenIndex[key] = index
+		unitMetrics.damageTaken = append(unitMetrics.damageTaken, &DamageTakenMetrics{
+			SourceIndex: key.SourceIndex,
+			ActionID:    key.ActionID,
+		})
+	}
+	return unitMetrics.damageTaken[index]
+}
+
+// Adds damage taken from an enemy spell, including avoided attacks.
+func (unitMetrics *UnitMetrics) addDamageTaken(spell *Spell, result *SpellResult) {
+	unitMetrics.getDamageTakenMetrics(damageTakenKey{SourceIndex: spell.Unit.Index, ActionID: spell.ActionID}).addResult(result)
+}
+
+func (unitMetrics *UnitMetrics) initTargetDps(numTargets int) {
+	unitMetrics.targetDps = make([]DistributionMetrics, numTargets)
+	unitMetrics.targetableDps = make([]DistributionMetrics, numTargets)
+	for i := range unitMetrics.targetDps {
+		unitMetrics.targetDps[i] = NewDistributionMetrics()
+		unitMetrics.targetableDps[i] = NewDistributionMetrics()
 	}
 }
 
@@ -329,6 +584,8 @@ func (unitMetrics *UnitMetrics) addSpellMetrics(spell *Spell, actionID ActionID,
 		tam.Threat += spellTargetMetrics.TotalThreat
 		tam.Healing += spellTargetMetrics.TotalHealing
 		tam.Shielding += spellTargetMetrics.TotalShielding
+		tam.Overhealing += spellTargetMetrics.TotalOverhealing
+		tam.Absorbed += spellTargetMetrics.TotalAbsorbed
 		tam.CastTime += spellTargetMetrics.TotalCastTime
 
 		target := spell.Unit.AttackTables[i].Defender
@@ -337,8 +594,12 @@ func (unitMetrics *UnitMetrics) addSpellMetrics(spell *Spell, actionID ActionID,
 		if spell.Unit.IsOpponent(target) {
 			unitMetrics.dps.Total += spellTargetMetrics.TotalDamage
 			unitMetrics.threat.Total += spellTargetMetrics.TotalThreat
+			if target.Type == EnemyUnit && int(target.Index) < len(unitMetrics.targetDps) {
+				unitMetrics.targetDps[target.Index].Total += spellTargetMetrics.TotalDamage
+			}
 		} else {
 			unitMetrics.hps.Total += spellTargetMetrics.TotalHealing + spellTargetMetrics.TotalShielding
+			unitMetrics.ehps.Total += spellTargetMetrics.TotalHealing - spellTargetMetrics.TotalOverhealing + spellTargetMetrics.TotalAbsorbed
 		}
 	}
 }
@@ -348,6 +609,9 @@ func (unitMetrics *UnitMetrics) addSpellMetrics(spell *Spell, actionID ActionID,
 // Assumes that doneIteration() has already been called on the pet metrics.
 func (unitMetrics *UnitMetrics) AddFinalPetMetrics(petMetrics *UnitMetrics) {
 	unitMetrics.dps.Total += petMetrics.dps.Total
+	for i := range petMetrics.targetDps {
+		unitMetrics.targetDps[i].Total += petMetrics.targetDps[i].Total
+	}
 }
 
 func (unitMetrics *UnitMetrics) AddOOMTime(sim *Simulation, dur time.Duration) {
@@ -374,12 +638,24 @@ func (unitMetrics *UnitMetrics) reset() {
 	unitMetrics.tmi.reset()
 	unitMetrics.tmiList = nil
 	unitMetrics.hps.reset()
+	unitMetrics.ehps.reset()
+	unitMetrics.healingReceived.reset()
 	unitMetrics.tto.reset()
+	for i := range unitMetrics.targetDps {
+		unitMetrics.targetDps[i].reset()
+		unitMetrics.targetableDps[i].reset()
+	}
+	unitMetrics.priorityTargetDps.reset()
+	unitMetrics.deadTargetDps.reset()
+	unitMetrics.uptimeDps.reset()
 	unitMetrics.CharacterIterationMetrics = CharacterIterationMetrics{}
 
 	for _, resourceMetrics := range unitMetrics.resources {
 		resourceMetrics.reset()
 	}
+	for _, aplActionMetrics := range unitMetrics.aplActions {
+		aplActionMetrics.reset()
+	}
 }
 
 // This should be called when a Sim iteration is complete.
@@ -418,12 +694,123 @@ func (unitMetrics *UnitMetrics) doneIteration(unit *Unit, sim *Simulation) {
 	unitMetrics.dtps.doneIteration(sim)
 	unitMetrics.tmi.doneIteration(sim)
 	unitMetrics.hps.doneIteration(sim)
+	unitMetrics.ehps.doneIteration(sim)
+	unitMetrics.healingReceived.doneIteration(sim)
 	unitMetrics.tto.doneIteration(sim)
 
+	if len(unitMetrics.targetDps) > 0 {
+		for i := range unitMetrics.targetDps {
+			targetDps := &unitMetrics.targetDps[i]
+			if int32(i) == sim.Encounter.PriorityTargetIndex {
+				unitMetrics.priorityTargetDps.Total += targetDps.Total
+			}
+			if sim.Encounter.Targets[i].HealthDepleted() {
+				unitMetrics.deadTargetDps.Total += targetDps.Total
+			}
+
+			// Hack because of the way DistributionMetrics does its calculations.
+			if targetable := sim.Encounter.Targets[i].TargetableTime(sim); targetable > 0 {
+				unitMetrics.targetableDps[i].Total = targetDps.Total * sim.Duration.Seconds() / targetable.Seconds()
+			}
+			targetDps.doneIteration(sim)
+			unitMetrics.targetableDps[i].doneIteration(sim)
+		}
+		unitMetrics.priorityTargetDps.doneIteration(sim)
+		unitMetrics.deadTargetDps.doneIteration(sim)
+
+		if uptime := sim.Encounter.UptimeDuration(sim); uptime > 0 {
+			unitMetrics.uptimeDps.Total = unitMetrics.dps.Total * sim.Duration.Seconds() / uptime.Seconds()
+		}
+		unitMetrics.uptimeDps.doneIteration(sim)
+	}
+
+	if unit.Type == EnemyUnit {
+		unitMetrics.targetablePercentSum += 100 * sim.Encounter.Targets[unit.Index].TargetableTime(sim).Seconds() / sim.Duration.Seconds()
+	}
+
 	unitMetrics.oomTimeSum += unitMetrics.OOMTime.Seconds()
 	if unitMetrics.Died {
 		unitMetrics.numItersDead++
 	}
+
+	for _, aplActionMetrics := range unitMetrics.aplActions {
+		if aplActionMetrics.enabled {
+			aplActionMetrics.doneIteration()
+		}
+	}
+
+	if unitMetrics.timeline != nil {
+		unitMetrics.timeline.doneIteration(sim)
+	}
+}
+
+// Adds the aggregate values of another UnitMetrics, for the same unit in a
+// Simulation built from the same request.
+func (unitMetrics *UnitMetrics) merge(other *UnitMetrics) {
+	unitMetrics.dps.merge(&other.dps)
+	unitMetrics.dpasp.merge(&other.dpasp)
+	unitMetrics.threat.merge(&other.threat)
+	unitMetrics.dtps.merge(&other.dtps)
+	unitMetrics.tmi.merge(&other.tmi)
+	unitMetrics.hps.merge(&other.hps)
+	unitMetrics.ehps.merge(&other.ehps)
+	unitMetrics.healingReceived.merge(&other.healingReceived)
+	unitMetrics.tto.merge(&other.tto)
+	for i := range unitMetrics.targetDps {
+		unitMetrics.targetDps[i].merge(&other.targetDps[i])
+		unitMetrics.targetableDps[i].merge(&other.targetableDps[i])
+	}
+	unitMetrics.priorityTargetDps.merge(&other.priorityTargetDps)
+	unitMetrics.deadTargetDps.merge(&other.deadTargetDps)
+	unitMetrics.uptimeDps.merge(&other.uptimeDps)
+	unitMetrics.targetablePercentSum += other.targetablePercentSum
+	for _, otherDamageTaken := range other.damageTaken {
+		key := damageTakenKey{SourceIndex: otherDamageTaken.SourceIndex, ActionID: otherDamageTaken.ActionID}
+		unitMetrics.getDamageTakenMetrics(key).merge(otherDamageTaken)
+	}
+	unitMetrics.mitigation.merge(&other.mitigation)
+
+	unitMetrics.oomTimeSum += other.oomTimeSum
+	unitMetrics.numItersDead += other.numItersDead
+
+	for actionID, otherAction := range other.actions {
+		actionMetrics, ok := unitMetrics.actions[actionID]
+		if !ok || len(actionMetrics.Targets) == 0 {
+			unitMetrics.actions[actionID] = otherAction
+			continue
+		}
+		for i := range otherAction.Targets {
+			actionMetrics.Targets[i].merge(&otherAction.Targets[i])
+		}
+	}
+
+	// Some resource metrics are only created when first needed, so match them
+	// by key, in creation order for duplicates.
+	resourcesByKey := make(map[ResourceKey][]*ResourceMetrics)
+	for _, resourceMetrics := range unitMetrics.resources {
+		key := ResourceKey{ActionID: resourceMetrics.ActionID, Type: resourceMetrics.Type}
+		resourcesByKey[key] = append(resourcesByKey[key], resourceMetrics)
+	}
+	for _, otherResource := range other.resources {
+		key := ResourceKey{ActionID: otherResource.ActionID, Type: otherResource.Type}
+		if len(resourcesByKey[key]) == 0 {
+			unitMetrics.resources = append(unitMetrics.resources, otherResource)
+			continue
+		}
+		resourceMetrics := resourcesByKey[key][0]
+		resourcesByKey[key] = resourcesByKey[key][1:]
+		resourceMetrics.Events += otherResource.Events
+		resourceMetrics.Gain += otherResource.Gain
+		resourceMetrics.ActualGain += otherResource.ActualGain
+	}
+
+	for i, aplActionMetrics := range unitMetrics.aplActions {
+		aplActionMetrics.merge(other.aplActions[i])
+	}
+
+	if unitMetrics.timeline != nil {
+		unitMetrics.timeline.merge(other.timeline)
+	}
 }
 
 func (unitMetrics *UnitMetrics) calculateTMI(unit *Unit, sim *Simulation) float64 {
@@ -508,9 +895,15 @@ func (unitMetrics *UnitMetrics) ToProto() *proto.UnitMetrics {
 		Dtps:          unitMetrics.dtps.ToProto(),
 		Tmi:           unitMetrics.tmi.ToProto(),
 		Hps:           unitMetrics.hps.ToProto(),
+		Ehps:          unitMetrics.ehps.ToProto(),
 		Tto:           unitMetrics.tto.ToProto(),
+		Mitigation:    unitMetrics.mitigation.ToProto(),
 		SecondsOomAvg: unitMetrics.oomTimeSum / n,
 		ChanceOfDeath: float64(unitMetrics.numItersDead) / n,
+
+		HealingReceived: unitMetrics.healingReceived.ToProto(),
+
+		TargetablePercent: unitMetrics.targetablePercentSum / n,
 	}
 
 	for actionID, action := range unitMetrics.actions {
@@ -521,6 +914,29 @@ func (unitMetrics *UnitMetrics) ToProto() *proto.UnitMetrics {
 			protoMetrics.Resources = append(protoMetrics.Resources, resource.ToProto())
 		}
 	}
+	for _, aplAction := range unitMetrics.aplActions {
+		if aplAction.n > 0 {
+			protoMetrics.AplActions = append(protoMetrics.AplActions, aplAction.ToProto())
+		}
+	}
+	if unitMetrics.timeline != nil {
+		protoMetrics.Timeline = unitMetrics.timeline.ToProto()
+	}
+	if len(unitMetrics.targetDps) > 0 {
+		for i := range unitMetrics.targetDps {
+			protoMetrics.TargetDps = append(protoMetrics.TargetDps, &proto.TargetDpsMetrics{
+				UnitIndex:     int32(i),
+				Dps:           unitMetrics.targetDps[i].ToProto(),
+				TargetableDps: unitMetrics.targetableDps[i].ToProto(),
+			})
+		}
+		protoMetrics.PriorityTargetDps = unitMetrics.priorityTargetDps.ToProto()
+		protoMetrics.DeadTargetDps = unitMetrics.deadTargetDps.ToProto()
+		protoMetrics.UptimeDps = unitMetrics.uptimeDps.ToProto()
+	}
+	for _, damageTaken := range unitMetrics.damageTaken {
+		protoMetrics.DamageTaken = append(protoMetrics.DamageTaken, damageTaken.ToProto())
+	}
 
 	return protoMetrics
 }
@@ -536,7 +952,7 @@ type AuraMetrics struct {
 	n           int
 	uptimeSum   float64
 	uptimeSumSq float64
-	procsSum    int32
+	procsSum    int64
 }
 
 func (auraMetrics *AuraMetrics) reset() {
@@ -549,7 +965,14 @@ func (auraMetrics *AuraMetrics) doneIteration() {
 	auraMetrics.n++
 	auraMetrics.uptimeSum += auraMetrics.Uptime.Seconds()
 	auraMetrics.uptimeSumSq += math.Pow(auraMetrics.Uptime.Seconds(), 2)
-	auraMetrics.procsSum += auraMetrics.Procs
+	auraMetrics.procsSum += int64(auraMetrics.Procs)
+}
+
+func (auraMetrics *AuraMetrics) merge(other *AuraMetrics) {
+	auraMetrics.n += other.n
+	auraMetrics.uptimeSum += other.uptimeSum
+	auraMetrics.uptimeSumSq += other.uptimeSumSq
+	auraMetrics.procsSum += other.procsSum
 }
 
 func (auraMetrics *AuraMetrics) ToProto() *proto.AuraMetrics {
@@ -564,6 +987,90 @@ func (auraMetrics *AuraMetrics) ToProto() *proto.AuraMetrics {
 	}
 }
 
+type APLActionMetrics struct {
+	Location string
+	ActionID ActionID
+
+	// Whether metrics are being recorded, see SimOptions.trace_apl.
+	enabled bool
+
+	// Metrics for the current iteration.
+	Evaluations   int32
+	ConditionTrue int32
+	Executions    int32
+	FirstUsed     time.Duration
+	LastUsed      time.Duration
+
+	// Aggregate values. These are updated after each iteration.
+	n                int
+	evaluationsSum   int64
+	conditionTrueSum int64
+	executionsSum    int64
+	usedIterations   int64
+	firstUsedSum     float64
+	lastUsedSum      float64
+}
+
+func (aplActionMetrics *APLActionMetrics) reset() {
+	aplActionMetrics.Evaluations = 0
+	aplActionMetrics.ConditionTrue = 0
+	aplActionMetrics.Executions = 0
+	aplActionMetrics.FirstUsed = 0
+	aplActionMetrics.LastUsed = 0
+}
+
+func (aplActionMetrics *APLActionMetrics) executed(sim *Simulation) {
+	if aplActionMetrics.Executions == 0 {
+		aplActionMetrics.FirstUsed = sim.CurrentTime
+	}
+	aplActionMetrics.LastUsed = sim.CurrentTime
+	aplActionMetrics.Executions++
+}
+
+// This should be called when a Sim iteration is complete.
+func (aplActionMetrics *APLActionMetrics) doneIteration() {
+	aplActionMetrics.n++
+	aplActionMetrics.evaluationsSum += int64(aplActionMetrics.Evaluations)
+	aplActionMetrics.conditionTrueSum += int64(aplActionMetrics.ConditionTrue)
+	aplActionMetrics.executionsSum += int64(aplActionMetrics.Executions)
+	if aplActionMetrics.Executions > 0 {
+		aplActionMetrics.usedIterations++
+		aplActionMetrics.firstUsedSum += aplActionMetrics.FirstUsed.Seconds()
+		aplActionMetrics.lastUsedSum += aplActionMetrics.LastUsed.Seconds()
+	}
+}
+
+func (aplActionMetrics *APLActionMetrics) merge(other *APLActionMetrics) {
+	aplActionMetrics.n += other.n
+	aplActionMetrics.evaluationsSum += other.evaluationsSum
+	aplActionMetrics.conditionTrueSum += other.conditionTrueSum
+	aplActionMetrics.executionsSum += other.executionsSum
+	aplActionMetrics.usedIterations += other.usedIterations
+	aplActionMetrics.firstUsedSum += other.firstUsedSum
+	aplActionMetrics.lastUsedSum += other.lastUsedSum
+}
+
+func (aplActionMetrics *APLActionMetrics) ToProto() *proto.APLActionMetrics {
+	n := float64(aplActionMetrics.n)
+	protoMetrics := &proto.APLActionMetrics{
+		Location: aplActionMetrics.Location,
+
+		EvaluationsAvg:      float64(aplActionMetrics.evaluationsSum) / n,
+		ConditionTrueAvg:    float64(aplActionMetrics.conditionTrueSum) / n,
+		ExecutionsAvg:       float64(aplActionMetrics.executionsSum) / n,
+		UsedIterationsRatio: float64(aplActionMetrics.usedIterations) / n,
+	}
+	if !aplActionMetrics.ActionID.IsEmptyAction() {
+		protoMetrics.ActionId = aplActionMetrics.ActionID.ToProto()
+	}
+	if aplActionMetrics.usedIterations > 0 {
+		used := float64(aplActionMetrics.usedIterations)
+		protoMetrics.FirstUsedSecondsAvg = aplActionMetrics.firstUsedSum / used
+		protoMetrics.LastUsedSecondsAvg = aplActionMetrics.lastUsedSum / used
+	}
+	return protoMetrics
+}
+
 // Calculates DPS for an action.
 func GetActionDPS(playerMetrics *proto.UnitMetrics, iterations int32, duration time.Duration, actionID ActionID, ignoreTag bool) float64 {
 	totalDPS := 0.0
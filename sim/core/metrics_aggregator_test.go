@@ -0,0 +1,192 @@
+package core
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+	"github.com/wowsims/wotlk/sim/core/stats"
+)
+
+func TestTargetDpsMetrics(t *testing.T) {
+	// Shocks every 1.5s: 1 at the first target, which dies right away, 2 at
+	// the second and the remaining 8, including one at the end of the fight,
+	// at the third.
+	rotation := `actions+=/cast_spell,id=2,target={type=SelectTargetIndex,target_index=0},if=current_time<1s
+actions+=/cast_spell,id=2,target={type=SelectTargetIndex,target_index=1},if=current_time<4s
+actions+=/cast_spell,id=2,target={type=SelectTargetIndex,target_index=2}
+`
+	rsr := newTestRaidSimRequest([]*proto.Player{newTestCaster("Caster", rotation)}, &proto.Encounter{
+		Duration:            15,
+		Targets:             []*proto.Target{newTestTarget(1), newTestTarget(0), newTestTarget(0)},
+		PriorityTargetIndex: 2,
+	}, 10)
+	rsr.SimOptions.NumWorkers = 2
+
+	player := runTestRaidSim(t, rsr).RaidMetrics.Parties[0].Players[0]
+
+	expectedDps := []float64{500.0 / 15, 1000.0 / 15, 4000.0 / 15}
+	if len(player.TargetDps) != len(expectedDps) {
+		t.Fatalf("Expected dps for %d targets, got %d", len(expectedDps), len(player.TargetDps))
+	}
+	for i, targetDps := range player.TargetDps {
+		if math.Abs(targetDps.Dps.Avg-expectedDps[i]) > 0.001 {
+			t.Fatalf("Expected %0.3f dps to target %d, got %0.3f", expectedDps[i], i, targetDps.Dps.Avg)
+		}
+	}
+	if math.Abs(player.Dps.Avg-5500.0/15) > 0.001 {
+		t.Fatalf("Expected %0.3f dps, got %0.3f", 5500.0/15, player.Dps.Avg)
+	}
+	if math.Abs(player.PriorityTargetDps.Avg-expectedDps[2]) > 0.001 {
+		t.Fatalf("Expected %0.3f priority target dps, got %0.3f", expectedDps[2], player.PriorityTargetDps.Avg)
+	}
+	if math.Abs(player.DeadTargetDps.Avg-expectedDps[0]) > 0.001 {
+		t.Fatalf("Expected %0.3f dead target dps, got %0.3f", expectedDps[0], player.DeadTargetDps.Avg)
+	}
+
+	rsr.Encounter.PriorityTargetIndex = 3
+	if result := RunRaidSim(rsr); result.ErrorResult == "" {
+		t.Fatalf("Expected an error for an invalid priority target")
+	}
+}
+
+func TestDamageTakenMetrics(t *testing.T) {
+	tank := newTestTank("Tank", stats.Stats{
+		stats.Dodge:      10 * DodgeRatingPerDodgeChance,
+		stats.Parry:      10 * ParryRatingPerParryChance,
+		stats.Block:      20 * BlockRatingPerBlockChance,
+		stats.BlockValue: 500,
+		stats.Armor:      10000,
+	})
+	rsr := newTestRaidSimRequest([]*proto.Player{tank}, &proto.Encounter{
+		Duration: 60,
+		Targets:  []*proto.Target{newTestAttackingTarget(1000)},
+	}, 20)
+
+	player := runTestRaidSim(t, rsr).RaidMetrics.Parties[0].Players[0]
+	if len(player.DamageTaken) != 1 || player.DamageTaken[0].SourceIndex != 0 || player.DamageTaken[0].Id.GetOtherId() != proto.OtherAction_OtherActionAttack {
+		t.Fatalf("Expected damage taken from the target's melee only, got %v", player.DamageTaken)
+	}
+	damageTaken := player.DamageTaken[0]
+
+	// The target swings every 2s, including at the start and end of the fight.
+	swings := damageTaken.Hits + damageTaken.Crits + damageTaken.Crushes + damageTaken.Blocks + damageTaken.CritBlocks +
+		damageTaken.Glances + damageTaken.Dodges + damageTaken.Parries + damageTaken.Misses
+	if expected := 31 * rsr.SimOptions.Iterations; swings != expected {
+		t.Fatalf("Expected %d swings, got %d", expected, swings)
+	}
+	if damageTaken.Hits == 0 || damageTaken.Dodges == 0 || damageTaken.Parries == 0 || damageTaken.Blocks == 0 {
+		t.Fatalf("Expected hits, dodges, parries and blocks, got %v", damageTaken)
+	}
+
+	dtps := damageTaken.Damage / float64(rsr.SimOptions.Iterations) / rsr.Encounter.Duration
+	if math.Abs(dtps-player.Dtps.Avg) > 0.001 {
+		t.Fatalf("Damage taken adds up to %0.3f dtps, expected %0.3f", dtps, player.Dtps.Avg)
+	}
+	if expected := 500 * float64(damageTaken.Blocks); damageTaken.Blocked != expected {
+		t.Fatalf("Expected %0.3f blocked damage, got %0.3f", expected, damageTaken.Blocked)
+	}
+	if expected := player.Mitigation.Armor + damageTaken.Blocked; math.Abs(damageTaken.Mitigated-expected) > 0.001 {
+		t.Fatalf("Expected %0.3f mitigated damage, got %0.3f", expected, damageTaken.Mitigated)
+	}
+}
+
+func TestMitigationMetrics(t *testing.T) {
+	newRequest := func(rotation string) *proto.RaidSimRequest {
+		tank := newTestTank("Tank", stats.Stats{
+			stats.Block:      20 * BlockRatingPerBlockChance,
+			stats.BlockValue: 500,
+			stats.Armor:      10000,
+		})
+		tank.Rotation = APLRotationFromTextString(rotation)
+		return newTestRaidSimRequest([]*proto.Player{tank}, &proto.Encounter{
+			Duration: 60,
+			Targets:  []*proto.Target{newTestAttackingTarget(1000)},
+		}, 20)
+	}
+
+	// A single shield, which absorbs 3000 of the damage taken.
+	rsr := newRequest("actions+=/cast_spell,id=5,if=current_time<1s\n")
+	player := runTestRaidSim(t, rsr).RaidMetrics.Parties[0].Players[0]
+	mitigation := player.Mitigation
+	if mitigation.Armor <= 0 || mitigation.Blocked <= 0 || mitigation.Resistances != 0 {
+		t.Fatalf("Expected armor and block mitigation, got %v", mitigation)
+	}
+	if expected := 3000 * float64(rsr.SimOptions.Iterations); math.Abs(mitigation.Absorbed-expected) > 0.001 {
+		t.Fatalf("Expected %0.3f absorbed damage, got %0.3f", expected, mitigation.Absorbed)
+	}
+
+	blocked, mitigated := 0.0, 0.0
+	for _, damageTaken := range player.DamageTaken {
+		blocked += damageTaken.Blocked
+		mitigated += damageTaken.Mitigated
+	}
+	if math.Abs(blocked-mitigation.Blocked) > 0.001 {
+		t.Fatalf("Blocked damage adds up to %0.3f, expected %0.3f", blocked, mitigation.Blocked)
+	}
+	if expected := mitigation.Armor + mitigation.Blocked; math.Abs(mitigated-expected) > 0.001 {
+		t.Fatalf("Mitigated damage adds up to %0.3f, expected %0.3f", mitigated, expected)
+	}
+
+	// The shield's absorbs count as effective healing.
+	if math.Abs(player.Ehps.Avg-50) > 0.001 {
+		t.Fatalf("Expected 50 ehps, got %0.3f", player.Ehps.Avg)
+	}
+	absorbed := 0.0
+	for _, action := range player.Actions {
+		for _, target := range action.Targets {
+			absorbed += target.Absorbed
+		}
+	}
+	if math.Abs(absorbed-mitigation.Absorbed) > 0.001 {
+		t.Fatalf("Absorbed damage adds up to %0.3f, expected %0.3f", absorbed, mitigation.Absorbed)
+	}
+
+	// Shields only count for metrics, and don't change the damage taken.
+	withoutShield := runTestRaidSim(t, newRequest("actions+=/cast_spell,id=5,if=current_time<0s\n")).RaidMetrics.Parties[0].Players[0]
+	if withoutShield.Dtps.Avg != player.Dtps.Avg {
+		t.Fatalf("Expected the shield to leave dtps at %0.3f, got %0.3f", withoutShield.Dtps.Avg, player.Dtps.Avg)
+	}
+}
+
+func TestHealingReceivedMetrics(t *testing.T) {
+	rsr := newTestRaidSimRequest([]*proto.Player{newTestHealer("Healer")}, &proto.Encounter{
+		Duration: 60,
+		Targets:  []*proto.Target{newTestTarget(0)},
+	}, 5)
+	// The dummy takes more damage than it is healed for, so only the heal
+	// before the first hit overheals.
+	rsr.Raid.TargetDummies = 1
+	rsr.Raid.TargetDummyDamage = &proto.TargetDummyDamageModel{
+		Health:         20000,
+		Dtps:           3000,
+		CadenceSeconds: 1,
+	}
+
+	players := runTestRaidSim(t, rsr).RaidMetrics.Parties[0].Players
+	healer, dummy := players[0], players[1]
+
+	// A 1000 heal every 1.5s, including at the end of the fight.
+	healing, overhealing := 0.0, 0.0
+	for _, action := range healer.Actions {
+		for _, target := range action.Targets {
+			if target.UnitIndex == dummy.UnitIndex {
+				healing += target.Healing
+				overhealing += target.Overhealing
+			}
+		}
+	}
+	if expected := 41000 * float64(rsr.SimOptions.Iterations); healing != expected {
+		t.Fatalf("Expected %0.3f healing, got %0.3f", expected, healing)
+	}
+	if expected := 1000 * float64(rsr.SimOptions.Iterations); overhealing != expected {
+		t.Fatalf("Expected %0.3f overhealing, got %0.3f", expected, overhealing)
+	}
+
+	if math.Abs(healer.Hps.Avg-41000.0/60) > 0.001 || math.Abs(healer.Ehps.Avg-40000.0/60) > 0.001 {
+		t.Fatalf("Expected %0.3f hps and %0.3f ehps, got %0.3f and %0.3f", 41000.0/60, 40000.0/60, healer.Hps.Avg, healer.Ehps.Avg)
+	}
+	if math.Abs(dummy.HealingReceived.Avg-40000.0/60) > 0.001 {
+		t.Fatalf("Expected %0.3f healing received, got %0.3f", 40000.0/60, dummy.HealingReceived.Avg)
+	}
+}
@@ -0,0 +1,189 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// An encounter movement event, with the raid members it applies to.
+type movementEvent struct {
+	config *proto.MovementEvent
+	units  []*Unit
+}
+
+func newMovementEvents(configs []*proto.MovementEvent, raid *Raid) []*movementEvent {
+	var events []*movementEvent
+	for _, config := range configs {
+		if config.StartSeconds < 0 || config.DurationSeconds <= 0 || config.PeriodSeconds < 0 {
+			panic(fmt.Sprintf("Invalid movement at %0.2fs for %0.2fs, every %0.2fs", config.StartSeconds, config.DurationSeconds, config.PeriodSeconds))
+		}
+
+		event := &movementEvent{
+			config: config,
+		}
+		if len(config.Players) == 0 {
+			for _, party := range raid.Parties {
+				for _, player := range party.Players {
+					event.units = append(event.units, &player.GetCharacter().Unit)
+				}
+			}
+		} else {
+			for _, raidTarget := range config.Players {
+				player := raid.GetPlayerFromRaidTarget(raidTarget)
+				if player == nil {
+					panic(fmt.Sprintf("Invalid raid index %d for movement", raidTarget.TargetIndex))
+				}
+				event.units = append(event.units, &player.GetCharacter().Unit)
+			}
+		}
+
+		for _, unit := range event.units {
+			unit.movementEvents = append(unit.movementEvents, config)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func (event *movementEvent) schedule(sim *Simulation) {
+	duration := DurationFromSeconds(event.config.DurationSeconds)
+	period := DurationFromSeconds(event.config.PeriodSeconds)
+
+	var startMoving func(sim *Simulation)
+	startMoving = func(sim *Simulation) {
+		for _, unit := range event.units {
+			unit.StartMoving(sim)
+		}
+		StartDelayedAction(sim, DelayedActionOptions{
+			DoAt:     sim.CurrentTime + duration,
+			Priority: ActionPriorityDOT,
+			OnAction: func(sim *Simulation) {
+				for _, unit := range event.units {
+					unit.EndMoving(sim)
+				}
+			},
+		})
+		if period > 0 {
+			StartDelayedAction(sim, DelayedActionOptions{
+				DoAt:     sim.CurrentTime + period,
+				Priority: ActionPriorityDOT,
+				OnAction: startMoving,
+			})
+		}
+	}
+
+	StartDelayedAction(sim, DelayedActionOptions{
+		DoAt: DurationFromSeconds(event.config.StartSeconds),
+		// Ahead of rotations at the same time, so they see the movement.
+		Priority: ActionPriorityDOT,
+		OnAction: startMoving,
+	})
+}
+
+// Whether the unit has to move, so it can't hard-cast or auto attack.
+func (unit *Unit) IsMoving() bool {
+	return unit.moving > 0
+}
+
+// Makes the unit move until a matching call to EndMoving. Calls may overlap,
+// for overlapping movement events.
+func (unit *Unit) StartMoving(sim *Simulation) {
+	unit.moving++
+	if unit.moving > 1 {
+		return
+	}
+
+	if sim.Log != nil {
+		unit.Log(sim, "Started moving")
+	}
+	unit.interruptCast(sim)
+	unit.AutoAttacks.CancelAutoSwing(sim)
+}
+
+func (unit *Unit) EndMoving(sim *Simulation) {
+	if unit.moving == 0 {
+		panic("EndMoving without StartMoving for " + unit.Label)
+	}
+	unit.moving--
+	if unit.moving > 0 {
+		return
+	}
+
+	if sim.Log != nil {
+		unit.Log(sim, "Stopped moving")
+	}
+	if unit.AutoAttacks.IsEnabled() {
+		unit.AutoAttacks.EnableAutoSwing(sim)
+	}
+
+	// Casts blocked by movement may be possible again.
+	unit.wakeUpRotation(sim)
+}
+
+// Stops the cast or channel in progress, without applying its effects.
+func (unit *Unit) interruptCast(sim *Simulation) {
+	hc := unit.Hardcast
+	// Waits from HardcastWaitUntil aren't casts.
+	if hc.Expires <= sim.CurrentTime || hc.ActionID.IsEmptyAction() {
+		return
+	}
+
+	if sim.Log != nil {
+		unit.Log(sim, "Interrupted cast of %s", hc.ActionID)
+	}
+
+	if spell := unit.GetSpell(hc.ActionID); spell != nil {
+		if hc.OnComplete == nil {
+			// Channels deal their effects with a dot while they last.
+			if dot := spell.AOEDot(); dot != nil {
+				dot.Cancel(sim)
+			} else if len(spell.dots) > 0 && hc.Target != nil {
+				spell.Dot(hc.Target).Cancel(sim)
+			}
+		} else {
+			// Hardcasts only pay their cost once they complete, so nothing was
+			// spent. Cooldowns start with the cast though, and had to be ready
+			// for it to begin, so reset them to match.
+			if spell.CD.Timer != nil {
+				spell.CD.Reset()
+			}
+			if spell.SharedCD.Timer != nil {
+				spell.SharedCD.Reset()
+			}
+		}
+	}
+
+	unit.Hardcast = Hardcast{Expires: startingCDTime}
+	if unit.hardcastAction != nil {
+		unit.hardcastAction.Cancel(sim)
+	}
+	unit.SetGCDTimer(sim, sim.CurrentTime)
+}
+
+// Spells with a cast time or channel can't be cast while moving.
+func (spell *Spell) castBlockedByMovement(cast Cast) bool {
+	return spell.Unit.moving > 0 && (cast.ChannelTime > 0 || spell.Unit.ApplyCastSpeedForSpell(cast.CastTime, spell) > 0)
+}
+
+// Time until the unit next has to move, 0 while moving, or the remaining
+// fight duration if it doesn't have to move again.
+func (unit *Unit) TimeUntilMove(sim *Simulation) time.Duration {
+	if unit.IsMoving() {
+		return 0
+	}
+
+	timeUntilMove := MaxDuration(0, sim.GetRemainingDuration())
+	for _, config := range unit.movementEvents {
+		startAt := DurationFromSeconds(config.StartSeconds)
+		if startAt < sim.CurrentTime && config.PeriodSeconds > 0 {
+			period := DurationFromSeconds(config.PeriodSeconds)
+			startAt += (sim.CurrentTime - startAt + period - 1) / period * period
+		}
+		if startAt >= sim.CurrentTime {
+			timeUntilMove = MinDuration(timeUntilMove, startAt-sim.CurrentTime)
+		}
+	}
+	return timeUntilMove
+}
@@ -0,0 +1,92 @@
+package core
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+	"github.com/wowsims/wotlk/sim/core/stats"
+)
+
+// Number of casts of each spell by the given player, over all iterations.
+func testSpellCasts(player *proto.UnitMetrics) map[int32]int32 {
+	casts := map[int32]int32{}
+	for _, action := range player.Actions {
+		for _, target := range action.Targets {
+			casts[action.Id.GetSpellId()] += target.Casts
+		}
+	}
+	return casts
+}
+
+func TestEncounterMovement(t *testing.T) {
+	runMovement := func(movement []*proto.MovementEvent) *proto.RaidSimResult {
+		rsr := newTestRaidSimRequest([]*proto.Player{newTestCaster("Caster", "")}, &proto.Encounter{
+			Duration: 60,
+			Targets:  []*proto.Target{newTestTarget(0)},
+			Movement: movement,
+		}, 6)
+		return runTestRaidSim(t, rsr)
+	}
+
+	// Bolts take 2s, so there's one landing at the end of the fight.
+	standing := runMovement(nil)
+	if casts := testSpellCasts(standing.RaidMetrics.Parties[0].Players[0])[testBoltID]; casts != 30*6 {
+		t.Fatalf("Expected %d bolts without movement, got %d", 30*6, casts)
+	}
+
+	// Moving from 5s to 15s, 25s to 35s and 45s to 55s interrupts the bolts
+	// from 4s, 24s and 44s, and bolts only land at 2s and 4s, then every 2s
+	// from 17s to 25s, 37s to 45s and 57s to 59s.
+	moving := runMovement([]*proto.MovementEvent{{StartSeconds: 5, DurationSeconds: 10, PeriodSeconds: 20}})
+	if casts := testSpellCasts(moving.RaidMetrics.Parties[0].Players[0])[testBoltID]; casts != 14*6 {
+		t.Fatalf("Expected %d bolts with movement, got %d", 14*6, casts)
+	}
+	if moving.RaidMetrics.Dps.Avg >= standing.RaidMetrics.Dps.Avg {
+		t.Fatalf("Expected movement to lower raid dps from %0.3f, got %0.3f", standing.RaidMetrics.Dps.Avg, moving.RaidMetrics.Dps.Avg)
+	}
+}
+
+func TestMovementInterruptsCast(t *testing.T) {
+	rotation := `actions+=/cast_spell,id=6
+actions+=/cast_spell,id=2
+`
+	caster := newTestCaster("Caster", rotation)
+	caster.BonusStats = &proto.UnitStats{Stats: stats.Stats{stats.Mana: 1000}.ToFloatArray()}
+	rsr := newTestRaidSimRequest([]*proto.Player{caster}, &proto.Encounter{
+		Duration: 10,
+		Targets:  []*proto.Target{newTestTarget(0)},
+		Movement: []*proto.MovementEvent{{StartSeconds: 0.5, DurationSeconds: 1}},
+	}, 3)
+	rsr.SimOptions.TraceApl = true
+	player := runTestRaidSim(t, rsr).RaidMetrics.Parties[0].Players[0]
+
+	// The first blast is interrupted, which leaves it ready again. A shock goes
+	// out right away while moving, then the blast at 2s once the GCD is up,
+	// and shocks every 1.5s after that.
+	casts := testSpellCasts(player)
+	if casts[testBlastID] != 1*3 || casts[testShockID] != 6*3 {
+		t.Fatalf("Expected %d blasts and %d shocks, got %d and %d", 1*3, 6*3, casts[testBlastID], casts[testShockID])
+	}
+	if damage := player.Dps.Avg * 10; math.Abs(damage-5000) > 0.001 {
+		t.Fatalf("Expected 5000 damage, got %0.3f", damage)
+	}
+	blast, shock := player.AplActions[0], player.AplActions[1]
+	if blast.ExecutionsAvg != 2 || blast.FirstUsedSecondsAvg != 0 || blast.LastUsedSecondsAvg != 2 {
+		t.Fatalf("Expected blasts started at 0s and 2s, got %v", blast)
+	}
+	if shock.FirstUsedSecondsAvg != 0.5 {
+		t.Fatalf("Expected the first shock at 0.5s, got %v", shock)
+	}
+
+	// Only the completed blast is paid for.
+	var manaSpent float64
+	for _, resource := range player.Resources {
+		if resource.Type == proto.ResourceType_ResourceTypeMana && resource.Id.GetSpellId() == testBlastID {
+			manaSpent -= resource.Gain
+		}
+	}
+	if manaSpent != 100*3 {
+		t.Fatalf("Expected %d mana spent on blasts, got %0.3f", 100*3, manaSpent)
+	}
+}
@@ -33,6 +33,10 @@ type PendingAction struct {
 
 	cancelled bool
 	consumed  bool
+
+	// Insertion order, for breaking ties between actions with the same time and
+	// priority.
+	seq uint64
 }
 
 func (pa *PendingAction) Cancel(sim *Simulation) {
@@ -47,3 +51,80 @@ func (pa *PendingAction) Cancel(sim *Simulation) {
 
 	pa.cancelled = true
 }
+
+// Binary min-heap of pending actions. Actions are ordered by time, then by
+// priority (highest first), then by the order in which they were added.
+type pendingActionQueue struct {
+	actions []*PendingAction
+	nextSeq uint64
+}
+
+func (queue *pendingActionQueue) reset() {
+	for i := range queue.actions {
+		queue.actions[i] = nil
+	}
+	queue.actions = queue.actions[:0]
+	queue.nextSeq = 0
+}
+
+func (queue *pendingActionQueue) len() int {
+	return len(queue.actions)
+}
+
+func (queue *pendingActionQueue) less(i, j int) bool {
+	a, b := queue.actions[i], queue.actions[j]
+	if a.NextActionAt != b.NextActionAt {
+		return a.NextActionAt < b.NextActionAt
+	}
+	if a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+	return a.seq < b.seq
+}
+
+func (queue *pendingActionQueue) push(pa *PendingAction) {
+	pa.seq = queue.nextSeq
+	queue.nextSeq++
+	queue.actions = append(queue.actions, pa)
+
+	i := len(queue.actions) - 1
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !queue.less(i, parent) {
+			break
+		}
+		queue.actions[i], queue.actions[parent] = queue.actions[parent], queue.actions[i]
+		i = parent
+	}
+}
+
+// Returns the next action, without removing it.
+func (queue *pendingActionQueue) peek() *PendingAction {
+	return queue.actions[0]
+}
+
+// Removes and returns the next action.
+func (queue *pendingActionQueue) pop() *PendingAction {
+	last := len(queue.actions) - 1
+	pa := queue.actions[0]
+	queue.actions[0] = queue.actions[last]
+	queue.actions[last] = nil
+	queue.actions = queue.actions[:last]
+
+	i := 0
+	for {
+		smallest := i
+		if left := 2*i + 1; left < last && queue.less(left, smallest) {
+			smallest = left
+		}
+		if right := 2*i + 2; right < last && queue.less(right, smallest) {
+			smallest = right
+		}
+		if smallest == i {
+			break
+		}
+		queue.actions[i], queue.actions[smallest] = queue.actions[smallest], queue.actions[i]
+		i = smallest
+	}
+	return pa
+}
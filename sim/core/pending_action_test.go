@@ -0,0 +1,71 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func newPendingActionTestSim() *Simulation {
+	return &Simulation{
+		Environment: &Environment{Raid: &Raid{}},
+		Duration:    time.Minute,
+	}
+}
+
+func TestPendingActionQueueOrder(t *testing.T) {
+	var order []string
+	newAction := func(name string, at time.Duration, priority ActionPriority) *PendingAction {
+		return &PendingAction{
+			NextActionAt: at,
+			Priority:     priority,
+			OnAction: func(sim *Simulation) {
+				order = append(order, name)
+			},
+		}
+	}
+
+	sim := newPendingActionTestSim()
+	sim.AddPendingAction(newAction("gcd@2", time.Second*2, ActionPriorityGCD))
+	sim.AddPendingAction(newAction("auto@1", time.Second, ActionPriorityAuto))
+	sim.AddPendingAction(newAction("gcd@1", time.Second, ActionPriorityGCD))
+	sim.AddPendingAction(newAction("dot@1", time.Second, ActionPriorityDOT))
+	sim.AddPendingAction(newAction("gcd@1 again", time.Second, ActionPriorityGCD))
+	cancelled := newAction("cancelled@1", time.Second, ActionPriorityDOT)
+	sim.AddPendingAction(cancelled)
+	sim.AddPendingAction(newAction("low@0", 0, ActionPriorityLow))
+	cancelled.Cancel(sim)
+
+	sim.runPendingActions(NeverExpires)
+
+	expected := []string{"low@0", "dot@1", "auto@1", "gcd@1", "gcd@1 again", "gcd@2"}
+	if len(order) != len(expected) {
+		t.Fatalf("Expected actions %v, got %v", expected, order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("Expected actions %v, got %v", expected, order)
+		}
+	}
+}
+
+func TestPendingActionQueueStopsAtMax(t *testing.T) {
+	sim := newPendingActionTestSim()
+	executed := 0
+	for i := 1; i <= 3; i++ {
+		sim.AddPendingAction(&PendingAction{
+			NextActionAt: time.Duration(i) * time.Second,
+			OnAction: func(sim *Simulation) {
+				executed++
+			},
+		})
+	}
+
+	sim.runPendingActions(time.Second * 2)
+	if executed != 1 || sim.pendingActions.len() != 2 {
+		t.Fatalf("Expected 1 action before the max and 2 still pending, got %d and %d", executed, sim.pendingActions.len())
+	}
+	sim.runPendingActions(NeverExpires)
+	if executed != 3 || sim.pendingActions.len() != 0 {
+		t.Fatalf("Expected all 3 actions to run, got %d with %d still pending", executed, sim.pendingActions.len())
+	}
+}
@@ -183,6 +183,9 @@ func (pet *Pet) Enable(sim *Simulation, petAgent PetAgent) {
 		pet.Log(sim, "Pet inherited stats: %s", pet.ApplyStatDependencies(pet.inheritedStats))
 		pet.Log(sim, "Pet summoned")
 	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.addUnit(sim, &pet.Owner.Unit, &pet.Unit, CombatLogSummon)
+	}
 }
 func (pet *Pet) Disable(sim *Simulation) {
 	if !pet.enabled {
@@ -227,6 +230,9 @@ func (pet *Pet) Disable(sim *Simulation) {
 			pet.Log(sim, pet.GetStats().String())
 		}
 	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.addUnit(sim, &pet.Owner.Unit, &pet.Unit, CombatLogDismiss)
+	}
 }
 
 // Helper for enabling a pet that will expire after a certain duration.
@@ -32,6 +32,18 @@ type PresimOptions struct {
 }
 
 func (sim *Simulation) runPresims(request *proto.RaidSimRequest) *proto.RaidSimResult {
+	rounds := sim.presim(request, nil)
+	if len(rounds) == 0 {
+		return nil
+	}
+	return rounds[len(rounds)-1]
+}
+
+// Runs presim rounds until every Agent is done, and returns the result of each
+// round. If the rounds were already run for another Simulation of the same
+// request, passing their results applies them to this Simulation's Agents
+// without running the presims again.
+func (sim *Simulation) presim(request *proto.RaidSimRequest, recordedRounds []*proto.RaidSimResult) []*proto.RaidSimResult {
 	const numPresimIterations = 100
 
 	// Run presims if requested.
@@ -69,10 +81,13 @@ func (sim *Simulation) runPresims(request *proto.RaidSimRequest) *proto.RaidSimR
 	presimRequest.SimOptions.RandomSeed = 1
 	presimRequest.SimOptions.Debug = false
 	presimRequest.SimOptions.DebugFirstIteration = false
+	presimRequest.SimOptions.CombatLogFormat = proto.CombatLogFormat_CombatLogFormatNone
 	presimRequest.SimOptions.Iterations = numPresimIterations
+	presimRequest.SimOptions.Convergence = nil
+	presimRequest.SimOptions.Replay = nil
 	duration := DurationFromSeconds(presimRequest.Encounter.Duration)
 
-	var lastResult *proto.RaidSimResult
+	var rounds []*proto.RaidSimResult
 
 	doOne := sim.Encounter.EndFightAtHealth > 0
 	for doOne || remainingAgents > 0 {
@@ -94,8 +109,13 @@ func (sim *Simulation) runPresims(request *proto.RaidSimRequest) *proto.RaidSimR
 		}
 
 		// Run the presim.
-		presimResult := runSim(presimRequest, nil, true)
-		lastResult = presimResult
+		var presimResult *proto.RaidSimResult
+		if recordedRounds != nil {
+			presimResult = recordedRounds[len(rounds)]
+		} else {
+			presimResult = runSim(presimRequest, nil, true, 1)
+		}
+		rounds = append(rounds, presimResult)
 
 		if presimResult.ErrorResult != "" {
 			break
@@ -118,5 +138,5 @@ func (sim *Simulation) runPresims(request *proto.RaidSimRequest) *proto.RaidSimR
 		}
 		doOne = false
 	}
-	return lastResult
+	return rounds
 }
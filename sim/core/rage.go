@@ -136,10 +136,20 @@ func (rb *rageBar) AddRage(sim *Simulation, amount float64, metrics *ResourceMet
 	if sim.Log != nil {
 		rb.unit.Log(sim, "Gained %0.3f rage from %s (%0.3f --> %0.3f).", amount, metrics.ActionID, rb.currentRage, newRage)
 	}
+	if timeline := rb.unit.Metrics.timeline; timeline != nil {
+		timeline.beforeResourceChange(sim, proto.ResourceType_ResourceTypeRage)
+	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.addResource(sim, rb.unit, metrics.ActionID, proto.ResourceType_ResourceTypeRage, amount, newRage)
+	}
 
 	rb.currentRage = newRage
-	if !sim.Options.Interactive {
-		rb.onRageGain(sim)
+	if sim.Options.Interactive {
+		return
+	}
+	rb.onRageGain(sim)
+	if rb.unit.Rotation != nil {
+		rb.unit.Rotation.onIdleWakeup(sim)
 	}
 }
 
@@ -154,6 +164,12 @@ func (rb *rageBar) SpendRage(sim *Simulation, amount float64, metrics *ResourceM
 	if sim.Log != nil {
 		rb.unit.Log(sim, "Spent %0.3f rage from %s (%0.3f --> %0.3f).", amount, metrics.ActionID, rb.currentRage, newRage)
 	}
+	if timeline := rb.unit.Metrics.timeline; timeline != nil {
+		timeline.beforeResourceChange(sim, proto.ResourceType_ResourceTypeRage)
+	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.addResource(sim, rb.unit, metrics.ActionID, proto.ResourceType_ResourceTypeRage, -amount, newRage)
+	}
 
 	rb.currentRage = newRage
 }
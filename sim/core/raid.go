@@ -208,7 +208,7 @@ func NewRaid(raidConfig *proto.Raid) *Raid {
 	numDummies := MinInt(24, int(raidConfig.TargetDummies))
 	for i := 0; i < numDummies; i++ {
 		party, partyIndex := raid.GetFirstEmptyRaidIndex()
-		dummy := NewTargetDummy(i, party, partyIndex)
+		dummy := NewTargetDummy(i, party, partyIndex, raidConfig.TargetDummyDamage)
 		party.Players = append(party.Players, dummy)
 	}
 
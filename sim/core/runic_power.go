@@ -194,6 +194,12 @@ func (rp *RunicPowerBar) addRunicPowerInterval(sim *Simulation, amount float64,
 		if sim.Log != nil {
 			rp.unit.Log(sim, "Gained %0.3f runic power from %s (%0.3f --> %0.3f).", amount, metrics.ActionID, rp.currentRunicPower, newRunicPower)
 		}
+		if timeline := rp.unit.Metrics.timeline; timeline != nil {
+			timeline.beforeResourceChange(sim, proto.ResourceType_ResourceTypeRunicPower)
+		}
+		if sim.CombatLog != nil {
+			sim.CombatLog.addResource(sim, rp.unit, metrics.ActionID, proto.ResourceType_ResourceTypeRunicPower, amount, newRunicPower)
+		}
 	}
 
 	rp.currentRunicPower = newRunicPower
@@ -203,6 +209,9 @@ func (rp *RunicPowerBar) AddRunicPower(sim *Simulation, amount float64, metrics
 	rp.addRunicPowerInterval(sim, amount, metrics)
 	if !rp.isACopy {
 		rp.onRunicPowerGain(sim)
+		if rp.unit.Rotation != nil {
+			rp.unit.Rotation.onIdleWakeup(sim)
+		}
 	}
 }
 
@@ -219,6 +228,12 @@ func (rp *RunicPowerBar) spendRunicPower(sim *Simulation, amount float64, metric
 		if sim.Log != nil {
 			rp.unit.Log(sim, "Spent %0.3f runic power from %s (%0.3f --> %0.3f).", amount, metrics.ActionID, rp.currentRunicPower, newRunicPower)
 		}
+		if timeline := rp.unit.Metrics.timeline; timeline != nil {
+			timeline.beforeResourceChange(sim, proto.ResourceType_ResourceTypeRunicPower)
+		}
+		if sim.CombatLog != nil {
+			sim.CombatLog.addResource(sim, rp.unit, metrics.ActionID, proto.ResourceType_ResourceTypeRunicPower, -amount, newRunicPower)
+		}
 	}
 
 	rp.currentRunicPower = newRunicPower
@@ -736,6 +751,20 @@ func (rp *RunicPowerBar) SpendRuneCost(sim *Simulation, spell *Spell, cost RuneC
 	return r[0], r[1], r[2]
 }
 
+func (rp *RunicPowerBar) currentRunesOfType(resourceType proto.ResourceType) int8 {
+	switch resourceType {
+	case proto.ResourceType_ResourceTypeDeathRune:
+		return rp.CurrentDeathRunes()
+	case proto.ResourceType_ResourceTypeBloodRune:
+		return rp.CurrentBloodRunes()
+	case proto.ResourceType_ResourceTypeFrostRune:
+		return rp.CurrentFrostRunes()
+	case proto.ResourceType_ResourceTypeUnholyRune:
+		return rp.CurrentUnholyRunes()
+	}
+	return 0
+}
+
 // GainRuneMetrics should be called after gaining the rune
 func (rp *RunicPowerBar) GainRuneMetrics(sim *Simulation, metrics *ResourceMetrics, gainAmount int8) {
 	if !rp.isACopy {
@@ -764,6 +793,9 @@ func (rp *RunicPowerBar) GainRuneMetrics(sim *Simulation, metrics *ResourceMetri
 
 			rp.unit.Log(sim, "Gained %0.3f %s rune from %s (%d --> %d).", float64(gainAmount), name, metrics.ActionID, currRunes-gainAmount, currRunes)
 		}
+		if sim.CombatLog != nil {
+			sim.CombatLog.addResource(sim, rp.unit, metrics.ActionID, metrics.Type, float64(gainAmount), float64(rp.currentRunesOfType(metrics.Type)))
+		}
 	}
 }
 
@@ -795,6 +827,9 @@ func (rp *RunicPowerBar) SpendRuneMetrics(sim *Simulation, metrics *ResourceMetr
 
 			rp.unit.Log(sim, "Spent 1.000 %s rune from %s (%d --> %d).", name, metrics.ActionID, currRunes+spendAmount, currRunes)
 		}
+		if sim.CombatLog != nil {
+			sim.CombatLog.addResource(sim, rp.unit, metrics.ActionID, metrics.Type, -float64(spendAmount), float64(rp.currentRunesOfType(metrics.Type)))
+		}
 	}
 }
 
@@ -851,6 +886,9 @@ func (rp *RunicPowerBar) RegenRune(sim *Simulation, regenAt time.Duration, slot
 
 			rp.GainRuneMetrics(sim, metrics, 1)
 			onGain(sim)
+			if rp.unit.Rotation != nil {
+				rp.unit.Rotation.onIdleWakeup(sim)
+			}
 		}
 	}
 }
@@ -117,6 +117,9 @@ func (rp *RunicPowerBar) GainDeathRuneMetrics(sim *Simulation, spell *Spell, cur
 		if sim.Log != nil {
 			rp.unit.Log(sim, "Gained 1.000 death rune from %s (%d --> %d).", metrics.ActionID, currRunes, newRunes)
 		}
+		if sim.CombatLog != nil {
+			sim.CombatLog.addResource(sim, rp.unit, metrics.ActionID, metrics.Type, 1, float64(newRunes))
+		}
 	}
 }
 
@@ -6,6 +6,9 @@ type Shield struct {
 
 	// Embed Aura so we can use IsActive/Refresh/etc directly.
 	*Aura
+
+	// Damage left to absorb, from the last Apply(). Only used for metrics.
+	remaining float64
 }
 
 func (shield *Shield) Apply(sim *Simulation, shieldAmount float64) {
@@ -19,6 +22,7 @@ func (shield *Shield) Apply(sim *Simulation, shieldAmount float64) {
 
 	shield.Aura.Deactivate(sim)
 	shield.Aura.Activate(sim)
+	shield.remaining = shieldAmount
 
 	threat := 0.0 // TODO
 	shield.Spell.SpellMetrics[target.UnitIndex].TotalThreat += threat
@@ -28,15 +32,63 @@ func (shield *Shield) Apply(sim *Simulation, shieldAmount float64) {
 	if sim.Log != nil {
 		caster.Log(sim, "%s %s Hit for %0.3f shielding. (Threat: %0.3f)", target.LogLabel(), shield.Spell.ActionID, shieldAmount, threat)
 	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.Add(&CombatLogEvent{
+			Timestamp: sim.CurrentTime,
+			Type:      CombatLogShield,
+			Source:    caster,
+			Target:    target,
+			ActionID:  shield.Spell.ActionID,
+			Outcome:   OutcomeHit,
+			Amount:    shieldAmount,
+			Threat:    threat,
+		})
+	}
+}
+
+// Records as much of the damage as the shield has left as absorbed. The
+// damage itself is still taken, since shields don't reduce damage in the sim.
+func (shield *Shield) recordAbsorb(sim *Simulation, damage float64) float64 {
+	absorbed := MinFloat(damage, shield.remaining)
+	shield.remaining -= absorbed
+	shield.Aura.Unit.AddAbsorb(sim, shield.Spell, absorbed)
+	return absorbed
 }
 
 func NewShield(config Shield) *Shield {
 	shield := &Shield{}
 	*shield = config
 
+	target := shield.Aura.Unit
+	target.shields = append(target.shields, shield)
 	return shield
 }
 
+// Records the damage the unit's active shields would absorb, in creation
+// order, for absorb metrics.
+func (unit *Unit) recordShieldAbsorbs(sim *Simulation, damage float64) {
+	for _, shield := range unit.shields {
+		if damage <= 0 {
+			return
+		}
+		if shield.IsActive() && shield.remaining > 0 {
+			damage -= shield.recordAbsorb(sim, damage)
+		}
+	}
+}
+
+// Records damage absorbed on this unit by spell, which may belong to any
+// friendly unit. For effects which aren't modeled as Shields, e.g. Anti-Magic
+// Shell.
+func (unit *Unit) AddAbsorb(sim *Simulation, spell *Spell, amount float64) {
+	spell.SpellMetrics[unit.UnitIndex].TotalAbsorbed += amount
+	unit.Metrics.mitigation.Absorbed += amount
+
+	if sim.Log != nil {
+		unit.Log(sim, "%s absorbed %0.3f damage.", spell.ActionID, amount)
+	}
+}
+
 // Creates Shields for all allied units.
 func NewAllyShieldArray(caster *Unit, config Shield, auraConfig Aura) []*Shield {
 	shields := make([]*Shield, len(caster.Env.AllUnits))
@@ -25,7 +25,7 @@ type Simulation struct {
 	testRands map[string]Rand
 
 	// Current Simulation State
-	pendingActions []*PendingAction
+	pendingActions pendingActionQueue
 	CurrentTime    time.Duration // duration that has elapsed in the sim since starting
 	Duration       time.Duration // Duration of current iteration
 	NeedsInput     bool          // Sim is in interactive mode and needs input
@@ -34,6 +34,9 @@ type Simulation struct {
 
 	Log func(string, ...interface{})
 
+	// Structured event stream, set only when requested in the options.
+	CombatLog *CombatLog
+
 	executePhase20Begins  time.Duration
 	executePhase25Begins  time.Duration
 	executePhase35Begins  time.Duration
@@ -41,26 +44,35 @@ type Simulation struct {
 	executePhase25        bool
 	executePhase35        bool
 	executePhaseCallbacks []func(*Simulation, int) // 2nd parameter is 35 for 35%, 25 for 25% and 20 for 20%
+
+	phase          int
+	phaseCallbacks []func(*Simulation, int) // 2nd parameter is the new phase
 }
 
 func RunSim(rsr *proto.RaidSimRequest, progress chan *proto.ProgressMetrics) *proto.RaidSimResult {
-	return runSim(rsr, progress, false)
+	return runSim(rsr, progress, false, numSimWorkers(rsr.SimOptions, 1))
+}
+
+// Returns how many workers to split a sim's iterations across, using
+// defaultWorkers if the options don't say.
+func numSimWorkers(options *proto.SimOptions, defaultWorkers int) int {
+	numWorkers := int(options.NumWorkers)
+	if numWorkers <= 0 {
+		numWorkers = defaultWorkers
+	}
+	// Logs, interactive mode and replays need a single, ordered sequence of
+	// iterations.
+	if options.Debug || options.Interactive || options.Replay != nil {
+		numWorkers = 1
+	}
+	return MaxInt(1, MinInt(numWorkers, int(options.Iterations)))
 }
 
-func runSim(rsr *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, skipPresim bool) (result *proto.RaidSimResult) {
+func runSim(rsr *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, skipPresim bool, numWorkers int) (result *proto.RaidSimResult) {
 	defer func() {
 		if err := recover(); err != nil {
-			errStr := ""
-			switch errt := err.(type) {
-			case string:
-				errStr = errt
-			case error:
-				errStr = errt.Error()
-			}
-
-			errStr += "\nStack Trace:\n" + string(debug.Stack())
 			result = &proto.RaidSimResult{
-				ErrorResult: errStr,
+				ErrorResult: simErrorString(err),
 			}
 			if progress != nil {
 				progress <- &proto.ProgressMetrics{
@@ -73,6 +85,10 @@ func runSim(rsr *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, ski
 		}
 	}()
 
+	if numWorkers > 1 {
+		return runSimParallel(rsr, progress, skipPresim, numWorkers)
+	}
+
 	sim := NewSim(rsr)
 
 	if !skipPresim {
@@ -103,12 +119,7 @@ func runSim(rsr *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, ski
 			}
 			runtime.Gosched() // allow time for message to make it back out.
 		}
-		// Use pre-sim as estimate for length of fight (when using health fight)
-		if sim.Encounter.EndFightAtHealth > 0 && presimResult != nil {
-			sim.BaseDuration = time.Duration(presimResult.AvgIterationDuration) * time.Second
-			sim.Duration = time.Duration(presimResult.AvgIterationDuration) * time.Second
-			sim.Encounter.DurationIsEstimate = false // we now have a pretty good value for duration
-		}
+		sim.applyPresimDuration(presimResult)
 	}
 
 	// using a variable here allows us to mutate it in the deferred recover, sending out error info
@@ -117,6 +128,28 @@ func runSim(rsr *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, ski
 	return result
 }
 
+// Formats a recovered panic, including the stack trace.
+func simErrorString(err interface{}) string {
+	errStr := ""
+	switch errt := err.(type) {
+	case string:
+		errStr = errt
+	case error:
+		errStr = errt.Error()
+	}
+
+	return errStr + "\nStack Trace:\n" + string(debug.Stack())
+}
+
+// Use pre-sim as estimate for length of fight (when using health fight)
+func (sim *Simulation) applyPresimDuration(presimResult *proto.RaidSimResult) {
+	if sim.Encounter.EndFightAtHealth > 0 && presimResult != nil {
+		sim.BaseDuration = time.Duration(presimResult.AvgIterationDuration) * time.Second
+		sim.Duration = time.Duration(presimResult.AvgIterationDuration) * time.Second
+		sim.Encounter.DurationIsEstimate = false // we now have a pretty good value for duration
+	}
+}
+
 func NewSim(rsr *proto.RaidSimRequest) *Simulation {
 	simOptions := rsr.SimOptions
 	rseed := simOptions.RandomSeed
@@ -162,7 +195,10 @@ func (sim *Simulation) labelRand(label string) Rand {
 }
 
 func (sim *Simulation) reseedRands(i int64) {
-	rseed := sim.Options.RandomSeed + i
+	sim.seedRands(sim.Options.RandomSeed + i)
+}
+
+func (sim *Simulation) seedRands(rseed int64) {
 	sim.rand.Seed(rseed)
 
 	if sim.isTest {
@@ -222,6 +258,7 @@ func (sim *Simulation) Init() {
 			}
 		}
 	}
+	sim.enableTimelineMetrics()
 }
 
 // Reset will set sim back and erase all current state.
@@ -245,12 +282,14 @@ func (sim *Simulation) reset() {
 	sim.executePhase25Begins = time.Duration(float64(sim.Duration) * (1.0 - sim.Encounter.ExecuteProportion_25))
 	sim.executePhase35Begins = time.Duration(float64(sim.Duration) * (1.0 - sim.Encounter.ExecuteProportion_35))
 
-	sim.pendingActions = make([]*PendingAction, 0, 64)
+	sim.pendingActions.reset()
 
 	sim.executePhase20 = false
 	sim.executePhase25 = false
 	sim.executePhase35 = false
 	sim.executePhaseCallbacks = []func(*Simulation, int){}
+	sim.phase = 1
+	sim.phaseCallbacks = []func(*Simulation, int){}
 
 	sim.CurrentTime = 0
 
@@ -259,11 +298,84 @@ func (sim *Simulation) reset() {
 	sim.initManaTickAction()
 }
 
-// Run runs the simulation for the configured number of iterations, and
-// collects all the metrics together.
+// Run runs the simulation for the configured number of iterations, or until
+// it converges, and collects all the metrics together.
 func (sim *Simulation) run() *proto.RaidSimResult {
+	logsBuffer := sim.setupLogs()
+	combatLog := sim.setupCombatLog()
+
+	var progress func(completedIterations int32)
+	if sim.ProgressReport != nil {
+		progress = func(completedIterations int32) {
+			metrics := sim.Raid.GetMetrics()
+			sim.ProgressReport(&proto.ProgressMetrics{
+				TotalIterations:     sim.Options.Iterations,
+				CompletedIterations: completedIterations,
+				Dps:                 metrics.Dps.Avg,
+				Hps:                 metrics.Hps.Avg,
+				DpsStderr:           metrics.Dps.Stderr,
+				HpsStderr:           metrics.Hps.Stderr,
+			})
+			runtime.Gosched() // ensure that reporting threads are given time to report, mostly only important in wasm (only 1 thread)
+		}
+	}
+
+	sim.Init()
+
+	var firstIterationDuration, totalDuration time.Duration
+	completedIterations := int32(0)
+	if sim.Options.Replay != nil {
+		firstIterationDuration = sim.runReplay(sim.Options.Replay.Seed)
+		totalDuration = firstIterationDuration
+		completedIterations = 1
+	}
+
+	// Iterations run in batches, checking for convergence in between.
+	batchSize := convergenceBatchSize(sim.Options)
+	for completedIterations < sim.Options.Iterations && sim.Options.Replay == nil {
+		batchStart := completedIterations
+		batchEnd := MinInt32(batchStart+batchSize, sim.Options.Iterations)
+
+		var batchProgress func(int32)
+		if progress != nil {
+			batchProgress = func(completedInBatch int32) {
+				progress(batchStart + completedInBatch)
+			}
+		}
+		first, total := sim.runIterations(batchStart, batchEnd, batchProgress)
+		if batchStart == 0 {
+			firstIterationDuration = first
+		}
+		totalDuration += total
+		completedIterations = batchEnd
+
+		if completedIterations < sim.Options.Iterations && sim.Options.Convergence != nil && simsConverged(sim.Options.Convergence, []*Simulation{sim}) {
+			break
+		}
+	}
+
+	result := &proto.RaidSimResult{
+		RaidMetrics:      sim.Raid.GetMetrics(),
+		EncounterMetrics: sim.Encounter.GetMetricsProto(),
+
+		Logs:                   logsBuffer.String(),
+		CombatLog:              combatLog.String(),
+		FirstIterationDuration: firstIterationDuration.Seconds(),
+		AvgIterationDuration:   totalDuration.Seconds() / float64(completedIterations),
+	}
+
+	// Final progress report
+	if sim.ProgressReport != nil {
+		sim.ProgressReport(&proto.ProgressMetrics{TotalIterations: sim.Options.Iterations, CompletedIterations: completedIterations, Dps: result.RaidMetrics.Dps.Avg, DpsStderr: result.RaidMetrics.Dps.Stderr, FinalRaidResult: result})
+	}
+
+	return result
+}
+
+// Enables logging if requested, and returns the buffer logs are written to.
+func (sim *Simulation) setupLogs() *strings.Builder {
 	logsBuffer := &strings.Builder{}
-	if sim.Options.Debug || sim.Options.DebugFirstIteration {
+	if sim.Options.Debug || sim.Options.DebugFirstIteration || sim.Options.Replay != nil {
 		sim.Log = func(message string, vals ...interface{}) {
 			logsBuffer.WriteString(fmt.Sprintf("[%0.2f] "+message+"\n", append([]interface{}{sim.CurrentTime.Seconds()}, vals...)...))
 		}
@@ -275,54 +387,71 @@ func (sim *Simulation) run() *proto.RaidSimResult {
 	// 	fmt.Printf(fmt.Sprintf("[%0.1f] "+message+"\n", append([]interface{}{sim.CurrentTime.Seconds()}, vals...)...))
 	// }
 
-	sim.Init()
-
-	sim.runOnce()
-	firstIterationDuration := sim.Duration
-	if sim.Encounter.EndFightAtHealth != 0 {
-		firstIterationDuration = sim.CurrentTime
-	}
-	totalDuration := firstIterationDuration
+	return logsBuffer
+}
 
-	if !sim.Options.Debug {
-		sim.Log = nil
+// Enables the structured combat log if requested, and returns it. It only
+// covers the first iteration. Replays always have one.
+func (sim *Simulation) setupCombatLog() *CombatLog {
+	format := sim.Options.CombatLogFormat
+	if sim.Options.Replay != nil && format == proto.CombatLogFormat_CombatLogFormatNone {
+		format = proto.CombatLogFormat_CombatLogFormatJsonLines
 	}
+	sim.CombatLog = NewCombatLog(format)
+	return sim.CombatLog
+}
 
+// Runs iterations start through end-1, and returns the duration of the first
+// one and the total duration of all of them. Each iteration is seeded by its
+// index, so it plays out the same no matter which Simulation runs it.
+// progress, if set, is periodically called with the number of iterations
+// completed so far. The Simulation must already be initialized.
+func (sim *Simulation) runIterations(start int32, end int32, progress func(completedIterations int32)) (time.Duration, time.Duration) {
+	var firstIterationDuration, totalDuration time.Duration
 	var st time.Time
-	for i := int32(1); i < sim.Options.Iterations; i++ {
-		// fmt.Printf("Iteration: %d\n", i)
-		if sim.ProgressReport != nil && time.Since(st) > time.Millisecond*100 {
-			metrics := sim.Raid.GetMetrics()
-			sim.ProgressReport(&proto.ProgressMetrics{TotalIterations: sim.Options.Iterations, CompletedIterations: i, Dps: metrics.Dps.Avg, Hps: metrics.Hps.Avg})
-			runtime.Gosched() // ensure that reporting threads are given time to report, mostly only important in wasm (only 1 thread)
+	for i := start; i < end; i++ {
+		// Ranges may start past the first iteration, which is the only one
+		// logged unless debugging.
+		if i > 0 {
+			if !sim.Options.Debug {
+				sim.Log = nil
+			}
+			sim.CombatLog = nil
+		}
+		if i > start && progress != nil && time.Since(st) > time.Millisecond*100 {
+			progress(i - start)
 			st = time.Now()
 		}
 
 		// Before each iteration, reset state to seed+iterations
-		sim.reseedRands(int64(i))
+		if i > 0 {
+			sim.reseedRands(int64(i))
+		}
 
 		sim.runOnce()
-		iterDuration := sim.Duration
-		if sim.Encounter.EndFightAtHealth != 0 {
-			iterDuration = sim.CurrentTime
+		iterDuration := sim.iterationDuration()
+		if i == start {
+			firstIterationDuration = iterDuration
 		}
 		totalDuration += iterDuration
 	}
-	result := &proto.RaidSimResult{
-		RaidMetrics:      sim.Raid.GetMetrics(),
-		EncounterMetrics: sim.Encounter.GetMetricsProto(),
+	return firstIterationDuration, totalDuration
+}
 
-		Logs:                   logsBuffer.String(),
-		FirstIterationDuration: firstIterationDuration.Seconds(),
-		AvgIterationDuration:   totalDuration.Seconds() / float64(sim.Options.Iterations),
-	}
+// Runs only the iteration that started with the given seed, as recorded in
+// DistributionMetrics, and returns its duration.
+func (sim *Simulation) runReplay(seed int64) time.Duration {
+	sim.seedRands(seed)
+	sim.runOnce()
+	return sim.iterationDuration()
+}
 
-	// Final progress report
-	if sim.ProgressReport != nil {
-		sim.ProgressReport(&proto.ProgressMetrics{TotalIterations: sim.Options.Iterations, CompletedIterations: sim.Options.Iterations, Dps: result.RaidMetrics.Dps.Avg, FinalRaidResult: result})
+// Returns the duration of the iteration that just finished.
+func (sim *Simulation) iterationDuration() time.Duration {
+	if sim.Encounter.EndFightAtHealth != 0 {
+		return sim.CurrentTime
 	}
-
-	return result
+	return sim.Duration
 }
 
 func (sim *Simulation) runPendingActions(max time.Duration) {
@@ -364,7 +493,7 @@ func (sim *Simulation) Cleanup() {
 	// intuitive.
 	sim.CurrentTime = sim.Duration
 
-	for _, pa := range sim.pendingActions {
+	for _, pa := range sim.pendingActions.actions {
 		if pa.CleanUp != nil {
 			pa.CleanUp(sim)
 		}
@@ -376,8 +505,8 @@ func (sim *Simulation) Cleanup() {
 	for _, unit := range sim.Raid.AllUnits {
 		unit.Metrics.doneIteration(unit, sim)
 	}
-	for _, target := range sim.Encounter.TargetUnits {
-		target.Metrics.doneIteration(target, sim)
+	for _, target := range sim.Encounter.Targets {
+		target.Metrics.doneIteration(&target.Unit, sim)
 	}
 }
 
@@ -390,33 +519,35 @@ func (sim *Simulation) runOnce() {
 }
 
 func (sim *Simulation) Step(max time.Duration) bool {
-	if len(sim.pendingActions) == 0 {
+	if sim.pendingActions.len() == 0 {
 		return true
 	}
 
-	last := len(sim.pendingActions) - 1
-	pa := sim.pendingActions[last]
-	sim.pendingActions = sim.pendingActions[:last]
+	pa := sim.pendingActions.peek()
 	if pa.cancelled {
+		sim.pendingActions.pop()
 		return false
 	}
 
 	// Use duration as an end check if not using health.
 	if sim.Encounter.EndFightAtHealth == 0 {
 		if pa.NextActionAt > sim.Duration {
+			sim.pendingActions.pop()
 			return true
 		}
 	} else if sim.Encounter.EndFightAtHealth < sim.Encounter.DamageTaken {
+		sim.pendingActions.pop()
+		return true
+	}
+
+	if pa.NextActionAt > sim.CurrentTime && pa.NextActionAt >= max {
 		return true
 	}
 
+	// Pop before advancing, which may add more actions.
+	sim.pendingActions.pop()
 	if pa.NextActionAt > sim.CurrentTime {
-		if pa.NextActionAt < max {
-			sim.advance(pa.NextActionAt - sim.CurrentTime)
-		} else {
-			sim.pendingActions = append(sim.pendingActions, pa)
-			return true
-		}
+		sim.advance(pa.NextActionAt - sim.CurrentTime)
 	}
 	pa.consumed = true
 
@@ -432,24 +563,7 @@ func (sim *Simulation) AddPendingAction(pa *PendingAction) {
 	//	panic(fmt.Sprintf("Cant add action in the past: %s", pa.NextActionAt))
 	//}
 	pa.consumed = false
-	for index, v := range sim.pendingActions {
-		if v.NextActionAt < pa.NextActionAt || (v.NextActionAt == pa.NextActionAt && v.Priority >= pa.Priority) {
-			//if sim.Log != nil {
-			//	sim.Log("Adding action at index %d for time %s", index - len(sim.pendingActions), pa.NextActionAt)
-			//	for i := index; i < len(sim.pendingActions); i++ {
-			//		sim.Log("Upcoming action at %s", sim.pendingActions[i].NextActionAt)
-			//	}
-			//}
-			sim.pendingActions = append(sim.pendingActions, pa)
-			copy(sim.pendingActions[index+1:], sim.pendingActions[index:])
-			sim.pendingActions[index] = pa
-			return
-		}
-	}
-	//if sim.Log != nil {
-	//	sim.Log("Adding action at end for time %s", pa.NextActionAt)
-	//}
-	sim.pendingActions = append(sim.pendingActions, pa)
+	sim.pendingActions.push(pa)
 }
 
 // Advance moves time forward counting down auras, CDs, mana regen, etc
@@ -0,0 +1,229 @@
+package core
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// Splits the iterations of a raid sim into contiguous ranges, one per worker.
+// Each worker runs its range on its own Simulation, and the metrics are merged
+// in worker order, so results only depend on the seed and numWorkers. With
+// convergence enabled, each batch of iterations is split this way.
+func runSimParallel(rsr *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, skipPresim bool, numWorkers int) *proto.RaidSimResult {
+	totalIterations := rsr.SimOptions.Iterations
+
+	sims := make([]*Simulation, numWorkers)
+	for i := range sims {
+		sims[i] = NewSim(rsr)
+	}
+	// All workers must share the same seed, even when it is picked at random.
+	for _, sim := range sims[1:] {
+		sim.rseed = sims[0].rseed
+		sim.rand.Seed(sims[0].rseed)
+	}
+
+	if progress != nil && !skipPresim {
+		progress <- &proto.ProgressMetrics{
+			TotalIterations: totalIterations,
+			PresimRunning:   true,
+		}
+		runtime.Gosched() // allow time for message to make it back out.
+	}
+
+	// Iterations completed by each worker, for progress reports.
+	completed := make([]int32, numWorkers)
+	reportProgress := func(sim *Simulation) {
+		completedIterations := int32(0)
+		for i := range completed {
+			completedIterations += atomic.LoadInt32(&completed[i])
+		}
+		metrics := sim.Raid.GetMetrics()
+		progress <- &proto.ProgressMetrics{
+			TotalIterations:     totalIterations,
+			CompletedIterations: completedIterations,
+			Dps:                 metrics.Dps.Avg,
+			Hps:                 metrics.Hps.Avg,
+			DpsStderr:           metrics.Dps.Stderr,
+			HpsStderr:           metrics.Hps.Stderr,
+		}
+	}
+
+	type workerResult struct {
+		firstIterationDuration time.Duration
+		totalDuration          time.Duration
+		errStr                 string
+	}
+	workerResults := make([]workerResult, numWorkers)
+	logsBuffer := sims[0].setupLogs()
+	combatLog := sims[0].setupCombatLog()
+
+	// Runs work on every worker concurrently, and waits for all of them.
+	runWorkers := func(work func(i int, sim *Simulation, result *workerResult)) {
+		var wg sync.WaitGroup
+		for i, sim := range sims {
+			wg.Add(1)
+			go func(i int, sim *Simulation) {
+				defer wg.Done()
+				result := &workerResults[i]
+				defer func() {
+					if err := recover(); err != nil {
+						result.errStr = simErrorString(err)
+					}
+				}()
+				work(i, sim, result)
+			}(i, sim)
+		}
+		wg.Wait()
+
+		for _, workerResult := range workerResults {
+			if workerResult.errStr != "" {
+				panic(workerResult.errStr)
+			}
+		}
+	}
+
+	// Presims configure the agents of the Simulation they run on, so every
+	// worker needs their settings. Only the first worker runs them, and the
+	// others replay its results.
+	var presimRounds []*proto.RaidSimResult
+	var presimResult *proto.RaidSimResult
+	if !skipPresim {
+		presimRounds = sims[0].presim(rsr, nil)
+		if len(presimRounds) > 0 {
+			presimResult = presimRounds[len(presimRounds)-1]
+		}
+		if presimResult != nil && presimResult.ErrorResult != "" {
+			if progress != nil {
+				progress <- &proto.ProgressMetrics{
+					TotalIterations: totalIterations,
+					FinalRaidResult: presimResult,
+				}
+			}
+			return presimResult
+		}
+		if progress != nil {
+			progress <- &proto.ProgressMetrics{
+				TotalIterations: totalIterations,
+				PresimRunning:   false,
+			}
+		}
+	}
+
+	runWorkers(func(i int, sim *Simulation, result *workerResult) {
+		if !skipPresim {
+			if i > 0 {
+				sim.presim(rsr, presimRounds)
+			}
+			sim.applyPresimDuration(presimResult)
+		}
+		sim.Init()
+	})
+
+	// Iterations run in batches, checking for convergence in between. Each
+	// batch is split across the workers.
+	completedIterations := int32(0)
+	batchSize := convergenceBatchSize(rsr.SimOptions)
+	for completedIterations < totalIterations {
+		batchStart := completedIterations
+		batchEnd := MinInt32(batchStart+batchSize, totalIterations)
+
+		runWorkers(func(i int, sim *Simulation, result *workerResult) {
+			start, end := splitIterations(batchStart, batchEnd, numWorkers, i)
+			completedBefore := atomic.LoadInt32(&completed[i])
+
+			var workerProgress func(int32)
+			if progress != nil {
+				workerProgress = func(completedIterations int32) {
+					atomic.StoreInt32(&completed[i], completedBefore+completedIterations)
+					if i == 0 {
+						reportProgress(sim)
+					}
+				}
+			}
+			first, total := sim.runIterations(start, end, workerProgress)
+			if start == 0 {
+				result.firstIterationDuration = first
+			}
+			result.totalDuration += total
+			atomic.StoreInt32(&completed[i], completedBefore+end-start)
+		})
+		completedIterations = batchEnd
+
+		if completedIterations < totalIterations && rsr.SimOptions.Convergence != nil && simsConverged(rsr.SimOptions.Convergence, sims) {
+			break
+		}
+	}
+
+	totalDuration := time.Duration(0)
+	for i, sim := range sims {
+		if i > 0 {
+			sims[0].mergeMetrics(sim)
+		}
+		totalDuration += workerResults[i].totalDuration
+	}
+
+	result := &proto.RaidSimResult{
+		RaidMetrics:      sims[0].Raid.GetMetrics(),
+		EncounterMetrics: sims[0].Encounter.GetMetricsProto(),
+
+		Logs:                   logsBuffer.String(),
+		CombatLog:              combatLog.String(),
+		FirstIterationDuration: workerResults[0].firstIterationDuration.Seconds(),
+		AvgIterationDuration:   totalDuration.Seconds() / float64(completedIterations),
+	}
+
+	// Final progress report
+	if progress != nil {
+		progress <- &proto.ProgressMetrics{TotalIterations: totalIterations, CompletedIterations: completedIterations, Dps: result.RaidMetrics.Dps.Avg, DpsStderr: result.RaidMetrics.Dps.Stderr, FinalRaidResult: result}
+	}
+
+	return result
+}
+
+// Adds the metrics of another Simulation, built from the same request, to
+// this one's.
+func (sim *Simulation) mergeMetrics(other *Simulation) {
+	sim.Raid.dpsMetrics.merge(&other.Raid.dpsMetrics)
+	sim.Raid.hpsMetrics.merge(&other.Raid.hpsMetrics)
+	for partyIdx, party := range sim.Raid.Parties {
+		otherParty := other.Raid.Parties[partyIdx]
+		party.dpsMetrics.merge(&otherParty.dpsMetrics)
+		party.hpsMetrics.merge(&otherParty.hpsMetrics)
+		for playerIdx, player := range party.Players {
+			player.GetCharacter().mergeMetrics(otherParty.Players[playerIdx].GetCharacter())
+		}
+	}
+
+	for targetIdx, target := range sim.Encounter.Targets {
+		otherTarget := other.Encounter.Targets[targetIdx]
+		target.Metrics.merge(&otherTarget.Metrics)
+		target.auraTracker.mergeMetrics(&otherTarget.auraTracker)
+	}
+}
+
+func (character *Character) mergeMetrics(other *Character) {
+	character.Metrics.merge(&other.Metrics)
+	character.auraTracker.mergeMetrics(&other.auraTracker)
+	for petIdx, petAgent := range character.Pets {
+		petAgent.GetCharacter().mergeMetrics(other.Pets[petIdx].GetCharacter())
+	}
+}
+
+// Returns the range of iterations, out of start through end-1, that worker i
+// of numWorkers should run. Earlier workers get the remainder.
+func splitIterations(start int32, end int32, numWorkers int, i int) (int32, int32) {
+	numIterations := end - start
+	perWorker := numIterations / int32(numWorkers)
+	remainder := numIterations % int32(numWorkers)
+
+	workerStart := start + perWorker*int32(i) + MinInt32(int32(i), remainder)
+	workerEnd := workerStart + perWorker
+	if int32(i) < remainder {
+		workerEnd++
+	}
+	return workerStart, workerEnd
+}
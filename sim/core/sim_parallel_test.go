@@ -0,0 +1,114 @@
+package core
+
+import (
+	"math"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+	"github.com/wowsims/wotlk/sim/core/stats"
+)
+
+// Number of presim sims run, counted by the agents created for them.
+var testPresimSims int32
+
+// A caster with a presim of 2 rounds. It stands in for the Rogue spec.
+type testPresimmer struct {
+	testCaster
+}
+
+func init() {
+	RegisterAgentFactory(
+		proto.Player_Rogue{},
+		proto.Spec_SpecRogue,
+		func(character Character, options *proto.Player) Agent {
+			if strings.HasPrefix(options.Name, "Presim") {
+				atomic.AddInt32(&testPresimSims, 1)
+			}
+			return &testPresimmer{testCaster: testCaster{Character: character}}
+		},
+		func(player *proto.Player, spec interface{}) {
+			player.Spec = spec.(*proto.Player_Rogue)
+		},
+	)
+}
+
+func (tp *testPresimmer) GetCharacter() *Character {
+	return &tp.Character
+}
+
+func (tp *testPresimmer) GetPresimOptions(playerConfig *proto.Player) *PresimOptions {
+	rounds := 0
+	return &PresimOptions{
+		SetPresimPlayerOptions: func(player *proto.Player) {
+			player.Name = "Presim"
+		},
+		OnPresimResult: func(presimResult *proto.UnitMetrics, iterations int32, duration time.Duration) bool {
+			rounds++
+			return rounds == 2
+		},
+	}
+}
+
+func TestParallelIterations(t *testing.T) {
+	rsr := newTestRaidSimRequest([]*proto.Player{
+		newTestTank("Tank", stats.Stats{stats.Dodge: 20 * DodgeRatingPerDodgeChance}),
+		newTestCaster("Caster", ""),
+	}, &proto.Encounter{
+		Duration: 60,
+		Targets:  []*proto.Target{newTestAttackingTarget(1000)},
+	}, 50)
+
+	sequential := runTestRaidSim(t, rsr)
+
+	rsr.SimOptions.NumWorkers = 4
+	parallel := runTestRaidSim(t, rsr)
+	parallelAgain := runTestRaidSim(t, rsr)
+	if parallelAgain.EncounterMetrics.Targets[0].Dps.Avg != parallel.EncounterMetrics.Targets[0].Dps.Avg {
+		t.Fatalf("Parallel sims with the same seed gave different results: %0.3f and %0.3f",
+			parallel.EncounterMetrics.Targets[0].Dps.Avg, parallelAgain.EncounterMetrics.Targets[0].Dps.Avg)
+	}
+
+	// Each iteration has the same seed no matter which worker runs it, so only
+	// the order of the additions differs.
+	seqMetrics := sequential.EncounterMetrics.Targets[0]
+	parMetrics := parallel.EncounterMetrics.Targets[0]
+	if seqMetrics.Dps.Min == seqMetrics.Dps.Max {
+		t.Fatalf("Expected the target's damage to vary between iterations")
+	}
+	if math.Abs(seqMetrics.Dps.Avg-parMetrics.Dps.Avg) > 0.001 || seqMetrics.Dps.Max != parMetrics.Dps.Max || seqMetrics.Dps.Min != parMetrics.Dps.Min {
+		t.Fatalf("Parallel dps %0.3f (%0.3f-%0.3f) differs from sequential dps %0.3f (%0.3f-%0.3f)",
+			parMetrics.Dps.Avg, parMetrics.Dps.Min, parMetrics.Dps.Max, seqMetrics.Dps.Avg, seqMetrics.Dps.Min, seqMetrics.Dps.Max)
+	}
+	if expected := sequential.RaidMetrics.Parties[0].Players[1].Dps.Avg; math.Abs(parallel.RaidMetrics.Parties[0].Players[1].Dps.Avg-expected) > 0.001 {
+		t.Fatalf("Expected parallel caster dps %0.3f, got %0.3f", expected, parallel.RaidMetrics.Parties[0].Players[1].Dps.Avg)
+	}
+}
+
+func TestParallelPresim(t *testing.T) {
+	rsr := newTestRaidSimRequest([]*proto.Player{{
+		Name:      "Rogue",
+		Race:      proto.Race_RaceHuman,
+		Class:     proto.Class_ClassRogue,
+		Equipment: &proto.EquipmentSpec{},
+		Spec:      &proto.Player_Rogue{Rogue: &proto.Rogue{}},
+	}}, &proto.Encounter{
+		Duration: 60,
+		Targets:  []*proto.Target{newTestTarget(0)},
+	}, 20)
+	rsr.SimOptions.NumWorkers = 4
+
+	atomic.StoreInt32(&testPresimSims, 0)
+	result := runTestRaidSim(t, rsr)
+
+	// Only the first worker runs the 2 presim rounds, and the others reuse
+	// their results.
+	if presimSims := atomic.LoadInt32(&testPresimSims); presimSims != 2 {
+		t.Fatalf("Expected 2 presim sims, got %d", presimSims)
+	}
+	if dps := result.RaidMetrics.Dps.Avg; dps != 500 {
+		t.Fatalf("Expected 500 dps, got %0.3f", dps)
+	}
+}
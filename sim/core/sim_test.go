@@ -0,0 +1,42 @@
+package core
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+func TestReplayIteration(t *testing.T) {
+	rsr := newTestRaidSimRequest([]*proto.Player{newTestCaster("Caster", "actions+=/cast_spell,id=4\n")}, &proto.Encounter{
+		Duration: 10,
+		Targets:  []*proto.Target{newTestTarget(0)},
+	}, 20)
+	rsr.SimOptions.NumWorkers = 3
+
+	dps := runTestRaidSim(t, rsr).RaidMetrics.Dps
+	if dps.Min == dps.Max {
+		t.Fatalf("Expected dps to vary between iterations")
+	}
+
+	for _, iteration := range []struct {
+		seed int64
+		dps  float64
+	}{{dps.MinSeed, dps.Min}, {dps.MaxSeed, dps.Max}} {
+		replayRequest := googleProto.Clone(rsr).(*proto.RaidSimRequest)
+		replayRequest.SimOptions.Replay = &proto.ReplayOptions{Seed: iteration.seed}
+		replay := runTestRaidSim(t, replayRequest)
+
+		replayDps := replay.RaidMetrics.Dps
+		if replayDps.Iterations != 1 || math.Abs(replayDps.Avg-iteration.dps) > 0.001 {
+			t.Fatalf("Replay of seed %d gave %0.3f dps over %d iterations, expected %0.3f", iteration.seed, replayDps.Avg, replayDps.Iterations, iteration.dps)
+		}
+		if replayDps.MinSeed != iteration.seed {
+			t.Fatalf("Replay ran seed %d, expected %d", replayDps.MinSeed, iteration.seed)
+		}
+		if replay.Logs == "" || replay.CombatLog == "" {
+			t.Fatalf("Expected logs and a combat log for the replay")
+		}
+	}
+}
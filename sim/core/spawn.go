@@ -0,0 +1,125 @@
+package core
+
+// Whether this target is an add, which is only in the fight between spawning
+// and despawning.
+func (target *Target) IsAdd() bool {
+	return target.spawn != nil
+}
+
+// Brings an add into the fight.
+func (target *Target) Spawn(sim *Simulation) {
+	if target.IsEnabled() {
+		return
+	}
+
+	if sim.Log != nil {
+		target.Log(sim, "Spawned")
+	}
+	target.enabled = true
+	target.EndUntargetable(sim)
+	target.SetGCDTimer(sim, sim.CurrentTime)
+	if target.AutoAttacks.IsEnabled() {
+		target.AutoAttacks.EnableAutoSwing(sim)
+	}
+	sim.Encounter.updateActiveTargets()
+	sim.Encounter.updateRaidTarget(sim)
+
+	if target.spawn.DespawnAfterSeconds > 0 {
+		StartDelayedAction(sim, DelayedActionOptions{
+			DoAt:     sim.CurrentTime + DurationFromSeconds(target.spawn.DespawnAfterSeconds),
+			Priority: ActionPriorityDOT,
+			OnAction: target.Despawn,
+		})
+	}
+}
+
+// Takes an add out of the fight, e.g. once it dies. Players attacking it move
+// on to the raid's target.
+func (target *Target) Despawn(sim *Simulation) {
+	if !target.IsEnabled() {
+		return
+	}
+
+	if sim.Log != nil {
+		if target.HealthDepleted() {
+			target.Log(sim, "Died")
+		} else {
+			target.Log(sim, "Despawned")
+		}
+	}
+	target.despawn(sim)
+	sim.Encounter.updateActiveTargets()
+	sim.Encounter.updateRaidTarget(sim)
+
+	// Tanks and players who picked their own target aren't moved by the raid.
+	for _, unit := range sim.Raid.AllUnits {
+		if unit.CurrentTarget == &target.Unit && unit.IsOpponent(unit.CurrentTarget) {
+			unit.switchTarget(sim, &sim.Encounter.raidTarget.Unit)
+		}
+	}
+}
+
+func (target *Target) despawn(sim *Simulation) {
+	target.enabled = false
+	target.StartUntargetable(sim)
+	// Targets without an AI never get a GCD action.
+	if target.gcdAction != nil {
+		target.CancelGCDTimer(sim)
+	}
+	target.AutoAttacks.CancelAutoSwing(sim)
+
+	// Dots and other timed debuffs fall off, while permanent ones like raid
+	// debuffs stay.
+	for _, aura := range target.auras {
+		if aura.IsActive() && aura.ExpiresAt() != NeverExpires {
+			aura.Deactivate(sim)
+		}
+	}
+}
+
+// Replaces the lists of targets in the fight, so that loops over the old ones
+// are unaffected.
+func (encounter *Encounter) updateActiveTargets() {
+	encounter.ActiveTargets = FilterSlice(encounter.Targets, func(target *Target) bool {
+		return target.IsEnabled()
+	})
+	encounter.TargetUnits = MapSlice(encounter.ActiveTargets, func(target *Target) *Unit {
+		return &target.Unit
+	})
+	encounter.updateAOECapMultiplier()
+}
+
+// Takes adds out of the fight until they spawn.
+func (encounter *Encounter) resetAdds(sim *Simulation) {
+	numAdds := 0
+	for _, target := range encounter.Targets {
+		if target.IsAdd() {
+			target.despawn(sim)
+			numAdds++
+		}
+	}
+	if numAdds == 0 {
+		return
+	}
+	encounter.updateActiveTargets()
+
+	for _, target := range encounter.Targets {
+		if !target.IsAdd() {
+			continue
+		}
+		target := target
+		if target.spawn.SpawnPhase > 1 {
+			sim.RegisterPhaseCallback(func(sim *Simulation, phase int) {
+				if phase == int(target.spawn.SpawnPhase) {
+					target.Spawn(sim)
+				}
+			})
+		} else {
+			StartDelayedAction(sim, DelayedActionOptions{
+				DoAt:     DurationFromSeconds(target.spawn.SpawnSeconds),
+				Priority: ActionPriorityDOT,
+				OnAction: target.Spawn,
+			})
+		}
+	}
+}
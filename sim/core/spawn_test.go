@@ -0,0 +1,45 @@
+package core
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+func TestEncounterAdds(t *testing.T) {
+	// Dies to the 4th shock.
+	add := newTestTarget(2000)
+	add.Spawn = &proto.TargetSpawn{SpawnSeconds: 20}
+	// Never dies, but leaves after 5s.
+	leavingAdd := newTestTarget(0)
+	leavingAdd.Spawn = &proto.TargetSpawn{SpawnSeconds: 40, DespawnAfterSeconds: 5}
+
+	// Adds can only be picked while they're in the fight.
+	rotation := `actions+=/cast_spell,id=2,target={type=SelectTargetIndex,target_index=1}
+actions+=/cast_spell,id=2,target={type=SelectTargetIndex,target_index=2}
+actions+=/cast_spell,id=2
+`
+	rsr := newTestRaidSimRequest([]*proto.Player{newTestCaster("Caster", rotation)}, &proto.Encounter{
+		Duration: 60,
+		Targets:  []*proto.Target{newTestTarget(0), add, leavingAdd},
+	}, 6)
+	result := runTestRaidSim(t, rsr)
+
+	// Shocks every 1.5s: at the add from 21s to 25.5s, and at the other one
+	// from 40.5s to 43.5s. The remaining 34 go to the boss.
+	targetable := []float64{100, 100 * 5.5 / 60, 100 * 5.0 / 60}
+	damage := []float64{17000, 2000, 1500}
+	player := result.RaidMetrics.Parties[0].Players[0]
+	for i := range targetable {
+		if actual := result.EncounterMetrics.Targets[i].TargetablePercent; math.Abs(actual-targetable[i]) > 0.001 {
+			t.Fatalf("Expected target %d to be targetable %0.3f%% of the fight, got %0.3f%%", i, targetable[i], actual)
+		}
+		if actual := player.TargetDps[i].Dps.Avg * 60; math.Abs(actual-damage[i]) > 0.001 {
+			t.Fatalf("Expected %0.0f damage to target %d, got %0.3f", damage[i], i, actual)
+		}
+	}
+	if math.Abs(player.DeadTargetDps.Avg*60-damage[1]) > 0.001 {
+		t.Fatalf("Expected only the add's damage to count for dead targets, got %0.3f", player.DeadTargetDps.Avg*60)
+	}
+}
@@ -412,6 +412,20 @@ func (spell *Spell) CanCast(sim *Simulation, target *Unit) bool {
 		return false
 	}
 
+	if spell.castBlockedByMovement(spell.DefaultCast) {
+		if sim.Log != nil {
+			sim.Log("Cant cast because of movement")
+		}
+		return false
+	}
+
+	if spell.castBlockedByUntargetable(target) {
+		if sim.Log != nil {
+			sim.Log("Cant cast because the target is untargetable")
+		}
+		return false
+	}
+
 	if spell.DefaultCast.GCD > 0 && !spell.Unit.GCD.IsReady(sim) {
 		if sim.Log != nil {
 			sim.Log("Cant cast because of GCD")
@@ -454,6 +468,10 @@ func (spell *Spell) SkipCastAndApplyEffects(sim *Simulation, target *Unit) {
 			spell.ActionID, spell.DefaultCast.Cost, time.Duration(0))
 		spell.Unit.Log(sim, "Completed cast %s", spell.ActionID)
 	}
+	if sim.CombatLog != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
+		sim.CombatLog.addCast(sim, spell, target, CombatLogCastStart)
+		sim.CombatLog.addCast(sim, spell, target, CombatLogCastSuccess)
+	}
 	spell.applyEffects(sim, target)
 }
 
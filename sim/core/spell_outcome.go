@@ -439,7 +439,8 @@ func (result *SpellResult) applyAttackTableBlock(spell *Spell, attackTable *Atta
 	if roll < *chance {
 		result.Outcome |= OutcomeBlock
 		spell.SpellMetrics[result.Target.UnitIndex].Blocks++
-		result.Damage = MaxFloat(0, result.Damage-result.Target.BlockValue())
+		result.Blocked = MinFloat(result.Damage, result.Target.BlockValue())
+		result.Damage -= result.Blocked
 		return true
 	}
 	return false
@@ -560,7 +561,8 @@ func (result *SpellResult) applyEnemyAttackTableBlock(spell *Spell, attackTable
 	if roll < *chance {
 		result.Outcome |= OutcomeBlock
 		spell.SpellMetrics[result.Target.UnitIndex].Blocks++
-		result.Damage = MaxFloat(0, result.Damage-result.Target.BlockValue())
+		result.Blocked = MinFloat(result.Damage, result.Target.BlockValue())
+		result.Damage -= result.Blocked
 		return true
 	}
 	return false
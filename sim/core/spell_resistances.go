@@ -17,6 +17,17 @@ func (result *SpellResult) applyResistances(sim *Simulation, spell *Spell, isPer
 	result.PreOutcomeDamage = result.Damage
 }
 
+// Damage prevented by the target's armor or resistances, i.e. by the
+// ResistanceMultiplier. Blocks happen after resistances, so they are added
+// back first.
+func (result *SpellResult) resistedDamage() float64 {
+	if result.ResistanceMultiplier <= 0 || result.ResistanceMultiplier >= 1 {
+		return 0
+	}
+	unmitigated := result.Damage + result.Blocked
+	return unmitigated/result.ResistanceMultiplier - unmitigated
+}
+
 // Modifies damage based on Armor or Magic resistances, depending on the damage type.
 func (spell *Spell) ResistanceMultiplier(sim *Simulation, isPeriodic bool, attackTable *AttackTable) float64 {
 	if spell.Flags.Matches(SpellFlagIgnoreResists) {
@@ -5,6 +5,7 @@ import (
 	"math"
 	"time"
 
+	"github.com/wowsims/wotlk/sim/core/proto"
 	"github.com/wowsims/wotlk/sim/core/stats"
 )
 
@@ -19,6 +20,9 @@ type SpellResult struct {
 
 	ResistanceMultiplier float64 // Partial Resists / Armor multiplier
 	PreOutcomeDamage     float64 // Damage done by this cast before Outcome is applied
+	Blocked              float64 // Damage prevented by blocks
+
+	criticalBlock bool
 
 	inUse bool
 }
@@ -33,6 +37,9 @@ func (spell *Spell) NewResult(target *Unit) *SpellResult {
 	result.Damage = 0
 	result.Threat = 0
 	result.Outcome = OutcomeEmpty // for blocks
+	result.ResistanceMultiplier = 1
+	result.Blocked = 0
+	result.criticalBlock = false
 	result.inUse = true
 
 	return result
@@ -49,6 +56,19 @@ func (result *SpellResult) DidCrit() bool {
 	return result.Outcome.Matches(OutcomeCrit)
 }
 
+// Blocks the damage a second time, for critical blocks.
+func (result *SpellResult) ApplyCriticalBlock(blockValue float64) {
+	blocked := MinFloat(result.Damage, blockValue)
+	result.Damage -= blocked
+	result.Blocked += blocked
+	result.criticalBlock = true
+}
+
+// Damage prevented by the target's armor or resistances, and by blocks.
+func (result *SpellResult) mitigatedDamage() float64 {
+	return result.resistedDamage() + result.Blocked
+}
+
 func (result *SpellResult) DamageString() string {
 	outcomeStr := result.Outcome.String()
 	if !result.Landed() {
@@ -163,6 +183,12 @@ func (spell *Spell) ApplyPostOutcomeDamageModifiers(sim *Simulation, result *Spe
 	result.Damage = MaxFloat(0, result.Damage)
 }
 
+// Whether the target can't be attacked by this spell right now, e.g. during an
+// intermission. Results against such targets are dropped when dealt.
+func (spell *Spell) targetIsUntargetable(target *Unit) bool {
+	return target.untargetable > 0 && spell.Unit.IsOpponent(target)
+}
+
 // For spells that do no damage but still have a hit/miss check.
 func (spell *Spell) CalcOutcome(sim *Simulation, target *Unit, outcomeApplier OutcomeApplier) *SpellResult {
 	attackTable := spell.Unit.AttackTables[target.UnitIndex]
@@ -230,13 +256,37 @@ func (spell *Spell) CalcAndDealOutcome(sim *Simulation, target *Unit, outcomeApp
 
 // Applies the fully computed spell result to the sim.
 func (spell *Spell) dealDamageInternal(sim *Simulation, isPeriodic bool, result *SpellResult) {
+	// Callers check Landed() afterwards, so clear the outcome to skip their
+	// on-hit effects too.
+	if spell.targetIsUntargetable(result.Target) {
+		if sim.Log != nil {
+			spell.Unit.Log(sim, "%s %s can't hit untargetable target.", result.Target.LogLabel(), spell.ActionID)
+		}
+		result.Outcome = OutcomeEmpty
+		result.Damage = 0
+		result.Threat = 0
+		spell.DisposeResult(result)
+		return
+	}
+
+	if result.Damage > 0 && len(result.Target.shields) > 0 {
+		result.Target.recordShieldAbsorbs(sim, result.Damage)
+	}
+
 	spell.SpellMetrics[result.Target.UnitIndex].TotalDamage += result.Damage
 	spell.SpellMetrics[result.Target.UnitIndex].TotalThreat += result.Threat
 
 	// Mark total damage done in raid so far for health based fights.
 	// Don't include damage done by EnemyUnits to Players
 	if result.Target.Type == EnemyUnit {
-		sim.Encounter.DamageTaken += result.Damage
+		if timeline := result.Target.Metrics.timeline; timeline != nil {
+			timeline.beforeResourceChange(sim, proto.ResourceType_ResourceTypeHealth)
+		}
+		target := sim.Encounter.Targets[result.Target.Index]
+		if !target.IsAdd() {
+			sim.Encounter.DamageTaken += result.Damage
+		}
+		target.DamageTaken += result.Damage
 	}
 
 	if sim.Log != nil {
@@ -246,6 +296,21 @@ func (spell *Spell) dealDamageInternal(sim *Simulation, isPeriodic bool, result
 			spell.Unit.Log(sim, "%s %s %s. (Threat: %0.3f)", result.Target.LogLabel(), spell.ActionID, result.DamageString(), result.Threat)
 		}
 	}
+	if timeline := spell.Unit.Metrics.timeline; timeline != nil && spell.Unit.IsOpponent(result.Target) {
+		timeline.addDamage(sim, spell.ActionID, result.Damage)
+	}
+	if sim.CombatLog != nil {
+		if isPeriodic {
+			sim.CombatLog.addSpellResult(sim, spell, result, CombatLogPeriodicDamage)
+		} else {
+			sim.CombatLog.addSpellResult(sim, spell, result, CombatLogDamage)
+		}
+	}
+
+	result.Target.Metrics.mitigation.addResult(spell, result)
+	if spell.Unit.Type == EnemyUnit && result.Target.Type != EnemyUnit {
+		result.Target.Metrics.addDamageTaken(spell, result)
+	}
 
 	if !spell.Flags.Matches(SpellFlagNoOnDamageDealt) {
 		if isPeriodic {
@@ -257,6 +322,12 @@ func (spell *Spell) dealDamageInternal(sim *Simulation, isPeriodic bool, result
 		}
 	}
 
+	if result.Target.Type == EnemyUnit {
+		if target := sim.Encounter.Targets[result.Target.Index]; target.IsAdd() && target.IsEnabled() && target.HealthDepleted() {
+			target.Despawn(sim)
+		}
+	}
+
 	spell.DisposeResult(result)
 }
 func (spell *Spell) DealDamage(sim *Simulation, result *SpellResult) {
@@ -322,7 +393,8 @@ func (spell *Spell) dealHealingInternal(sim *Simulation, isPeriodic bool, result
 	spell.SpellMetrics[result.Target.UnitIndex].TotalHealing += result.Damage
 	spell.SpellMetrics[result.Target.UnitIndex].TotalThreat += result.Threat
 	if result.Target.HasHealthBar() {
-		result.Target.GainHealth(sim, result.Damage, spell.HealthMetrics(result.Target))
+		gained := result.Target.GainHealth(sim, result.Damage, spell.HealthMetrics(result.Target))
+		spell.SpellMetrics[result.Target.UnitIndex].TotalOverhealing += result.Damage - gained
 	}
 
 	if sim.Log != nil {
@@ -332,6 +404,13 @@ func (spell *Spell) dealHealingInternal(sim *Simulation, isPeriodic bool, result
 			spell.Unit.Log(sim, "%s %s %s. (Threat: %0.3f)", result.Target.LogLabel(), spell.ActionID, result.HealingString(), result.Threat)
 		}
 	}
+	if sim.CombatLog != nil {
+		if isPeriodic {
+			sim.CombatLog.addSpellResult(sim, spell, result, CombatLogPeriodicHeal)
+		} else {
+			sim.CombatLog.addSpellResult(sim, spell, result, CombatLogHeal)
+		}
+	}
 
 	if isPeriodic {
 		spell.Unit.OnPeriodicHealDealt(sim, spell, result)
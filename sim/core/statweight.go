@@ -149,6 +149,13 @@ func CalcStatWeight(swr *proto.StatWeightsRequest, referenceStat stats.Stat, pro
 		return StatWeightsResult{}
 	}
 
+	// Only the baseline checks for convergence. The other sims reuse its
+	// iteration count, since their values are compared iteration by iteration.
+	if simOptions.Convergence != nil {
+		simOptions.Iterations = baselineResult.RaidMetrics.Dps.Iterations
+		simOptions.Convergence = nil
+	}
+
 	var waitGroup sync.WaitGroup
 
 	// Do half the iterations with a positive, and half with a negative value for better accuracy.
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"fmt"
 	"strconv"
 	"time"
 
@@ -11,8 +12,12 @@ import (
 type Encounter struct {
 	Duration          time.Duration
 	DurationVariation time.Duration
-	Targets           []*Target
-	TargetUnits       []*Unit
+	// Every target, including adds which aren't in the fight.
+	Targets []*Target
+	// Targets currently in the fight, in encounter order. These lists are
+	// replaced when adds spawn or despawn, so they must not be kept around.
+	ActiveTargets []*Target
+	TargetUnits   []*Unit
 
 	ExecuteProportion_20 float64
 	ExecuteProportion_25 float64
@@ -25,6 +30,30 @@ type Encounter struct {
 	// In health fight: set to true until we get something to base on
 	DurationIsEstimate bool
 
+	// Index of the target used for priority target metrics.
+	PriorityTargetIndex int32
+
+	// Start times of the phases after the first.
+	PhaseStarts []time.Duration
+
+	// Windows in which no target can be attacked.
+	downtime []*proto.TimeWindow
+
+	// Times in which raid members have to move.
+	movement []*movementEvent
+
+	targetPolicy   proto.TargetPolicy
+	targetSwitches []*proto.TargetSwitch
+	// The target chosen by scripted switches, and the one the raid attacks.
+	focusTarget *Target
+	raidTarget  *Target
+
+	// Number of targets which currently can't be attacked, and when and for
+	// how long all of them couldn't in the current iteration.
+	numUntargetable int
+	downtimeSince   time.Duration
+	downtimeTime    time.Duration
+
 	// Value to multiply by, for damage spells which are subject to the aoe cap.
 	aoeCapMultiplier float64
 }
@@ -39,12 +68,35 @@ func NewEncounter(options *proto.Encounter) Encounter {
 		ExecuteProportion_20: MaxFloat(options.ExecuteProportion_20, 0),
 		ExecuteProportion_25: MaxFloat(options.ExecuteProportion_25, 0),
 		ExecuteProportion_35: MaxFloat(options.ExecuteProportion_35, 0),
+		PriorityTargetIndex:  options.PriorityTargetIndex,
 		Targets:              []*Target{},
+		downtime:             options.Downtime,
+		targetPolicy:         options.TargetPolicy,
+		targetSwitches:       options.TargetSwitches,
+	}
+	if options.PriorityTargetIndex < 0 || int(options.PriorityTargetIndex) >= MaxInt(1, len(options.Targets)) {
+		panic("Invalid priority target index " + strconv.Itoa(int(options.PriorityTargetIndex)))
 	}
-	// If UseHealth is set, we use the sum of targets health.
+	for i, phaseStart := range options.PhaseStartSeconds {
+		if phaseStart <= 0 || (i > 0 && phaseStart <= options.PhaseStartSeconds[i-1]) {
+			panic(fmt.Sprintf("Phase start times must be positive and increasing, got %v", options.PhaseStartSeconds))
+		}
+		encounter.PhaseStarts = append(encounter.PhaseStarts, DurationFromSeconds(phaseStart))
+	}
+	validateTimeWindows(options.Downtime, "downtime")
+	for _, targetSwitch := range options.TargetSwitches {
+		if targetSwitch.AtSeconds < 0 || targetSwitch.TargetIndex < 0 || int(targetSwitch.TargetIndex) >= MaxInt(1, len(options.Targets)) {
+			panic(fmt.Sprintf("Invalid switch to target %d at %0.2fs", targetSwitch.TargetIndex, targetSwitch.AtSeconds))
+		}
+	}
+
+	// If UseHealth is set, we use the sum of targets health. Adds don't
+	// count, they leave the fight once their own health is gone.
 	if options.UseHealth {
 		for _, t := range options.Targets {
-			encounter.EndFightAtHealth += t.Stats[stats.Health]
+			if t.Spawn == nil {
+				encounter.EndFightAtHealth += t.Stats[stats.Health]
+			}
 		}
 		if encounter.EndFightAtHealth == 0 {
 			encounter.EndFightAtHealth = 1 // default to something so we don't instantly end without anything.
@@ -52,6 +104,9 @@ func NewEncounter(options *proto.Encounter) Encounter {
 	}
 
 	for targetIndex, targetOptions := range options.Targets {
+		if targetIndex == 0 && targetOptions.Spawn != nil {
+			panic("The first target can't be an add")
+		}
 		target := NewTarget(targetOptions, int32(targetIndex))
 		encounter.Targets = append(encounter.Targets, target)
 		encounter.TargetUnits = append(encounter.TargetUnits, &target.Unit)
@@ -64,6 +119,10 @@ func NewEncounter(options *proto.Encounter) Encounter {
 		encounter.TargetUnits = append(encounter.TargetUnits, &target.Unit)
 	}
 
+	// Every target is active until the first reset, so anything sized by
+	// the number of targets during setup covers adds too.
+	encounter.ActiveTargets = encounter.Targets
+
 	if encounter.EndFightAtHealth > 0 {
 		// Until we pre-sim set duration to 10m
 		encounter.Duration = time.Minute * 10
@@ -79,7 +138,7 @@ func (encounter *Encounter) AOECapMultiplier() float64 {
 	return encounter.aoeCapMultiplier
 }
 func (encounter *Encounter) updateAOECapMultiplier() {
-	encounter.aoeCapMultiplier = MinFloat(10/float64(len(encounter.Targets)), 1)
+	encounter.aoeCapMultiplier = MinFloat(10/float64(len(encounter.ActiveTargets)), 1)
 }
 
 func (encounter *Encounter) doneIteration(sim *Simulation) {
@@ -108,6 +167,15 @@ type Target struct {
 	Unit
 
 	AI TargetAI
+
+	// Damage taken by this target in the current iteration.
+	DamageTaken float64
+
+	untargetableWindows []*proto.TimeWindow
+	spawn               *proto.TargetSpawn
+	untargetableSince   time.Duration
+	// Untargetable time in the current iteration, before untargetableSince.
+	untargetableTime time.Duration
 }
 
 func NewTarget(options *proto.Target, targetIndex int32) *Target {
@@ -152,8 +220,18 @@ func NewTarget(options *proto.Target, targetIndex int32) *Target {
 	target.PseudoStats.InFrontOfTarget = true
 	target.PseudoStats.TightEnemyDamage = options.TightEnemyDamage
 
-	preset := GetPresetTargetWithID(options.Id)
-	if preset != nil && preset.AI != nil {
+	validateTimeWindows(options.Untargetable, "untargetable")
+	target.untargetableWindows = options.Untargetable
+	if options.Spawn != nil {
+		if options.Spawn.SpawnSeconds < 0 || options.Spawn.DespawnAfterSeconds < 0 {
+			panic(fmt.Sprintf("Invalid spawn for %s", target.Label))
+		}
+		target.spawn = options.Spawn
+	}
+
+	if options.Script != nil {
+		target.AI = NewScriptedAI(options.Script)
+	} else if preset := GetPresetTargetWithID(options.Id); preset != nil && preset.AI != nil {
 		target.AI = preset.AI()
 	}
 
@@ -171,6 +249,9 @@ func (target *Target) init(sim *Simulation) {
 func (target *Target) Reset(sim *Simulation) {
 	target.Unit.reset(sim, nil)
 	target.SetGCDTimer(sim, 0)
+	target.DamageTaken = 0
+	target.untargetable = 0
+	target.untargetableTime = 0
 	if target.AI != nil {
 		target.AI.Reset(sim)
 	}
@@ -184,12 +265,35 @@ func (target *Target) doneIteration(sim *Simulation) {
 	target.Unit.doneIteration(sim)
 }
 
+// Health left in the current iteration, based on the target's Health stat.
+func (target *Target) RemainingHealth() float64 {
+	return target.stats[stats.Health] - target.DamageTaken
+}
+
+// Health left in the current iteration between 0 and 1. Targets without a
+// Health stat follow the execute model, losing health evenly over the fight.
+func (target *Target) RemainingHealthPercent(sim *Simulation) float64 {
+	if target.stats[stats.Health] <= 0 {
+		return sim.GetRemainingDurationPercent()
+	}
+	return MaxFloat(0, target.RemainingHealth()/target.stats[stats.Health])
+}
+
+// Whether the target has a Health stat, and has taken that much damage in the
+// current iteration.
+func (target *Target) HealthDepleted() bool {
+	return target.stats[stats.Health] > 0 && target.RemainingHealth() <= 0
+}
+
+// The next target in the fight after this one, wrapping around to the first.
 func (target *Target) NextTarget() *Target {
-	nextIndex := target.Index + 1
-	if nextIndex >= target.Env.GetNumTargets() {
-		nextIndex = 0
+	activeTargets := target.Env.Encounter.ActiveTargets
+	for i, activeTarget := range activeTargets {
+		if activeTarget == target {
+			return activeTargets[(i+1)%len(activeTargets)]
+		}
 	}
-	return target.Env.GetTarget(nextIndex)
+	return activeTargets[0]
 }
 
 func (target *Target) GetMetricsProto() *proto.UnitMetrics {
@@ -2,6 +2,7 @@ package core
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/wowsims/wotlk/sim/core/proto"
 	"github.com/wowsims/wotlk/sim/core/stats"
@@ -11,7 +12,7 @@ type TargetDummy struct {
 	Character
 }
 
-func NewTargetDummy(dummyIndex int, party *Party, partyIndex int) *TargetDummy {
+func NewTargetDummy(dummyIndex int, party *Party, partyIndex int, damageModel *proto.TargetDummyDamageModel) *TargetDummy {
 	name := fmt.Sprintf("Target Dummy %d", dummyIndex+1)
 	td := &TargetDummy{
 		Character: Character{
@@ -37,9 +38,48 @@ func NewTargetDummy(dummyIndex int, party *Party, partyIndex int) *TargetDummy {
 	td.Label = fmt.Sprintf("%s (#%d)", td.Name, td.Index+1)
 	td.GCD = td.NewTimer()
 
+	if damageModel != nil {
+		td.applyDamageModel(damageModel)
+	}
+
 	return td
 }
 
+// Gives the dummy a health pool which takes damage at a steady rate, so that
+// healing on it can overheal.
+func (td *TargetDummy) applyDamageModel(damageModel *proto.TargetDummyDamageModel) {
+	// Dummies don't go through applyAllEffects(), so add their health here.
+	if damageModel.Health > 0 {
+		td.baseStats[stats.Health] = damageModel.Health
+	}
+	td.AddStat(stats.Health, td.baseStats[stats.Health])
+	td.EnableHealthBar()
+	if damageModel.Dtps <= 0 {
+		return
+	}
+
+	cadence := newCadenceModel("Damage", damageModel.CadenceSeconds, damageModel.CadenceVariation)
+
+	td.RegisterResetEffect(func(sim *Simulation) {
+		timeToNextHit := cadence.next(sim)
+		pa := &PendingAction{
+			NextActionAt: timeToNextHit,
+		}
+
+		pa.OnAction = func(sim *Simulation) {
+			// Use modeled DTPS to scale damage per hit based on random cadence.
+			damage := damageModel.Dtps * (float64(timeToNextHit) / float64(time.Second))
+			td.RemoveHealth(sim, damage)
+
+			timeToNextHit = cadence.next(sim)
+			pa.NextActionAt = sim.CurrentTime + timeToNextHit
+			sim.AddPendingAction(pa)
+		}
+
+		sim.AddPendingAction(pa)
+	})
+}
+
 func (td *TargetDummy) GetCharacter() *Character {
 	return &td.Character
 }
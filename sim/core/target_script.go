@@ -0,0 +1,360 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// How often the scripted AI checks abilities which are ready, but didn't pass
+// their chance to use, and health triggered phases in health based fights.
+const scriptedAIPollInterval = time.Second
+
+// TargetAI which follows an EncounterScript.
+type ScriptedAI struct {
+	Target *Target
+
+	script  *proto.EncounterScript
+	phases  []*scriptedPhase
+	players []*Unit
+
+	// The script's phases are the encounter's phases, see sim.CurrentPhase().
+	phaseStartedAt time.Duration
+	// Whether the AI waits for its next ability, so an encounter phase started
+	// elsewhere has to wake it up.
+	waiting bool
+}
+
+type scriptedPhase struct {
+	config *proto.EncounterPhase
+
+	abilities []*scriptedAbility
+
+	// Buffs on the target and auras on the raid, active during the phase.
+	auras []*Aura
+}
+
+type scriptedAbility struct {
+	config *proto.EncounterAbility
+
+	spell       *Spell
+	initialCD   time.Duration
+	chanceToUse float64
+}
+
+func NewScriptedAI(script *proto.EncounterScript) TargetAI {
+	return &ScriptedAI{
+		script: script,
+	}
+}
+
+func (ai *ScriptedAI) Initialize(target *Target, config *proto.Target) {
+	ai.Target = target
+
+	for _, party := range target.Env.Raid.Parties {
+		for _, player := range party.Players {
+			ai.players = append(ai.players, &player.GetCharacter().Unit)
+		}
+	}
+
+	numAuras := 0
+	newAuraLabel := func() string {
+		numAuras++
+		return fmt.Sprintf("Encounter Aura %d", numAuras)
+	}
+
+	for i, phaseConfig := range ai.script.Phases {
+		if i > 0 && phaseConfig.StartSeconds <= 0 && phaseConfig.StartHealthPercent <= 0 {
+			panic(fmt.Sprintf("Encounter phase %d has no start condition", i+1))
+		}
+
+		phase := &scriptedPhase{
+			config: phaseConfig,
+		}
+		for _, auraConfig := range phaseConfig.Buffs {
+			phase.auras = append(phase.auras, registerEncounterAura(&target.Unit, newAuraLabel(), auraConfig))
+		}
+		for _, auraConfig := range phaseConfig.RaidAuras {
+			label := newAuraLabel()
+			for _, player := range ai.players {
+				phase.auras = append(phase.auras, registerEncounterAura(player, label, auraConfig))
+			}
+		}
+		if i == 0 {
+			// Players are reset after targets, so the first phase's auras
+			// activate from their own reset instead of the AI's.
+			for _, aura := range phase.auras {
+				aura.OnReset = func(aura *Aura, sim *Simulation) {
+					aura.Activate(sim)
+				}
+			}
+		}
+
+		for _, abilityConfig := range phaseConfig.Abilities {
+			var auras []*Aura
+			if abilityConfig.Aura != nil {
+				label := newAuraLabel()
+				auras = make([]*Aura, len(target.Env.AllUnits))
+				for _, player := range ai.players {
+					auras[player.UnitIndex] = registerEncounterAura(player, label, abilityConfig.Aura)
+				}
+			}
+			phase.abilities = append(phase.abilities, ai.registerAbility(abilityConfig, auras))
+		}
+
+		ai.phases = append(ai.phases, phase)
+	}
+}
+
+func registerEncounterAura(unit *Unit, label string, config *proto.EncounterAura) *Aura {
+	multiplier := func(value float64) float64 {
+		if value == 0 {
+			return 1
+		}
+		return value
+	}
+	damageDealtMultiplier := multiplier(config.DamageDealtMultiplier)
+	damageTakenMultiplier := multiplier(config.DamageTakenMultiplier)
+	healingTakenMultiplier := multiplier(config.HealingTakenMultiplier)
+	attackSpeedMultiplier := multiplier(config.AttackSpeedMultiplier)
+
+	duration := NeverExpires
+	if config.DurationSeconds > 0 {
+		duration = DurationFromSeconds(config.DurationSeconds)
+	}
+
+	return unit.RegisterAura(Aura{
+		Label:    label,
+		ActionID: ActionID{SpellID: config.SpellId},
+		Duration: duration,
+		OnGain: func(aura *Aura, sim *Simulation) {
+			aura.Unit.PseudoStats.DamageDealtMultiplier *= damageDealtMultiplier
+			aura.Unit.PseudoStats.DamageTakenMultiplier *= damageTakenMultiplier
+			aura.Unit.PseudoStats.HealingTakenMultiplier *= healingTakenMultiplier
+			if attackSpeedMultiplier != 1 {
+				aura.Unit.MultiplyAttackSpeed(sim, attackSpeedMultiplier)
+			}
+		},
+		OnExpire: func(aura *Aura, sim *Simulation) {
+			aura.Unit.PseudoStats.DamageDealtMultiplier /= damageDealtMultiplier
+			aura.Unit.PseudoStats.DamageTakenMultiplier /= damageTakenMultiplier
+			aura.Unit.PseudoStats.HealingTakenMultiplier /= healingTakenMultiplier
+			if attackSpeedMultiplier != 1 {
+				aura.Unit.MultiplyAttackSpeed(sim, 1/attackSpeedMultiplier)
+			}
+		},
+	})
+}
+
+func (ai *ScriptedAI) registerAbility(config *proto.EncounterAbility, auras []*Aura) *scriptedAbility {
+	if config.MinDamage > config.MaxDamage {
+		panic(fmt.Sprintf("Encounter ability %d has min damage above max damage", config.SpellId))
+	}
+
+	ability := &scriptedAbility{
+		config:      config,
+		initialCD:   DurationFromSeconds(config.InitialCooldownSeconds),
+		chanceToUse: config.ChanceToUse,
+	}
+	if ability.chanceToUse == 0 {
+		ability.chanceToUse = 1
+	}
+
+	spellConfig := SpellConfig{
+		ActionID:    ActionID{SpellID: config.SpellId},
+		SpellSchool: SpellSchoolFromProto(config.SpellSchool),
+		ProcMask:    ProcMaskSpellDamage,
+
+		Cast: CastConfig{
+			DefaultCast: Cast{
+				GCD:      GCDDefault,
+				CastTime: DurationFromSeconds(config.CastTimeSeconds),
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   1,
+	}
+	if config.Melee {
+		spellConfig.ProcMask = ProcMaskMeleeMHSpecial
+		spellConfig.Flags |= SpellFlagMeleeMetrics
+		spellConfig.CritMultiplier = 2
+	}
+	if config.CooldownSeconds > 0 {
+		spellConfig.Cast.CD = Cooldown{
+			Timer:    ai.Target.NewTimer(),
+			Duration: DurationFromSeconds(config.CooldownSeconds),
+		}
+	}
+
+	dealDamage := func(sim *Simulation, target *Unit, spell *Spell) {
+		if config.MaxDamage > 0 {
+			baseDamage := sim.Roll(config.MinDamage, config.MaxDamage)
+			outcome := spell.OutcomeAlwaysHit
+			if config.Melee {
+				outcome = spell.OutcomeEnemyMeleeWhite
+			}
+			result := spell.CalcDamage(sim, target, baseDamage, outcome)
+			landed := result.Landed()
+			spell.DealDamage(sim, result)
+			if !landed {
+				return
+			}
+		}
+		if auras != nil {
+			auras[target.UnitIndex].Activate(sim)
+		}
+	}
+
+	if config.Targeting == proto.EncounterTargeting_EncounterTargetingAllRaidMembers {
+		spellConfig.ApplyEffects = func(sim *Simulation, _ *Unit, spell *Spell) {
+			for _, player := range ai.players {
+				dealDamage(sim, player, spell)
+			}
+		}
+	} else {
+		spellConfig.ApplyEffects = func(sim *Simulation, target *Unit, spell *Spell) {
+			dealDamage(sim, target, spell)
+		}
+	}
+
+	ability.spell = ai.Target.RegisterSpell(spellConfig)
+	return ability
+}
+
+func (ai *ScriptedAI) Reset(sim *Simulation) {
+	ai.phaseStartedAt = 0
+	ai.waiting = false
+	if len(ai.phases) > 1 {
+		sim.RegisterPhaseCallback(ai.onPhase)
+	}
+}
+
+// Index of the script phase for the given encounter phase. The last script
+// phase lasts until the end, even if the encounter has more phases.
+func (ai *ScriptedAI) phaseIndex(phase int) int {
+	return MinInt(phase, len(ai.phases)) - 1
+}
+
+// Name of the current phase, or "" for scripts without phases.
+func (ai *ScriptedAI) PhaseName(sim *Simulation) string {
+	if len(ai.phases) == 0 {
+		return ""
+	}
+	return ai.phases[ai.phaseIndex(sim.CurrentPhase())].config.Name
+}
+
+// Returns when the given phase starts because of time, in duration based
+// fights also counting its health condition.
+func (ai *ScriptedAI) phaseStartTime(sim *Simulation, phase *scriptedPhase) time.Duration {
+	startAt := NeverExpires
+	if phase.config.StartSeconds > 0 {
+		startAt = DurationFromSeconds(phase.config.StartSeconds)
+	}
+	if phase.config.StartHealthPercent > 0 && sim.Encounter.EndFightAtHealth == 0 {
+		healthStartAt := time.Duration(float64(sim.Duration) * (1 - phase.config.StartHealthPercent))
+		startAt = MinDuration(startAt, healthStartAt)
+	}
+	return startAt
+}
+
+// Starts the next encounter phase once its condition in the script is met.
+func (ai *ScriptedAI) updatePhase(sim *Simulation) {
+	for sim.CurrentPhase() < len(ai.phases) {
+		next := ai.phases[sim.CurrentPhase()]
+		started := sim.CurrentTime >= ai.phaseStartTime(sim, next) ||
+			(next.config.StartHealthPercent > 0 && sim.Encounter.EndFightAtHealth > 0 &&
+				sim.GetRemainingDurationPercent() <= next.config.StartHealthPercent)
+		if !started {
+			return
+		}
+		sim.startPhase(sim.CurrentPhase() + 1)
+	}
+}
+
+// Switches to the script phase of a new encounter phase, whether this script,
+// another one or the encounter's phase start times began it.
+func (ai *ScriptedAI) onPhase(sim *Simulation, phase int) {
+	index := ai.phaseIndex(phase)
+	if index == ai.phaseIndex(phase-1) {
+		return
+	}
+
+	for _, aura := range ai.phases[ai.phaseIndex(phase-1)].auras {
+		aura.Deactivate(sim)
+	}
+	ai.phaseStartedAt = sim.CurrentTime
+	if sim.Log != nil {
+		ai.Target.Log(sim, "Starting encounter phase %d (%s)", index+1, ai.phases[index].config.Name)
+	}
+	for _, aura := range ai.phases[index].auras {
+		aura.Activate(sim)
+	}
+
+	if ai.waiting && ai.Target.IsEnabled() {
+		ai.waiting = false
+		ai.Target.WaitUntil(sim, sim.CurrentTime)
+	}
+}
+
+func (ai *ScriptedAI) abilityTarget(sim *Simulation, ability *scriptedAbility) *Unit {
+	switch ability.config.Targeting {
+	case proto.EncounterTargeting_EncounterTargetingRandomRaidMember:
+		if len(ai.players) == 0 {
+			return nil
+		}
+		return ai.players[int(sim.RandomFloat("Encounter Random Target")*float64(len(ai.players)))]
+	case proto.EncounterTargeting_EncounterTargetingAllRaidMembers:
+		// ApplyEffects hits every player, the target only has to be valid.
+		if len(ai.players) == 0 {
+			return nil
+		}
+		return ai.players[0]
+	default:
+		return ai.Target.CurrentTarget
+	}
+}
+
+func (ai *ScriptedAI) DoAction(sim *Simulation) {
+	if len(ai.phases) == 0 {
+		ai.Target.DoNothing()
+		return
+	}
+
+	ai.waiting = false
+	ai.updatePhase(sim)
+	phase := ai.phases[ai.phaseIndex(sim.CurrentPhase())]
+
+	nextEventAt := sim.CurrentTime + time.Minute
+	for _, ability := range phase.abilities {
+		readyAt := MaxDuration(ability.spell.ReadyAt(), ai.phaseStartedAt+ability.initialCD)
+		if readyAt > sim.CurrentTime {
+			nextEventAt = MinDuration(nextEventAt, readyAt)
+			continue
+		}
+
+		// Tank abilities wait while nobody is tanking.
+		target := ai.abilityTarget(sim, ability)
+		if target == nil {
+			continue
+		}
+
+		if sim.Proc(ability.chanceToUse, "Encounter Ability") {
+			ability.spell.Cast(sim, target)
+			return
+		}
+		nextEventAt = MinDuration(nextEventAt, sim.CurrentTime+scriptedAIPollInterval)
+	}
+
+	if sim.CurrentPhase() < len(ai.phases) {
+		next := ai.phases[sim.CurrentPhase()]
+		nextEventAt = MinDuration(nextEventAt, ai.phaseStartTime(sim, next))
+		if next.config.StartHealthPercent > 0 && sim.Encounter.EndFightAtHealth > 0 {
+			nextEventAt = MinDuration(nextEventAt, sim.CurrentTime+scriptedAIPollInterval)
+		}
+	}
+
+	ai.waiting = true
+	ai.Target.WaitUntil(sim, nextEventAt)
+}
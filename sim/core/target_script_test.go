@@ -0,0 +1,107 @@
+package core
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+	"github.com/wowsims/wotlk/sim/core/stats"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestEncounterScript(t *testing.T) {
+	script := &proto.EncounterScript{}
+	err := protojson.Unmarshal([]byte(`{
+		"phases": [{
+			"name": "Ground",
+			"abilities": [{
+				"spellId": 1001, "melee": true, "minDamage": 2000, "maxDamage": 3000, "cooldownSeconds": 8,
+				"aura": {"spellId": 1002, "durationSeconds": 10, "damageTakenMultiplier": 1.1}
+			}]
+		}, {
+			"name": "Enraged",
+			"startSeconds": 30,
+			"buffs": [{"spellId": 1003, "damageDealtMultiplier": 1.5}],
+			"abilities": [{
+				"spellId": 1004, "spellSchool": "SpellSchoolShadow", "minDamage": 1000, "maxDamage": 1000,
+				"castTimeSeconds": 2, "cooldownSeconds": 10, "targeting": "EncounterTargetingAllRaidMembers"
+			}]
+		}]
+	}`), script)
+	if err != nil {
+		t.Fatalf("Failed to parse script: %s", err)
+	}
+
+	boss := newTestTarget(0)
+	boss.Script = script
+	// Joins the fight with the script's second phase.
+	add := newTestAttackingTarget(1000)
+	add.Spawn = &proto.TargetSpawn{SpawnPhase: 2}
+
+	players := []*proto.Player{
+		newTestTank("Tank", stats.Stats{}),
+		newTestCaster("Caster", "actions+=/cast_spell,id=2,if=current_phase=2"),
+	}
+	runScript := func(phaseStartSeconds []float64) *proto.RaidSimResult {
+		rsr := newTestRaidSimRequest(players, &proto.Encounter{
+			Duration:          60,
+			Targets:           []*proto.Target{boss, add},
+			PhaseStartSeconds: phaseStartSeconds,
+		}, 20)
+		return runTestRaidSim(t, rsr)
+	}
+
+	checkPhase := func(result *proto.RaidSimResult, phaseStart float64, numCasts int32) {
+		t.Helper()
+		tank := result.RaidMetrics.Parties[0].Players[0]
+		caster := result.RaidMetrics.Parties[0].Players[1]
+		iterations := int32(20)
+
+		casts := map[int32]int32{}
+		addSwings := int32(0)
+		for _, damageTaken := range tank.DamageTaken {
+			count := damageTaken.Hits + damageTaken.Crits + damageTaken.Crushes + damageTaken.Blocks + damageTaken.CritBlocks +
+				damageTaken.Glances + damageTaken.Dodges + damageTaken.Parries + damageTaken.Misses
+			if damageTaken.SourceIndex == 1 {
+				addSwings += count
+			} else {
+				casts[damageTaken.Id.GetSpellId()] += count
+			}
+		}
+		if casts[1001] == 0 {
+			t.Fatalf("Expected the first phase ability to be used")
+		}
+		// Cast every 10s from the start of the second phase, except for one
+		// which wouldn't finish before the end of the fight.
+		if expected := numCasts * iterations; casts[1004] != expected {
+			t.Fatalf("Expected %d casts of the second phase ability, got %d", expected, casts[1004])
+		}
+		// The add spawns with the phase and swings every 2s.
+		if expected := int32((60-phaseStart)/2+1) * iterations; addSwings != expected {
+			t.Fatalf("Expected %d swings from the add, got %d", expected, addSwings)
+		}
+
+		auras := map[int32]float64{}
+		for _, aura := range tank.Auras {
+			auras[aura.Id.GetSpellId()] = aura.UptimeSecondsAvg
+		}
+		for _, aura := range result.EncounterMetrics.Targets[0].Auras {
+			auras[aura.Id.GetSpellId()] = aura.UptimeSecondsAvg
+		}
+		if auras[1002] <= 0 {
+			t.Fatalf("Expected the ability debuff on the tank")
+		}
+		if math.Abs(auras[1003]-(60-phaseStart)) > 0.001 {
+			t.Fatalf("Expected the enrage for the last %0.0fs, got %0.3fs", 60-phaseStart, auras[1003])
+		}
+
+		// The caster shocks every 1.5s once current_phase reaches 2.
+		if expected := 500 * ((60-phaseStart)/1.5 + 1) / 60; math.Abs(caster.Dps.Avg-expected) > 0.001 {
+			t.Fatalf("Expected %0.3f dps from the second phase on, got %0.3f", expected, caster.Dps.Avg)
+		}
+	}
+
+	checkPhase(runScript(nil), 30, 3)
+	// Encounter phase start times also move the script on.
+	checkPhase(runScript([]float64{21}), 21, 4)
+}
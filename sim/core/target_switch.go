@@ -0,0 +1,111 @@
+package core
+
+import (
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// Invoked when the unit's target is switched for it, e.g. when the raid moves
+// on to an add, so that debuffs like Hunter's Mark and Sunder Armor can be
+// applied to the new target.
+type TargetSwitchHandler func(sim *Simulation, oldTarget *Unit, newTarget *Unit)
+
+func (unit *Unit) RegisterTargetSwitchHandler(handler TargetSwitchHandler) {
+	unit.targetSwitchHandlers = append(unit.targetSwitchHandlers, handler)
+}
+
+func (unit *Unit) switchTarget(sim *Simulation, newTarget *Unit) {
+	oldTarget := unit.CurrentTarget
+	unit.CurrentTarget = newTarget
+	for _, handler := range unit.targetSwitchHandlers {
+		handler(sim, oldTarget, newTarget)
+	}
+	// Dots and debuffs are missing on the new target, and rotations held
+	// while the old one couldn't be attacked have something to do again.
+	unit.wakeUpRotation(sim)
+}
+
+// Whether the raid moves this unit along when it switches targets. Tanks stay
+// on the targets they are tanking, and healers on their friendly targets.
+func (encounter *Encounter) followsRaidTarget(unit *Unit) bool {
+	return unit.CurrentTarget != nil && unit.IsOpponent(unit.CurrentTarget) && !encounter.isTanking(unit)
+}
+
+// The target the raid is attacking.
+func (encounter *Encounter) RaidTarget() *Target {
+	return encounter.raidTarget
+}
+
+func (encounter *Encounter) resetRaidTarget(sim *Simulation) {
+	encounter.focusTarget = encounter.Targets[0]
+
+	for _, targetSwitch := range encounter.targetSwitches {
+		focusTarget := encounter.Targets[targetSwitch.TargetIndex]
+		StartDelayedAction(sim, DelayedActionOptions{
+			DoAt:     DurationFromSeconds(targetSwitch.AtSeconds),
+			Priority: ActionPriorityDOT,
+			OnAction: func(sim *Simulation) {
+				encounter.focusTarget = focusTarget
+				encounter.updateRaidTarget(sim)
+			},
+		})
+	}
+}
+
+// The target the raid should attack according to the target policy. Targets
+// which can't be attacked right now are skipped.
+func (encounter *Encounter) chooseRaidTarget() *Target {
+	if encounter.targetPolicy == proto.TargetPolicy_TargetPolicyAddsFirst {
+		for i := len(encounter.ActiveTargets) - 1; i >= 0; i-- {
+			if target := encounter.ActiveTargets[i]; target.IsAdd() && target.IsTargetable() {
+				return target
+			}
+		}
+	}
+	if encounter.focusTarget.IsEnabled() && encounter.focusTarget.IsTargetable() {
+		return encounter.focusTarget
+	}
+	for _, target := range encounter.ActiveTargets {
+		if target.IsTargetable() {
+			return target
+		}
+	}
+
+	// Nothing can be attacked, e.g. during encounter downtime, so the raid
+	// stays where it is.
+	if encounter.raidTarget.IsEnabled() {
+		return encounter.raidTarget
+	}
+	return encounter.ActiveTargets[0]
+}
+
+func (encounter *Encounter) updateRaidTarget(sim *Simulation) {
+	// Targets change while the encounter resets, before the raid has.
+	if encounter.raidTarget == nil {
+		return
+	}
+
+	target := encounter.chooseRaidTarget()
+	if target == encounter.raidTarget {
+		return
+	}
+
+	if sim.Log != nil {
+		sim.Log("Raid switches to %s", target.Label)
+	}
+	encounter.raidTarget = target
+
+	for _, unit := range sim.Raid.AllUnits {
+		if unit.CurrentTarget != &target.Unit && encounter.followsRaidTarget(unit) {
+			unit.switchTarget(sim, &target.Unit)
+		}
+	}
+}
+
+func (encounter *Encounter) isTanking(unit *Unit) bool {
+	for _, target := range encounter.ActiveTargets {
+		if target.CurrentTarget == unit {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+	"github.com/wowsims/wotlk/sim/core/stats"
+)
+
+func TestEncounterTargetSwitching(t *testing.T) {
+	players := []*proto.Player{
+		newTestTank("Tank", stats.Stats{}),
+		newTestCaster("Caster", "actions+=/cast_spell,id=2"),
+		newTestHealer("Healer"),
+		newTestCaster("Legacy", ""),
+	}
+	runSwitches := func(encounter *proto.Encounter) *proto.RaidSimResult {
+		encounter.Duration = 60
+		return runTestRaidSim(t, newTestRaidSimRequest(players, encounter, 3))
+	}
+	checkDamage := func(result *proto.RaidSimResult, casterDamage []float64, legacyDamage []float64) {
+		t.Helper()
+		tank := result.RaidMetrics.Parties[0].Players[0]
+		caster := result.RaidMetrics.Parties[0].Players[1]
+		healer := result.RaidMetrics.Parties[0].Players[2]
+		legacy := result.RaidMetrics.Parties[0].Players[3]
+
+		for i, damage := range casterDamage {
+			if actual := caster.TargetDps[i].Dps.Avg * 60; math.Abs(actual-damage) > 0.001 {
+				t.Fatalf("Expected %0.0f damage from the caster to target %d, got %0.3f", damage, i, actual)
+			}
+		}
+		// Hand-written rotations follow the raid too.
+		for i, damage := range legacyDamage {
+			if actual := legacy.TargetDps[i].Dps.Avg * 60; math.Abs(actual-damage) > 0.001 {
+				t.Fatalf("Expected %0.0f damage from the hand-written rotation to target %d, got %0.3f", damage, i, actual)
+			}
+		}
+
+		// The tank keeps bolting the boss it is tanking.
+		if tank.TargetDps[0].Dps.Avg <= 0 || tank.TargetDps[1].Dps.Avg != 0 {
+			t.Fatalf("Expected the tank to stay on the boss, got %0.3f and %0.3f dps", tank.TargetDps[0].Dps.Avg, tank.TargetDps[1].Dps.Avg)
+		}
+
+		// The healer heals themselves every 1.5s. Unit indices count the
+		// targets first, then the raid.
+		healerIndex := int32(len(result.EncounterMetrics.Targets)) + 2
+		heals := int32(0)
+		for _, action := range healer.Actions {
+			for _, target := range action.Targets {
+				if target.Casts > 0 && target.UnitIndex != healerIndex {
+					t.Fatalf("Expected the healer to only heal themselves, got %s at unit %d", action.Id, target.UnitIndex)
+				}
+				heals += target.Casts
+			}
+		}
+		if heals != 41*3 {
+			t.Fatalf("Expected %d heals, got %d", 41*3, heals)
+		}
+	}
+
+	// The raid moves over to the second boss halfway through. Shocks go out
+	// every 1.5s up to the end of the fight: 20 at the first boss and 21 at
+	// the second. Bolts take 2s, so 15 land on each.
+	checkDamage(runSwitches(&proto.Encounter{
+		Targets:        []*proto.Target{newTestAttackingTarget(100), newTestTarget(0)},
+		TargetSwitches: []*proto.TargetSwitch{{AtSeconds: 30, TargetIndex: 1}},
+	}), []float64{10000, 10500}, []float64{15000, 15000})
+
+	// The raid shocks an add 6 times while it is up, from 21s to 28.5s, then
+	// goes back to the boss for the other 35. Bolts start on the add from 20s
+	// to 28s.
+	add := newTestTarget(0)
+	add.Spawn = &proto.TargetSpawn{SpawnSeconds: 20, DespawnAfterSeconds: 10}
+	checkDamage(runSwitches(&proto.Encounter{
+		Targets:      []*proto.Target{newTestAttackingTarget(100), add},
+		TargetPolicy: proto.TargetPolicy_TargetPolicyAddsFirst,
+	}), []float64{17500, 3000}, []float64{25000, 5000})
+
+	// The boss can't be attacked from 20s to 30s, so the raid moves over to an
+	// add which is up from 10s, and goes back to the boss once it can be
+	// attacked again. That hits the add as often as the previous fight.
+	boss := newTestAttackingTarget(100)
+	boss.Untargetable = []*proto.TimeWindow{{StartSeconds: 20, DurationSeconds: 10}}
+	add = newTestTarget(0)
+	add.Spawn = &proto.TargetSpawn{SpawnSeconds: 10}
+	checkDamage(runSwitches(&proto.Encounter{
+		Targets: []*proto.Target{boss, add},
+	}), []float64{17500, 3000}, []float64{25000, 5000})
+}
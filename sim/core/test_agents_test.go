@@ -0,0 +1,283 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+	"github.com/wowsims/wotlk/sim/core/stats"
+)
+
+// Agents with fixed, unresisted spells, so that sim results can be worked out
+// by hand. They stand in for the Mage, Protection Warrior and Healing Priest
+// specs, which aren't registered in core tests.
+func init() {
+	RegisterAgentFactory(
+		proto.Player_Mage{},
+		proto.Spec_SpecMage,
+		func(character Character, options *proto.Player) Agent {
+			caster := &testCaster{Character: character}
+			// Casters given mana in their bonus stats keep exactly that much, and
+			// never regenerate any since they have no spirit or intellect.
+			if mana := caster.GetStat(stats.Mana); mana > 0 {
+				caster.EnableManaBar()
+				caster.AddStat(stats.Mana, mana-caster.GetStat(stats.Mana))
+			}
+			return caster
+		},
+		func(player *proto.Player, spec interface{}) {
+			player.Spec = spec.(*proto.Player_Mage)
+		},
+	)
+	RegisterAgentFactory(
+		proto.Player_ProtectionWarrior{},
+		proto.Spec_SpecProtectionWarrior,
+		func(character Character, options *proto.Player) Agent {
+			tank := &testCaster{Character: character}
+			tank.PseudoStats.CanBlock = true
+			tank.PseudoStats.CanParry = true
+			return tank
+		},
+		func(player *proto.Player, spec interface{}) {
+			player.Spec = spec.(*proto.Player_ProtectionWarrior)
+		},
+	)
+	RegisterAgentFactory(
+		proto.Player_HealingPriest{},
+		proto.Spec_SpecHealingPriest,
+		func(character Character, options *proto.Player) Agent {
+			return &testCaster{Character: character, isHealer: true}
+		},
+		func(player *proto.Player, spec interface{}) {
+			player.Spec = spec.(*proto.Player_HealingPriest)
+		},
+	)
+}
+
+const (
+	testBoltID   = 1 // 1000 damage, 2s cast.
+	testShockID  = 2 // 500 damage, instant.
+	testHealID   = 3 // 1000 healing, instant.
+	testRollID   = 4 // 500 to 1500 damage, instant.
+	testShieldID = 5 // Shields the caster for 3000 damage for 60s, instant.
+	testBlastID  = 6 // 2000 damage, 1s cast, 10s cooldown and a 5s shared cooldown. Costs 100 mana if the caster has any.
+)
+
+type testCaster struct {
+	Character
+
+	isHealer bool
+
+	bolt  *Spell
+	shock *Spell
+	heal  *Spell
+	roll  *Spell
+	blast *Spell
+
+	shield *Shield
+}
+
+func (tc *testCaster) GetCharacter() *Character {
+	return &tc.Character
+}
+
+func (tc *testCaster) Initialize() {
+	if tc.isHealer {
+		// Like real healers, heal the first target dummy or themselves.
+		tc.CurrentTarget = &tc.Unit
+		if dummy := tc.Env.Raid.GetFirstTargetDummy(); dummy != nil {
+			tc.CurrentTarget = &dummy.Unit
+		}
+	}
+
+	damageSpell := func(actionID ActionID, castTime time.Duration, minDamage float64, maxDamage float64) SpellConfig {
+		return SpellConfig{
+			ActionID:    actionID,
+			SpellSchool: SpellSchoolArcane,
+			ProcMask:    ProcMaskSpellDamage,
+			Flags:       SpellFlagIgnoreResists | SpellFlagAPL,
+
+			Cast: CastConfig{
+				DefaultCast: Cast{
+					GCD:      GCDDefault,
+					CastTime: castTime,
+				},
+			},
+
+			DamageMultiplier: 1,
+			CritMultiplier:   1,
+			ThreatMultiplier: 1,
+
+			ApplyEffects: func(sim *Simulation, target *Unit, spell *Spell) {
+				spell.CalcAndDealDamage(sim, target, sim.Roll(minDamage, maxDamage), spell.OutcomeAlwaysHit)
+			},
+		}
+	}
+	tc.bolt = tc.RegisterSpell(damageSpell(ActionID{SpellID: testBoltID}, time.Second*2, 1000, 1000))
+	tc.shock = tc.RegisterSpell(damageSpell(ActionID{SpellID: testShockID}, 0, 500, 500))
+	tc.roll = tc.RegisterSpell(damageSpell(ActionID{SpellID: testRollID}, 0, 500, 1500))
+
+	blastConfig := damageSpell(ActionID{SpellID: testBlastID}, time.Second, 2000, 2000)
+	if tc.HasManaBar() {
+		blastConfig.ManaCost = ManaCostOptions{FlatCost: 100}
+	}
+	blastConfig.Cast.CD = Cooldown{
+		Timer:    tc.NewTimer(),
+		Duration: time.Second * 10,
+	}
+	blastConfig.Cast.SharedCD = Cooldown{
+		Timer:    tc.NewTimer(),
+		Duration: time.Second * 5,
+	}
+	tc.blast = tc.RegisterSpell(blastConfig)
+
+	tc.heal = tc.RegisterSpell(SpellConfig{
+		ActionID:    ActionID{SpellID: testHealID},
+		SpellSchool: SpellSchoolNature,
+		ProcMask:    ProcMaskSpellHealing,
+		Flags:       SpellFlagHelpful | SpellFlagAPL,
+
+		Cast: CastConfig{
+			DefaultCast: Cast{
+				GCD: GCDDefault,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   1,
+		ThreatMultiplier: 1,
+
+		ApplyEffects: func(sim *Simulation, target *Unit, spell *Spell) {
+			spell.CalcAndDealHealing(sim, target, 1000, spell.OutcomeHealing)
+		},
+	})
+
+	tc.shield = NewShield(Shield{
+		Spell: tc.RegisterSpell(SpellConfig{
+			ActionID:    ActionID{SpellID: testShieldID},
+			SpellSchool: SpellSchoolHoly,
+			ProcMask:    ProcMaskSpellHealing,
+			Flags:       SpellFlagHelpful | SpellFlagAPL,
+
+			Cast: CastConfig{
+				DefaultCast: Cast{
+					GCD: GCDDefault,
+				},
+			},
+
+			DamageMultiplier: 1,
+			ThreatMultiplier: 1,
+
+			ApplyEffects: func(sim *Simulation, _ *Unit, spell *Spell) {
+				tc.shield.Apply(sim, 3000)
+			},
+		}),
+		Aura: tc.RegisterAura(Aura{
+			Label:    "Test Shield",
+			ActionID: ActionID{SpellID: testShieldID},
+			Duration: time.Second * 60,
+		}),
+	})
+}
+
+func (tc *testCaster) AddRaidBuffs(raidBuffs *proto.RaidBuffs)    {}
+func (tc *testCaster) AddPartyBuffs(partyBuffs *proto.PartyBuffs) {}
+func (tc *testCaster) ApplyTalents()                              {}
+func (tc *testCaster) Reset(sim *Simulation)                      {}
+func (tc *testCaster) OnAutoAttack(sim *Simulation, spell *Spell) {}
+
+// Without an APL, healers heal their target and everyone else casts bolts at
+// theirs, retrying shortly if they can't.
+func (tc *testCaster) OnGCDReady(sim *Simulation) {
+	spell := tc.bolt
+	if tc.isHealer {
+		spell = tc.heal
+	}
+	if !spell.Cast(sim, tc.CurrentTarget) {
+		tc.WaitUntil(sim, sim.CurrentTime+time.Millisecond*500)
+	}
+}
+
+func newTestCaster(name string, rotation string) *proto.Player {
+	player := &proto.Player{
+		Name:      name,
+		Race:      proto.Race_RaceHuman,
+		Class:     proto.Class_ClassMage,
+		Equipment: &proto.EquipmentSpec{},
+		Spec:      &proto.Player_Mage{Mage: &proto.Mage{}},
+	}
+	if rotation != "" {
+		player.Rotation = APLRotationFromTextString(rotation)
+	}
+	return player
+}
+
+func newTestTank(name string, bonusStats stats.Stats) *proto.Player {
+	return &proto.Player{
+		Name:            name,
+		Race:            proto.Race_RaceHuman,
+		Class:           proto.Class_ClassWarrior,
+		Equipment:       &proto.EquipmentSpec{},
+		Spec:            &proto.Player_ProtectionWarrior{ProtectionWarrior: &proto.ProtectionWarrior{}},
+		BonusStats:      &proto.UnitStats{Stats: bonusStats.ToFloatArray()},
+		InFrontOfTarget: true,
+	}
+}
+
+func newTestHealer(name string) *proto.Player {
+	return &proto.Player{
+		Name:      name,
+		Race:      proto.Race_RaceHuman,
+		Class:     proto.Class_ClassPriest,
+		Equipment: &proto.EquipmentSpec{},
+		Spec:      &proto.Player_HealingPriest{HealingPriest: &proto.HealingPriest{}},
+	}
+}
+
+// A target without armor or attacks.
+func newTestTarget(health float64) *proto.Target {
+	return &proto.Target{
+		Level:   CharacterLevel + 3,
+		Stats:   stats.Stats{stats.Health: health}.ToFloatArray(),
+		MobType: proto.MobType_MobTypeDemon,
+	}
+}
+
+// A target which swings at its tank every 2s.
+func newTestAttackingTarget(minBaseDamage float64) *proto.Target {
+	target := newTestTarget(0)
+	target.SwingSpeed = 2
+	target.MinBaseDamage = minBaseDamage
+	return target
+}
+
+// Targets attack the first tank, unless their TankIndex says otherwise.
+func newTestRaidSimRequest(players []*proto.Player, encounter *proto.Encounter, iterations int32) *proto.RaidSimRequest {
+	var tanks []*proto.RaidTarget
+	for i, player := range players {
+		if _, ok := player.Spec.(*proto.Player_ProtectionWarrior); ok {
+			tanks = append(tanks, &proto.RaidTarget{TargetIndex: int32(i)})
+		}
+	}
+	return &proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{Players: players}},
+			Tanks:   tanks,
+		},
+		Encounter: encounter,
+		SimOptions: &proto.SimOptions{
+			Iterations: iterations,
+			RandomSeed: 101,
+			NumWorkers: 1,
+		},
+	}
+}
+
+func runTestRaidSim(t *testing.T, rsr *proto.RaidSimRequest) *proto.RaidSimResult {
+	t.Helper()
+	result := RunRaidSim(rsr)
+	if result.ErrorResult != "" {
+		t.Fatalf("Sim failed with error: %s", result.ErrorResult)
+	}
+	return result
+}
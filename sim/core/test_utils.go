@@ -2,6 +2,7 @@ package core
 
 import (
 	"log"
+	"math"
 	"testing"
 
 	"github.com/wowsims/wotlk/sim/core/proto"
@@ -233,3 +234,90 @@ func RaidBenchmark(b *testing.B, rsr *proto.RaidSimRequest) {
 		}
 	}
 }
+
+// A built-in APL rotation, and the player to compare it with the hand-written
+// rotation on.
+type APLPresetTestCase struct {
+	Preset string
+	Player *proto.Player
+	IsTank bool
+
+	// Allowed difference from the hand-written rotation, as a fraction of its
+	// output. Defaults to 3%.
+	Tolerance float64
+}
+
+// Checks that each APL preset performs like the hand-written rotation it
+// replaces, on a single target with full buffs. Healers are compared by HPS,
+// everyone else by DPS. Specs without any output are compared by the number
+// of times each action is used instead.
+func RunAPLPresetTests(t *testing.T, testCases []APLPresetTestCase) {
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.Preset, func(t *testing.T) {
+			player := googleProto.Clone(testCase.Player).(*proto.Player)
+			player.Buffs = FullIndividualBuffs
+			raid := SinglePlayerRaidProto(player, FullPartyBuffs, FullRaidBuffs, FullDebuffs)
+			if testCase.IsTank {
+				raid.Tanks = append(raid.Tanks, &proto.RaidTarget{TargetIndex: 0})
+			}
+			tolerance := testCase.Tolerance
+			if tolerance == 0 {
+				tolerance = 0.03
+			}
+
+			runSim := func(rotation *proto.APLRotation) *proto.UnitMetrics {
+				raid := googleProto.Clone(raid).(*proto.Raid)
+				raid.Parties[0].Players[0].Rotation = rotation
+				result := RunRaidSim(&proto.RaidSimRequest{
+					Raid:       raid,
+					Encounter:  MakeSingleTargetEncounter(5),
+					SimOptions: AverageDefaultSimTestOptions,
+				})
+				if result.ErrorResult != "" {
+					t.Fatalf("Sim failed with error: %s", result.ErrorResult)
+				}
+				return result.RaidMetrics.Parties[0].Players[0]
+			}
+
+			handWritten := runSim(nil)
+			preset := runSim(&proto.APLRotation{Enabled: true, Preset: testCase.Preset})
+
+			metricName, expected, actual := "dps", handWritten.Dps.Avg, preset.Dps.Avg
+			if handWritten.Hps.Avg > handWritten.Dps.Avg {
+				metricName, expected, actual = "hps", handWritten.Hps.Avg, preset.Hps.Avg
+			}
+			t.Logf("APL preset %s: %0.2f %s, hand-written rotation: %0.2f %s", testCase.Preset, actual, metricName, expected, metricName)
+			if math.Abs(actual-expected) > expected*tolerance {
+				t.Fatalf("APL preset %s has %0.2f %s, expected %0.2f from the hand-written rotation (tolerance %0.1f%%)", testCase.Preset, actual, metricName, expected, tolerance*100)
+			}
+
+			if expected == 0 && actual == 0 {
+				expectedCasts, actualCasts := aplPresetTestCasts(handWritten), aplPresetTestCasts(preset)
+				for actionID, casts := range expectedCasts {
+					if math.Abs(float64(actualCasts[actionID]-casts)) > float64(casts)*tolerance {
+						t.Fatalf("APL preset %s used %s %d times, expected %d from the hand-written rotation", testCase.Preset, actionID, actualCasts[actionID], casts)
+					}
+				}
+				for actionID, casts := range actualCasts {
+					if expectedCasts[actionID] == 0 {
+						t.Fatalf("APL preset %s used %s %d times, which the hand-written rotation never uses", testCase.Preset, actionID, casts)
+					}
+				}
+			}
+		})
+	}
+}
+
+// Number of casts of each action, keyed by its ActionID.
+func aplPresetTestCasts(unitMetrics *proto.UnitMetrics) map[ActionID]int32 {
+	casts := make(map[ActionID]int32)
+	for _, action := range unitMetrics.Actions {
+		for _, target := range action.Targets {
+			if target.Casts > 0 {
+				casts[ProtoToActionID(action.Id)] += target.Casts
+			}
+		}
+	}
+	return casts
+}
@@ -0,0 +1,298 @@
+package core
+
+import (
+	"time"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+	"github.com/wowsims/wotlk/sim/core/stats"
+)
+
+// Metrics split into fixed-size buckets of the fight, for 1 unit. Values are
+// summed across iterations as they happen, and averaged in ToProto().
+type timelineMetrics struct {
+	bucketSize time.Duration
+
+	// Aggregate values, grown as iterations reach more buckets.
+	iterations  []int32   // Iterations which reached each bucket.
+	coveredTime []float64 // Seconds of each bucket covered by those iterations.
+	damage      []float64
+
+	actions     []*actionTimeline
+	actionIndex map[ActionID]int
+	auras       []*auraTimeline
+	auraIndex   map[ActionID]int
+	resources   []*resourceTimeline
+}
+
+type actionTimeline struct {
+	actionID ActionID
+	damage   []float64
+}
+
+type auraTimeline struct {
+	actionID ActionID
+	uptime   []float64 // Seconds active in each bucket.
+}
+
+type resourceTimeline struct {
+	resourceType proto.ResourceType
+	currentValue func() float64
+
+	// First bucket whose starting value hasn't been recorded yet, in the
+	// current iteration.
+	nextBucket int
+	values     []float64
+}
+
+func newTimelineMetrics(bucketSize time.Duration) *timelineMetrics {
+	return &timelineMetrics{
+		bucketSize:  bucketSize,
+		actionIndex: make(map[ActionID]int),
+		auraIndex:   make(map[ActionID]int),
+	}
+}
+
+// Enables timeline metrics for all units, if requested in the options.
+func (sim *Simulation) enableTimelineMetrics() {
+	if sim.Options.TimelineBucketSeconds <= 0 {
+		return
+	}
+	bucketSize := DurationFromSeconds(sim.Options.TimelineBucketSeconds)
+
+	for _, unit := range sim.AllUnits {
+		if unit.Metrics.timeline != nil {
+			continue
+		}
+		unit.Metrics.timeline = newTimelineMetrics(bucketSize)
+		unit.trackTimelineResources()
+	}
+}
+
+func (unit *Unit) trackTimelineResources() {
+	timeline := unit.Metrics.timeline
+	if unit.Type == EnemyUnit {
+		target := unit.Env.Encounter.Targets[unit.Index]
+		if target.stats[stats.Health] > 0 {
+			timeline.trackResource(proto.ResourceType_ResourceTypeHealth, target.RemainingHealth)
+		}
+		return
+	}
+
+	if unit.HasManaBar() {
+		timeline.trackResource(proto.ResourceType_ResourceTypeMana, unit.CurrentMana)
+	}
+	if unit.HasRageBar() {
+		timeline.trackResource(proto.ResourceType_ResourceTypeRage, unit.CurrentRage)
+	}
+	if unit.HasEnergyBar() {
+		timeline.trackResource(proto.ResourceType_ResourceTypeEnergy, unit.CurrentEnergy)
+	}
+	if unit.HasFocusBar() {
+		timeline.trackResource(proto.ResourceType_ResourceTypeFocus, unit.CurrentFocus)
+	}
+	if unit.HasRunicPowerBar() {
+		timeline.trackResource(proto.ResourceType_ResourceTypeRunicPower, unit.CurrentRunicPower)
+	}
+}
+
+func (timeline *timelineMetrics) trackResource(resourceType proto.ResourceType, currentValue func() float64) {
+	timeline.resources = append(timeline.resources, &resourceTimeline{
+		resourceType: resourceType,
+		currentValue: currentValue,
+	})
+}
+
+func (timeline *timelineMetrics) bucket(t time.Duration) int {
+	if t < 0 {
+		return 0
+	}
+	return int(t / timeline.bucketSize)
+}
+
+// Returns values, extended with zeroes to at least n elements.
+func growTimeline(values []float64, n int) []float64 {
+	if len(values) < n {
+		values = append(values, make([]float64, n-len(values))...)
+	}
+	return values
+}
+
+func (timeline *timelineMetrics) addDamage(sim *Simulation, actionID ActionID, damage float64) {
+	// Damage at the exact end of a bucket counts towards it, like damage at
+	// the end of the fight does for the last bucket.
+	bucket := timeline.bucket(sim.CurrentTime - 1)
+	timeline.damage = growTimeline(timeline.damage, bucket+1)
+	timeline.damage[bucket] += damage
+
+	index, ok := timeline.actionIndex[actionID]
+	if !ok {
+		index = len(timeline.actions)
+		timeline.actionIndex[actionID] = index
+		timeline.actions = append(timeline.actions, &actionTimeline{actionID: actionID})
+	}
+	action := timeline.actions[index]
+	action.damage = growTimeline(action.damage, bucket+1)
+	action.damage[bucket] += damage
+}
+
+// Adds the time between start and end, spread over the buckets it covers.
+func (timeline *timelineMetrics) addAuraUptime(actionID ActionID, start time.Duration, end time.Duration) {
+	start = MaxDuration(start, 0)
+	if end <= start {
+		return
+	}
+
+	index, ok := timeline.auraIndex[actionID]
+	if !ok {
+		index = len(timeline.auras)
+		timeline.auraIndex[actionID] = index
+		timeline.auras = append(timeline.auras, &auraTimeline{actionID: actionID})
+	}
+	aura := timeline.auras[index]
+
+	lastBucket := timeline.bucket(end - 1)
+	aura.uptime = growTimeline(aura.uptime, lastBucket+1)
+	for bucket := timeline.bucket(start); bucket <= lastBucket; bucket++ {
+		bucketStart := time.Duration(bucket) * timeline.bucketSize
+		overlap := MinDuration(end, bucketStart+timeline.bucketSize) - MaxDuration(start, bucketStart)
+		aura.uptime[bucket] += overlap.Seconds()
+	}
+}
+
+// Should be called right before a tracked resource changes, so the levels at
+// the start of any buckets passed since the last change can be recorded.
+func (timeline *timelineMetrics) beforeResourceChange(sim *Simulation, resourceType proto.ResourceType) {
+	if sim.CurrentTime < 0 {
+		return
+	}
+	for _, resource := range timeline.resources {
+		if resource.resourceType == resourceType {
+			resource.recordUntil(timeline.bucket(sim.CurrentTime))
+			return
+		}
+	}
+}
+
+// Records the current value as the starting level of all buckets up to and
+// including lastBucket.
+func (resource *resourceTimeline) recordUntil(lastBucket int) {
+	if lastBucket < resource.nextBucket {
+		return
+	}
+	value := resource.currentValue()
+	resource.values = growTimeline(resource.values, lastBucket+1)
+	for bucket := resource.nextBucket; bucket <= lastBucket; bucket++ {
+		resource.values[bucket] += value
+	}
+	resource.nextBucket = lastBucket + 1
+}
+
+// This should be called when a Sim iteration is complete.
+func (timeline *timelineMetrics) doneIteration(sim *Simulation) {
+	end := sim.CurrentTime
+	if end <= 0 {
+		return
+	}
+	numBuckets := timeline.bucket(end-1) + 1
+
+	for len(timeline.iterations) < numBuckets {
+		timeline.iterations = append(timeline.iterations, 0)
+	}
+	timeline.coveredTime = growTimeline(timeline.coveredTime, numBuckets)
+	for bucket := 0; bucket < numBuckets; bucket++ {
+		bucketStart := time.Duration(bucket) * timeline.bucketSize
+		timeline.iterations[bucket]++
+		timeline.coveredTime[bucket] += (MinDuration(end, bucketStart+timeline.bucketSize) - bucketStart).Seconds()
+	}
+
+	for _, resource := range timeline.resources {
+		resource.recordUntil(numBuckets - 1)
+		resource.nextBucket = 0
+	}
+}
+
+// Adds the aggregate values of another timelineMetrics, for the same unit in
+// a Simulation built from the same request.
+func (timeline *timelineMetrics) merge(other *timelineMetrics) {
+	for len(timeline.iterations) < len(other.iterations) {
+		timeline.iterations = append(timeline.iterations, 0)
+	}
+	for i, iterations := range other.iterations {
+		timeline.iterations[i] += iterations
+	}
+	timeline.coveredTime = mergeTimeline(timeline.coveredTime, other.coveredTime)
+	timeline.damage = mergeTimeline(timeline.damage, other.damage)
+
+	for _, otherAction := range other.actions {
+		if index, ok := timeline.actionIndex[otherAction.actionID]; ok {
+			action := timeline.actions[index]
+			action.damage = mergeTimeline(action.damage, otherAction.damage)
+		} else {
+			timeline.actionIndex[otherAction.actionID] = len(timeline.actions)
+			timeline.actions = append(timeline.actions, otherAction)
+		}
+	}
+	for _, otherAura := range other.auras {
+		if index, ok := timeline.auraIndex[otherAura.actionID]; ok {
+			aura := timeline.auras[index]
+			aura.uptime = mergeTimeline(aura.uptime, otherAura.uptime)
+		} else {
+			timeline.auraIndex[otherAura.actionID] = len(timeline.auras)
+			timeline.auras = append(timeline.auras, otherAura)
+		}
+	}
+	for i, resource := range timeline.resources {
+		resource.values = mergeTimeline(resource.values, other.resources[i].values)
+	}
+}
+
+func mergeTimeline(values []float64, other []float64) []float64 {
+	values = growTimeline(values, len(other))
+	for i, value := range other {
+		values[i] += value
+	}
+	return values
+}
+
+func (timeline *timelineMetrics) ToProto() *proto.TimelineMetrics {
+	numBuckets := len(timeline.iterations)
+	perIteration := func(sums []float64) []float64 {
+		averages := make([]float64, numBuckets)
+		for i := range sums {
+			if i < numBuckets && timeline.iterations[i] > 0 {
+				averages[i] = sums[i] / float64(timeline.iterations[i])
+			}
+		}
+		return averages
+	}
+
+	protoTimeline := &proto.TimelineMetrics{
+		BucketSeconds: timeline.bucketSize.Seconds(),
+		Damage:        perIteration(timeline.damage),
+	}
+	for _, action := range timeline.actions {
+		protoTimeline.Actions = append(protoTimeline.Actions, &proto.ActionTimelineMetrics{
+			Id:     action.actionID.ToProto(),
+			Damage: perIteration(action.damage),
+		})
+	}
+	for _, resource := range timeline.resources {
+		protoTimeline.Resources = append(protoTimeline.Resources, &proto.ResourceTimelineMetrics{
+			Type:   resource.resourceType,
+			Values: perIteration(resource.values),
+		})
+	}
+	for _, aura := range timeline.auras {
+		uptime := make([]float64, numBuckets)
+		for i := range aura.uptime {
+			if i < numBuckets && timeline.coveredTime[i] > 0 {
+				uptime[i] = aura.uptime[i] / timeline.coveredTime[i]
+			}
+		}
+		protoTimeline.Auras = append(protoTimeline.Auras, &proto.AuraTimelineMetrics{
+			Id:     aura.actionID.ToProto(),
+			Uptime: uptime,
+		})
+	}
+	return protoTimeline
+}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+func TestTimelineMetrics(t *testing.T) {
+	rsr := newTestRaidSimRequest([]*proto.Player{newTestCaster("Caster", "")}, &proto.Encounter{
+		Duration: 20,
+		Targets:  []*proto.Target{newTestTarget(100_000)},
+	}, 3)
+	rsr.SimOptions.TimelineBucketSeconds = 5
+
+	result := runTestRaidSim(t, rsr)
+
+	// Bolts land every 2s, including one at the end of the fight.
+	expectedDamage := []float64{2000, 3000, 2000, 3000}
+	timeline := result.RaidMetrics.Parties[0].Players[0].Timeline
+	if !reflect.DeepEqual(timeline.Damage, expectedDamage) {
+		t.Fatalf("Expected timeline damage %v, got %v", expectedDamage, timeline.Damage)
+	}
+	if len(timeline.Actions) != 1 || !reflect.DeepEqual(timeline.Actions[0].Damage, expectedDamage) {
+		t.Fatalf("Expected bolt damage %v, got %v", expectedDamage, timeline.Actions)
+	}
+	if len(timeline.Resources) != 0 {
+		t.Fatalf("Expected no resource timelines, got %v", timeline.Resources)
+	}
+
+	// Health at the start of each bucket.
+	expectedHealth := []float64{100_000, 98_000, 96_000, 93_000}
+	targetTimeline := result.EncounterMetrics.Targets[0].Timeline
+	if len(targetTimeline.Resources) != 1 || !reflect.DeepEqual(targetTimeline.Resources[0].Values, expectedHealth) {
+		t.Fatalf("Expected health timeline %v, got %v", expectedHealth, targetTimeline.Resources)
+	}
+
+	// Timelines from parallel workers are merged.
+	rsr.SimOptions.NumWorkers = 3
+	parallelTimeline := runTestRaidSim(t, rsr).RaidMetrics.Parties[0].Players[0].Timeline
+	if !reflect.DeepEqual(parallelTimeline.Damage, expectedDamage) {
+		t.Fatalf("Expected parallel timeline damage %v, got %v", expectedDamage, parallelTimeline.Damage)
+	}
+}
+
+func TestTimelineAuraUptime(t *testing.T) {
+	timeline := newTimelineMetrics(time.Second * 5)
+	actionID := ActionID{SpellID: 1}
+	timeline.addAuraUptime(actionID, -time.Second, time.Second*3)
+	timeline.addAuraUptime(actionID, time.Second*4, time.Second*11)
+
+	expected := []float64{4, 5, 1}
+	if uptime := timeline.auras[0].uptime; !reflect.DeepEqual(uptime, expected) {
+		t.Fatalf("Expected uptime %v, got %v", expected, uptime)
+	}
+}
@@ -120,6 +120,20 @@ type Unit struct {
 	AttackTables                []*AttackTable
 	DynamicDamageTakenModifiers []DynamicDamageTakenModifier
 
+	// Shields which can be applied to this unit, in creation order.
+	shields []*Shield
+
+	// Opponents can't attack this unit while above 0, see
+	// Target.StartUntargetable.
+	untargetable int32
+
+	// Can't hard-cast or auto attack while above 0, see StartMoving.
+	moving int32
+	// Encounter movement events which include this unit.
+	movementEvents []*proto.MovementEvent
+
+	targetSwitchHandlers []TargetSwitchHandler
+
 	GCD       *Timer
 	doNothing bool // flags that this character chose to do nothing.
 
@@ -171,6 +185,11 @@ func (unit *Unit) IsOpponent(other *Unit) bool {
 	return (unit.Type == EnemyUnit) != (other.Type == EnemyUnit)
 }
 
+// Whether opponents can attack this unit right now.
+func (unit *Unit) IsTargetable() bool {
+	return unit.untargetable == 0
+}
+
 func (unit *Unit) GetOpponents() []*Unit {
 	if unit.Type == EnemyUnit {
 		return unit.Env.Raid.AllUnits
@@ -419,12 +438,16 @@ func (unit *Unit) finalize() {
 
 func (unit *Unit) init(sim *Simulation) {
 	unit.auraTracker.init(sim)
+	if unit.Type != EnemyUnit {
+		unit.Metrics.initTargetDps(len(sim.Encounter.Targets))
+	}
 }
 
 func (unit *Unit) reset(sim *Simulation, agent Agent) {
 	unit.enabled = true
 	unit.resetCDs(sim)
 	unit.Hardcast.Expires = startingCDTime
+	unit.moving = 0
 	unit.Metrics.reset()
 	unit.ResetStatDeps()
 	unit.statsWithoutDeps = unit.initialStatsWithoutDeps
@@ -249,3 +249,13 @@ func calcMeanAndStdevFromSums(n int, sum float64, sumSq float64) (float64, float
 	stdev := math.Abs(math.Sqrt(sumSq/float64(n) - mean*mean))
 	return mean, stdev
 }
+
+// Returns the standard error of the mean, using the sample standard deviation.
+func calcStderrFromSums(n int, sum float64, sumSq float64) float64 {
+	if n < 2 {
+		return 0
+	}
+	mean := sum / float64(n)
+	variance := math.Max(0, sumSq/float64(n)-mean*mean)
+	return math.Sqrt(variance / float64(n-1))
+}
@@ -97,9 +97,11 @@ func (dk *Deathknight) registerAntiMagicShellSpell() {
 			if result.Damage > 0 && physDmgTakenMult != 1.0 {
 				coeff := core.TernaryFloat64(spell.SpellSchool == core.SpellSchoolPhysical, physDmgTakenMult, spellDmgTakenMult)
 				absorbedDmg := (1.0 - coeff) * result.Damage / coeff
+				dk.AddAbsorb(sim, dk.AntiMagicShell, absorbedDmg)
 				dk.AddRunicPower(sim, absorbedDmg/69.0, rpMetrics)
 			} else if result.Damage > 0 && spell.SpellSchool != core.SpellSchoolPhysical {
 				absorbedDmg := (1.0 - spellDmgTakenMult) * result.Damage / spellDmgTakenMult
+				dk.AddAbsorb(sim, dk.AntiMagicShell, absorbedDmg)
 				dk.AddRunicPower(sim, absorbedDmg/69.0, rpMetrics)
 			}
 		},
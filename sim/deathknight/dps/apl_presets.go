@@ -0,0 +1,72 @@
+package dps
+
+import (
+	"github.com/wowsims/wotlk/sim/core"
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// Obliterate and Frost Strike, refreshing diseases with Pestilence and using
+// Killing Machine and Rime procs as they come up. Unbreakable Armor is paired
+// with Blood Tap, and Empower Rune Weapon is used once the runes run dry.
+var FrostAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+actions+=/cast_spell,id=59131,if=!dot_is_active(55095)
+actions+=/cast_spell,id=49921@1,if=!dot_is_active(55078)
+actions+=/cast_spell,id=51271
+actions+=/cast_spell,id=45529,if=aura_is_active(51271)
+actions+=/cast_spell,id=46584
+actions+=/cast_spell,id=50842,if=dot_remaining_time(55095)<6s|dot_remaining_time(55078)<6s
+actions+=/cast_spell,id=55268@1,if=aura_is_active(51130)|runic_power>=110
+actions+=/cast_spell,id=51425@1
+actions+=/cast_spell,id=51411,if=aura_is_active(59057)
+actions+=/cast_spell,id=55268@1
+actions+=/cast_spell,id=49930@1,if=dot_remaining_time(55095)>8s
+actions+=/cast_spell,id=47568
+actions+=/cast_spell,id=57623
+`)
+
+// Heart Strike and Death Strike with diseases kept up by Pestilence. Dancing
+// Rune Weapon, Raise Dead and Empower Rune Weapon go out on cooldown, and
+// runic power goes into Death Coil.
+var BloodAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+actions+=/cast_spell,id=59131,if=!dot_is_active(55095)
+actions+=/cast_spell,id=49921@1,if=!dot_is_active(55078)
+actions+=/cast_spell,id=49028
+actions+=/cast_spell,id=46584
+actions+=/cast_spell,id=45529
+actions+=/cast_spell,id=50842,if=dot_remaining_time(55095)<3s|dot_remaining_time(55078)<3s
+actions+=/cast_spell,id=55262@1
+actions+=/cast_spell,id=49924@1
+actions+=/cast_spell,id=49895
+actions+=/cast_spell,id=47568
+actions+=/cast_spell,id=57623
+`)
+
+// Death and Decay on cooldown, with Icy Touch, Plague Strike, Blood Strike and
+// Blood Boil spending the runes in between. Cooldowns are held until there is
+// enough runic power for Gargoyle, which is summoned in Unholy Presence before
+// going back to Blood Presence. Ghoul Frenzy is kept up with Blood Tap.
+var UnholyAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true,if=!spell_is_ready(49206)|runic_power>=60
+actions+=/cast_spell,id=59131,if=!dot_is_active(55095)
+actions+=/cast_spell,id=49921@1,if=!dot_is_active(55078)
+actions+=/cast_spell,id=49938
+actions+=/cast_spell,id=48265,if=spell_is_ready(49206)&runic_power>=60&!aura_is_active(48265)
+actions+=/cast_spell,id=49206
+actions+=/cast_spell,id=47568,if=spell_is_ready(42650)
+actions+=/cast_spell,id=42650
+actions+=/cast_spell,id=50689,if=!spell_is_ready(49206)&!aura_is_active(49206)&!aura_is_active(50689)
+actions+=/cast_spell,id=45529,if=aura_remaining_time(63560)<3s
+actions+=/cast_spell,id=63560,if=aura_remaining_time(63560)<3s
+actions+=/cast_spell,id=49895,if=runic_power>100
+actions+=/cast_spell,id=59131,if=spell_time_to_ready(49938)>3s
+actions+=/cast_spell,id=49921@1,if=spell_time_to_ready(49938)>3s
+actions+=/cast_spell,id=49930@1,if=spell_time_to_ready(49938)>3s
+actions+=/cast_spell,id=49941,if=spell_time_to_ready(49938)>3s
+actions+=/cast_spell,id=49895
+actions+=/cast_spell,id=57623
+`)
+
+func registerAPLPresets() {
+	core.RegisterAPLPreset(proto.Spec_SpecDeathknight, "Blood", BloodAPL)
+	core.RegisterAPLPreset(proto.Spec_SpecDeathknight, "Frost", FrostAPL)
+	core.RegisterAPLPreset(proto.Spec_SpecDeathknight, "Unholy", UnholyAPL)
+}
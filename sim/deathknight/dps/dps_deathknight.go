@@ -24,6 +24,7 @@ func RegisterDpsDeathknight() {
 			player.Spec = playerSpec
 		},
 	)
+	registerAPLPresets()
 }
 
 type DpsDeathknight struct {
@@ -523,7 +524,11 @@ func (dk *DpsDeathknight) Reset(sim *core.Simulation) {
 	dk.fr.Reset(sim)
 	dk.ur.Reset(sim)
 
-	dk.SetupRotations()
+	// The hand-written rotations take over some of the major cooldowns, so
+	// leave them alone when an APL is in charge.
+	if !dk.IsUsingAPL() {
+		dk.SetupRotations()
+	}
 
 	dk.Presence = deathknight.UnsetPresence
 
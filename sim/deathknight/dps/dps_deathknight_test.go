@@ -120,6 +120,47 @@ func TestFrostUH(t *testing.T) {
 	}))
 }
 
+func TestAPLPresets(t *testing.T) {
+	core.RunAPLPresetTests(t, []core.APLPresetTestCase{
+		{
+			Preset: "Blood",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceOrc,
+				Class:         proto.Class_ClassDeathknight,
+				Equipment:     BloodP1Gear,
+				Consumes:      FullConsumes,
+				Spec:          PlayerOptionsBlood,
+				Glyphs:        BloodDefaultGlyphs,
+				TalentsString: BloodTalents,
+			},
+		},
+		{
+			Preset: "Frost",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceOrc,
+				Class:         proto.Class_ClassDeathknight,
+				Equipment:     FrostP1Gear,
+				Consumes:      FullConsumes,
+				Spec:          PlayerOptionsFrost,
+				Glyphs:        FrostDefaultGlyphs,
+				TalentsString: FrostTalents,
+			},
+		},
+		{
+			Preset: "Unholy",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceOrc,
+				Class:         proto.Class_ClassDeathknight,
+				Equipment:     UnholyDwP1Gear,
+				Consumes:      FullConsumes,
+				Spec:          PlayerOptionsUnholy,
+				Glyphs:        UnholyDefaultGlyphs,
+				TalentsString: UnholyTalents,
+			},
+		},
+	})
+}
+
 var BloodTalents = "2305120530003303231023001351--230220305003"
 var BloodDefaultGlyphs = &proto.Glyphs{
 	Major1: int32(proto.DeathknightMajorGlyph_GlyphOfDancingRuneWeapon),
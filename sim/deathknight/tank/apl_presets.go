@@ -0,0 +1,24 @@
+package tank
+
+import (
+	"github.com/wowsims/wotlk/sim/core"
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// Threat opener with extra Icy Touches, then keeps both diseases up with
+// Pestilence and spends spare Blood runes on Blood Strike. Runic power is left
+// for Rune Strike.
+var BloodTankAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+actions+=/cast_spell,id=59131,if=!dot_is_active(55095)
+actions+=/cast_spell,id=49921@1,if=!dot_is_active(55078)
+actions+=/cast_spell,id=50842,if=dot_remaining_time(55095)<=3s|dot_remaining_time(55078)<=3s
+actions+=/wait_until,condition=current_rune_count(RuneBlood)+current_rune_count(RuneDeath)>=1,if=dot_remaining_time(55095)<=3s
+actions+=/cast_spell,id=45529
+actions+=/cast_spell,id=47568
+actions+=/cast_spell,id=49930@1,if=dot_remaining_time(55095)>6s&dot_remaining_time(55078)>6s
+actions+=/cast_spell,id=59131,if=current_time<20s
+`)
+
+func registerAPLPresets() {
+	core.RegisterAPLPreset(proto.Spec_SpecTankDeathknight, "Blood Tank", BloodTankAPL)
+}
@@ -21,6 +21,7 @@ func RegisterTankDeathknight() {
 			player.Spec = playerSpec
 		},
 	)
+	registerAPLPresets()
 }
 
 type TankDeathknight struct {
@@ -39,6 +39,25 @@ func TestBloodTank(t *testing.T) {
 	}))
 }
 
+func TestAPLPresets(t *testing.T) {
+	core.RunAPLPresetTests(t, []core.APLPresetTestCase{
+		{
+			Preset: "Blood Tank",
+			Player: &proto.Player{
+				Race:            proto.Race_RaceOrc,
+				Class:           proto.Class_ClassDeathknight,
+				Equipment:       BloodP1Gear,
+				Consumes:        FullConsumes,
+				Spec:            PlayerOptionsBloodTank,
+				Glyphs:          Glyphs,
+				TalentsString:   BloodTankTalents,
+				InFrontOfTarget: true,
+			},
+			IsTank: true,
+		},
+	})
+}
+
 var BloodTankTalents = "005510153330330220102013-3050505100023101-002"
 var Glyphs = &proto.Glyphs{
 	Major1: int32(proto.DeathknightMajorGlyph_GlyphOfDarkCommand),
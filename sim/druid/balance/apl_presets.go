@@ -0,0 +1,28 @@
+package balance
+
+import (
+	"github.com/wowsims/wotlk/sim/core"
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// Fishes for whichever Eclipse did not proc last: Wrath for Lunar, Starfire
+// for Solar.
+var BalanceAPL = core.APLRotationFromTextString(`variable.fish_for_solar=0
+
+actions+=/autocast_cooldowns,all_types=true
+actions+=/cast_spell,id=770,if=aura_remaining_time(770,on_target=true)<3s
+actions+=/cast_spell,id=65861
+actions+=/cast_spell,id=53201
+actions+=/set_variable,name=fish_for_solar,value=1,if=aura_is_active(48518)
+actions+=/set_variable,name=fish_for_solar,value=0,if=aura_is_active(48517)
+actions+=/cast_spell,id=48468,if=!dot_is_active(48468)&!aura_is_active(48518)
+actions+=/cast_spell,id=48465,if=aura_remaining_time(48518)>spell_cast_time(48465)
+actions+=/cast_spell,id=48461,if=aura_remaining_time(48517)>spell_cast_time(48461)
+actions+=/cast_spell,id=48463,if=!dot_is_active(48463)&variable(fish_for_solar)=0
+actions+=/cast_spell,id=48465,if=variable(fish_for_solar)=1
+actions+=/cast_spell,id=48461
+`)
+
+func registerAPLPresets() {
+	core.RegisterAPLPreset(proto.Spec_SpecBalanceDruid, "Balance", BalanceAPL)
+}
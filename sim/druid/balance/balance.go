@@ -22,6 +22,7 @@ func RegisterBalanceDruid() {
 			player.Spec = playerSpec
 		},
 	)
+	registerAPLPresets()
 }
 
 func NewBalanceDruid(character core.Character, options *proto.Player) *BalanceDruid {
@@ -90,7 +91,7 @@ func (moonkin *BalanceDruid) Reset(sim *core.Simulation) {
 		moonkin.Rotation.PlayerLatency = 200
 	}
 
-	if moonkin.Rotation.UseSmartCooldowns {
+	if moonkin.Rotation.UseSmartCooldowns && !moonkin.IsUsingAPL() {
 		moonkin.potionUsed = false
 		consumes := moonkin.Consumes
 
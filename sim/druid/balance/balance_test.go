@@ -48,6 +48,23 @@ func TestBalance(t *testing.T) {
 	}))
 }
 
+func TestAPLPresets(t *testing.T) {
+	core.RunAPLPresetTests(t, []core.APLPresetTestCase{
+		{
+			Preset: "Balance",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceTauren,
+				Class:         proto.Class_ClassDruid,
+				Equipment:     P1Gear,
+				Consumes:      FullConsumes,
+				Spec:          PlayerOptionsAdaptive,
+				Glyphs:        StandardGlyphs,
+				TalentsString: StandardTalents,
+			},
+		},
+	})
+}
+
 var StandardTalents = "5012203115331303213305311231--205003012"
 var StandardGlyphs = &proto.Glyphs{
 	Major1: int32(proto.DruidMajorGlyph_GlyphOfStarfire),
@@ -21,7 +21,7 @@ func (moonkin *BalanceDruid) tryUseGCD(sim *core.Simulation) {
 }
 
 func (moonkin *BalanceDruid) rotation(sim *core.Simulation) (*core.Spell, *core.Unit) {
-	moonkin.CurrentTarget = sim.Environment.GetTargetUnit(0)
+	moonkin.CurrentTarget = &sim.Encounter.RaidTarget().Unit
 	rotation := moonkin.Rotation
 	target := moonkin.CurrentTarget
 
@@ -68,7 +68,7 @@ func (moonkin *BalanceDruid) rotation(sim *core.Simulation) (*core.Spell, *core.
 			return moonkin.InsectSwarm, target
 		}
 	} else if rotation.IsUsage == proto.BalanceDruid_Rotation_MultidotIs {
-		for range sim.Encounter.Targets {
+		for range sim.Encounter.TargetUnits {
 			if moonkin.InsectSwarm.CurDot().RemainingDuration(sim) <= 0 {
 				return moonkin.InsectSwarm, moonkin.CurrentTarget
 			}
@@ -81,7 +81,7 @@ func (moonkin *BalanceDruid) rotation(sim *core.Simulation) (*core.Spell, *core.
 	if rotation.MfUsage == proto.BalanceDruid_Rotation_MaximizeMf && shouldRefreshMf {
 		return moonkin.Moonfire, target
 	} else if rotation.MfUsage == proto.BalanceDruid_Rotation_MultidotMf {
-		for range sim.Encounter.Targets {
+		for range sim.Encounter.TargetUnits {
 			if moonkin.Moonfire.CurDot().RemainingDuration(sim) <= 0 {
 				return moonkin.Moonfire, moonkin.CurrentTarget
 			}
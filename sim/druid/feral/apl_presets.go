@@ -0,0 +1,24 @@
+package feral
+
+import (
+	"github.com/wowsims/wotlk/sim/core"
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// Single target cat rotation without weaving. Berserk is held until just after
+// Tiger's Fury, and Faerie Fire is used on cooldown to fish for Omen procs.
+var FeralAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+actions+=/cast_spell,id=50213,if=energy<30&!aura_is_active(50334)
+actions+=/cast_spell,id=16857,if=!aura_is_active(16870)&energy<87
+actions+=/cast_spell,id=50334,if=dot_is_active(49800)&!aura_is_active(16870)&spell_time_to_ready(50213)>15s
+actions+=/cast_spell,id=52610,if=combo_points>=1&!aura_is_active(52610)
+actions+=/cast_spell,id=49800,if=combo_points>=5&!dot_is_active(49800)&remaining_time>=10s
+actions+=/cast_spell,id=48577,if=combo_points>=5&dot_remaining_time(49800)>=4s&aura_remaining_time(52610)>=4s&energy<67&!aura_is_active(16870)
+actions+=/cast_spell,id=48577,if=combo_points>=5&remaining_time<10s&!aura_is_active(16870)
+actions+=/cast_spell,id=48574,if=!dot_is_active(48574)&remaining_time>9s
+actions+=/cast_spell,id=48572
+`)
+
+func registerAPLPresets() {
+	core.RegisterAPLPreset(proto.Spec_SpecFeralDruid, "Feral", FeralAPL)
+}
@@ -23,6 +23,7 @@ func RegisterFeralDruid() {
 			player.Spec = playerSpec
 		},
 	)
+	registerAPLPresets()
 }
 
 func NewFeralDruid(character core.Character, options *proto.Player) *FeralDruid {
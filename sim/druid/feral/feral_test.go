@@ -137,6 +137,23 @@ func BenchmarkSimulate(b *testing.B) {
 	core.RaidBenchmark(b, rsr)
 }
 
+func TestAPLPresets(t *testing.T) {
+	core.RunAPLPresetTests(t, []core.APLPresetTestCase{
+		{
+			Preset: "Feral",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceTauren,
+				Class:         proto.Class_ClassDruid,
+				Equipment:     P1Gear,
+				Consumes:      FullConsumes,
+				Spec:          PlayerOptionsMonoCat,
+				Glyphs:        StandardGlyphs,
+				TalentsString: StandardTalents,
+			},
+		},
+	})
+}
+
 var StandardTalents = "-503202132322010053120230310511-205503012"
 var StandardGlyphs = &proto.Glyphs{
 	Major1: int32(proto.DruidMajorGlyph_GlyphOfRip),
@@ -10,6 +10,9 @@ import (
 )
 
 func (cat *FeralDruid) OnEnergyGain(sim *core.Simulation) {
+	if cat.IsUsingAPL() {
+		return
+	}
 	cat.TryUseCooldowns(sim)
 	if cat.InForm(druid.Cat) && !cat.readyToShift {
 		cat.doTigersFury(sim)
@@ -53,6 +56,10 @@ func (cat *FeralDruid) OnAutoAttack(sim *core.Simulation, spell *core.Spell) {
 		panic("auto attack out of form?")
 	}
 
+	if cat.IsUsingAPL() {
+		return
+	}
+
 	// If the swing resulted in an Omen proc, then schedule the
 	// next player decision based on latency.
 
@@ -69,6 +69,20 @@ func (druid *Druid) registerMaulSpell(rageThreshold float64) {
 		Duration: core.NeverExpires,
 	})
 
+	// Queues Maul for the next swing, so it can be used from APL rotations.
+	druid.MaulQueueSpell = druid.RegisterSpell(core.SpellConfig{
+		ActionID: druid.Maul.WithTag(1),
+		Flags:    core.SpellFlagNoMetrics | core.SpellFlagAPL,
+
+		ExtraCastCondition: func(sim *core.Simulation, target *core.Unit) bool {
+			return !druid.MaulQueueAura.IsActive() && druid.CurrentRage() >= druid.Maul.DefaultCast.Cost
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			druid.QueueMaul(sim)
+		},
+	})
+
 	druid.MaulRageThreshold = core.MaxFloat(druid.Maul.DefaultCast.Cost, rageThreshold)
 }
 
@@ -0,0 +1,16 @@
+package restoration
+
+import (
+	"github.com/wowsims/wotlk/sim/core"
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// Healing spells are not implemented yet, so like the hand-written rotation
+// this only uses cooldowns.
+var RestorationAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+actions+=/wait,duration=5s
+`)
+
+func registerAPLPresets() {
+	core.RegisterAPLPreset(proto.Spec_SpecRestorationDruid, "Restoration", RestorationAPL)
+}
@@ -21,6 +21,7 @@ func RegisterRestorationDruid() {
 			player.Spec = playerSpec
 		},
 	)
+	registerAPLPresets()
 }
 
 func NewRestorationDruid(character core.Character, options *proto.Player) *RestorationDruid {
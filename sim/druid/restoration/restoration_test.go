@@ -39,6 +39,23 @@ func TestRestoration(t *testing.T) {
 	}))
 }
 
+func TestAPLPresets(t *testing.T) {
+	core.RunAPLPresetTests(t, []core.APLPresetTestCase{
+		{
+			Preset: "Restoration",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceTauren,
+				Class:         proto.Class_ClassDruid,
+				Equipment:     P1Gear,
+				Consumes:      FullConsumes,
+				Spec:          PlayerOptionsStandard,
+				Glyphs:        StandardGlyphs,
+				TalentsString: StandardTalents,
+			},
+		},
+	})
+}
+
 var StandardTalents = "05320031103--230023312131502331050313051"
 var StandardGlyphs = &proto.Glyphs{
 	Major1: int32(proto.DruidMajorGlyph_GlyphOfWildGrowth),
@@ -0,0 +1,24 @@
+package tank
+
+import (
+	"github.com/wowsims/wotlk/sim/core"
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// Mangle on cooldown, building and maintaining 5 Lacerate stacks, with Maul
+// queued whenever there is rage to spare.
+var BearAPL = core.APLRotationFromTextString(`actions+=/cast_spell,id=48480@1,if=rage>=25
+actions+=/cast_spell,id=48568,if=aura_num_stacks(48568,on_target=true)=5&aura_remaining_time(48568,on_target=true)<=1.5s
+actions+=/cast_spell,id=48560,if=aura_remaining_time(48560,on_target=true)<2s
+actions+=/cast_spell,id=50334
+actions+=/cast_spell,id=5229,if=aura_is_active(50334)
+actions+=/autocast_cooldowns,all_types=true
+actions+=/cast_spell,id=48564
+actions+=/cast_spell,id=16857,if=spell_time_to_ready(48564)>=1s
+actions+=/cast_spell,id=48568,if=spell_time_to_ready(48564)>=1.5s&(aura_num_stacks(48568,on_target=true)<5|aura_remaining_time(48568,on_target=true)<=8s)
+actions+=/cast_spell,id=48562,if=spell_time_to_ready(48564)>=1.5s&rage>=45
+`)
+
+func registerAPLPresets() {
+	core.RegisterAPLPreset(proto.Spec_SpecFeralTankDruid, "Bear", BearAPL)
+}
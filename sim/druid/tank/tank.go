@@ -21,6 +21,7 @@ func RegisterFeralTankDruid() {
 			player.Spec = playerSpec
 		},
 	)
+	registerAPLPresets()
 }
 
 func NewFeralTankDruid(character core.Character, options *proto.Player) *FeralTankDruid {
@@ -45,7 +46,7 @@ func NewFeralTankDruid(character core.Character, options *proto.Player) *FeralTa
 	}
 
 	bear.EnableRageBar(rbo, func(sim *core.Simulation) {
-		if bear.GCD.IsReady(sim) {
+		if bear.GCD.IsReady(sim) && !bear.IsUsingAPL() {
 			bear.TryUseCooldowns(sim)
 			if bear.GCD.IsReady(sim) {
 				bear.doRotation(sim)
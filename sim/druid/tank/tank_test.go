@@ -69,6 +69,25 @@ func BenchmarkSimulate(b *testing.B) {
 	core.RaidBenchmark(b, rsr)
 }
 
+func TestAPLPresets(t *testing.T) {
+	core.RunAPLPresetTests(t, []core.APLPresetTestCase{
+		{
+			Preset: "Bear",
+			Player: &proto.Player{
+				Race:            proto.Race_RaceTauren,
+				Class:           proto.Class_ClassDruid,
+				Equipment:       P1Gear,
+				Consumes:        FullConsumes,
+				Spec:            PlayerOptionsDefault,
+				Glyphs:          StandardGlyphs,
+				TalentsString:   StandardTalents,
+				InFrontOfTarget: true,
+			},
+			IsTank: true,
+		},
+	})
+}
+
 var StandardTalents = "-503232132322010353120300313511-20350001"
 var StandardGlyphs = &proto.Glyphs{
 	Major1: int32(proto.DruidMajorGlyph_GlyphOfMaul),
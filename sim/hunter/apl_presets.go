@@ -0,0 +1,45 @@
+package hunter
+
+import (
+	"github.com/wowsims/wotlk/sim/core"
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// Aspect swapping shared by all presets, using Viper below 20% mana.
+const aplAspects = `actions+=/cast_spell,id=34074,if=!aura_is_active(34074)&mana_percent<20%
+actions+=/cast_spell,id=61847,if=aura_is_active(34074)&mana_percent>30%
+`
+
+var BeastMasteryAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+` + aplAspects + `actions+=/cast_spell,id=61006
+actions+=/cast_spell,id=49001,if=!dot_is_active(49001)
+actions+=/cast_spell,id=49048
+actions+=/cast_spell,id=49045
+actions+=/cast_spell,id=49052
+`)
+
+var MarksmanshipAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+` + aplAspects + `actions+=/cast_spell,id=34490
+actions+=/cast_spell,id=61006
+actions+=/cast_spell,id=49001,if=!dot_is_active(49001)
+actions+=/cast_spell,id=53209
+actions+=/cast_spell,id=49050
+actions+=/cast_spell,id=49045
+actions+=/cast_spell,id=49052
+`)
+
+var SurvivalAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+` + aplAspects + `actions+=/cast_spell,id=61006
+actions+=/cast_spell,id=60053,if=!dot_is_active(60053)
+actions+=/cast_spell,id=49001,if=!dot_is_active(49001)
+actions+=/cast_spell,id=63672
+actions+=/cast_spell,id=49050
+actions+=/cast_spell,id=49045,if=!dot_is_active(60053)
+actions+=/cast_spell,id=49052
+`)
+
+func registerAPLPresets() {
+	core.RegisterAPLPreset(proto.Spec_SpecHunter, "BeastMastery", BeastMasteryAPL)
+	core.RegisterAPLPreset(proto.Spec_SpecHunter, "Marksmanship", MarksmanshipAPL)
+	core.RegisterAPLPreset(proto.Spec_SpecHunter, "Survival", SurvivalAPL)
+}
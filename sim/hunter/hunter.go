@@ -28,6 +28,7 @@ func RegisterHunter() {
 			player.Spec = playerSpec
 		},
 	)
+	registerAPLPresets()
 }
 
 type Hunter struct {
@@ -52,6 +53,10 @@ type Hunter struct {
 	// The most recent time at which moving could have started, for trap weaving.
 	mayMoveAt time.Duration
 
+	// The target with this hunter's own Hunter's Mark, if any. Marks from the
+	// raid's debuffs are the same aura, and aren't tracked here.
+	markedTarget *core.Unit
+
 	AspectOfTheDragonhawk *core.Spell
 	AspectOfTheViper      *core.Spell
 
@@ -152,9 +157,25 @@ func (hunter *Hunter) Initialize() {
 	}
 
 	if hunter.Options.UseHuntersMark {
+		huntersMarkAuras := hunter.NewEnemyAuraArray(func(target *core.Unit) *core.Aura {
+			return core.HuntersMarkAura(target, hunter.Talents.ImprovedHuntersMark, hunter.HasMajorGlyph(proto.HunterMajorGlyph_GlyphOfHuntersMark))
+		})
+		markTarget := func(sim *core.Simulation, target *core.Unit) {
+			hunter.markedTarget = nil
+			if aura := huntersMarkAuras.Get(target); !aura.IsActive() {
+				aura.Activate(sim)
+				hunter.markedTarget = target
+			}
+		}
 		hunter.RegisterPrepullAction(0, func(sim *core.Simulation) {
-			huntersMarkAura := core.HuntersMarkAura(hunter.CurrentTarget, hunter.Talents.ImprovedHuntersMark, hunter.HasMajorGlyph(proto.HunterMajorGlyph_GlyphOfHuntersMark))
-			huntersMarkAura.Activate(sim)
+			markTarget(sim, hunter.CurrentTarget)
+		})
+		// Each hunter has a single mark, which moves to the raid's new target.
+		hunter.RegisterTargetSwitchHandler(func(sim *core.Simulation, oldTarget *core.Unit, newTarget *core.Unit) {
+			if hunter.markedTarget == oldTarget {
+				huntersMarkAuras.Get(oldTarget).Deactivate(sim)
+			}
+			markTarget(sim, newTarget)
 		})
 	}
 }
@@ -80,6 +80,47 @@ func TestAPL(t *testing.T) {
 	}))
 }
 
+func TestAPLPresets(t *testing.T) {
+	core.RunAPLPresetTests(t, []core.APLPresetTestCase{
+		{
+			Preset: "BeastMastery",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceOrc,
+				Class:         proto.Class_ClassHunter,
+				Equipment:     P1Gear,
+				Consumes:      FullConsumes,
+				Spec:          PlayerOptionsBM,
+				Glyphs:        BMGlyphs,
+				TalentsString: BMTalents,
+			},
+		},
+		{
+			Preset: "Marksmanship",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceOrc,
+				Class:         proto.Class_ClassHunter,
+				Equipment:     P1Gear,
+				Consumes:      FullConsumes,
+				Spec:          PlayerOptionsMM,
+				Glyphs:        MMGlyphs,
+				TalentsString: MMTalents,
+			},
+		},
+		{
+			Preset: "Survival",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceOrc,
+				Class:         proto.Class_ClassHunter,
+				Equipment:     P1Gear,
+				Consumes:      FullConsumes,
+				Spec:          PlayerOptionsSV,
+				Glyphs:        SVGlyphs,
+				TalentsString: SVTalents,
+			},
+		},
+	})
+}
+
 var ItemFilter = core.ItemFilter{
 	ArmorType: proto.ArmorType_ArmorTypeMail,
 	WeaponTypes: []proto.WeaponType{
@@ -0,0 +1,33 @@
+package mage
+
+import (
+	"github.com/wowsims/wotlk/sim/core"
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+var ArcaneAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+# Missiles with Missile Barrage when low on mana, otherwise build Arcane Blast stacks.
+actions+=/cast_spell,id=42846,if=aura_is_active(44401)&mana_percent<10%
+actions+=/cast_spell,id=42897,if=!aura_is_active(44401)&mana_percent>20%
+actions+=/cast_spell,id=42897,if=aura_num_stacks(36032)<4&mana_percent>=15%
+actions+=/cast_spell,id=42897,if=aura_num_stacks(36032)<3
+actions+=/cast_spell,id=42846
+`)
+
+var FireAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+actions+=/cast_spell,id=42891,if=aura_is_active(44448)
+actions+=/cast_spell,id=55360,if=!dot_is_active(55360)&remaining_time>12s
+actions+=/cast_spell,id=42833
+`)
+
+var FrostAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+actions+=/cast_spell,id=44572,if=aura_is_active(44545)
+actions+=/cast_spell,id=47610,if=aura_is_active(44545)&aura_is_active(44549)
+actions+=/cast_spell,id=42842
+`)
+
+func registerAPLPresets() {
+	core.RegisterAPLPreset(proto.Spec_SpecMage, "Arcane", ArcaneAPL)
+	core.RegisterAPLPreset(proto.Spec_SpecMage, "Fire", FireAPL)
+	core.RegisterAPLPreset(proto.Spec_SpecMage, "Frost", FrostAPL)
+}
@@ -31,6 +31,7 @@ func RegisterMage() {
 			player.Spec = playerSpec
 		},
 	)
+	registerAPLPresets()
 }
 
 type Mage struct {
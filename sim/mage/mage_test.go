@@ -125,6 +125,47 @@ func TestFrost(t *testing.T) {
 	}))
 }
 
+func TestAPLPresets(t *testing.T) {
+	core.RunAPLPresetTests(t, []core.APLPresetTestCase{
+		{
+			Preset: "Arcane",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceTroll,
+				Class:         proto.Class_ClassMage,
+				Equipment:     P1ArcaneGear,
+				Consumes:      FullFireConsumes,
+				Spec:          PlayerOptionsArcane,
+				Glyphs:        ArcaneGlyphs,
+				TalentsString: ArcaneTalents,
+			},
+		},
+		{
+			Preset: "Fire",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceTroll,
+				Class:         proto.Class_ClassMage,
+				Equipment:     P1FireGear,
+				Consumes:      FullFireConsumes,
+				Spec:          PlayerOptionsFire,
+				Glyphs:        FireGlyphs,
+				TalentsString: FireTalents,
+			},
+		},
+		{
+			Preset: "Frost",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceTroll,
+				Class:         proto.Class_ClassMage,
+				Equipment:     P1FrostGear,
+				Consumes:      FullFireConsumes,
+				Spec:          PlayerOptionsFrost,
+				Glyphs:        FrostGlyphs,
+				TalentsString: FrostTalents,
+			},
+		},
+	})
+}
+
 var ArcaneTalents = "23000513310033015032310250532-03-023303001"
 var FireTalents = "23000503110003-0055030012303331053120301351"
 var FrostFireTalents = "23000503110003-0055030012303331053120301351"
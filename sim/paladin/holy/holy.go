@@ -21,6 +21,7 @@ func RegisterHolyPaladin() {
 			player.Spec = playerSpec
 		},
 	)
+	registerAPLPresets()
 }
 
 func NewHolyPaladin(character core.Character, options *proto.Player) *HolyPaladin {
@@ -70,6 +70,23 @@ func BenchmarkSimulate(b *testing.B) {
 	core.RaidBenchmark(b, rsr)
 }
 
+func TestAPLPresets(t *testing.T) {
+	core.RunAPLPresetTests(t, []core.APLPresetTestCase{
+		{
+			Preset: "Holy",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceBloodElf,
+				Class:         proto.Class_ClassPaladin,
+				Equipment:     P1Gear,
+				Consumes:      FullConsumes,
+				Spec:          BasicOptions,
+				Glyphs:        StandardGlyphs,
+				TalentsString: StandardTalents,
+			},
+		},
+	})
+}
+
 var StandardTalents = "50350151020013053100515221-50023131203"
 var StandardGlyphs = &proto.Glyphs{
 	Major1: int32(proto.PaladinMajorGlyph_GlyphOfHolyLight),
@@ -0,0 +1,22 @@
+package protection
+
+import (
+	"github.com/wowsims/wotlk/sim/core"
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// Standard 969 rotation, leading with Shield of Righteousness. Hammer of the
+// Righteous is held until a GCD has passed since Shield, and the 9 second
+// abilities fill the gaps in between.
+var ProtectionAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+actions+=/cast_spell,id=61411
+actions+=/cast_spell,id=53595,if=spell_time_to_ready(61411)<4s
+actions+=/cast_spell,id=48806,if=target_health_percent<=20%
+actions+=/cast_spell,id=48952
+actions+=/cast_spell,id=48819
+actions+=/cast_spell,id=53408
+`)
+
+func registerAPLPresets() {
+	core.RegisterAPLPreset(proto.Spec_SpecProtectionPaladin, "Protection", ProtectionAPL)
+}
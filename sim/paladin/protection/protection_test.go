@@ -96,6 +96,25 @@ func BenchmarkSimulate(b *testing.B) {
 	core.RaidBenchmark(b, rsr)
 }
 
+func TestAPLPresets(t *testing.T) {
+	core.RunAPLPresetTests(t, []core.APLPresetTestCase{
+		{
+			Preset: "Protection",
+			Player: &proto.Player{
+				Race:            proto.Race_RaceBloodElf,
+				Class:           proto.Class_ClassPaladin,
+				Equipment:       P1Gear,
+				Consumes:        FullConsumes,
+				Spec:            DefaultOptions,
+				Glyphs:          StandardGlyphs,
+				TalentsString:   StandardTalents,
+				InFrontOfTarget: true,
+			},
+			IsTank: true,
+		},
+	})
+}
+
 var StandardTalents = "-05005135200132311333312321-511302012003"
 var StandardGlyphs = &proto.Glyphs{
 	Major1: int32(proto.PaladinMajorGlyph_GlyphOfSealOfVengeance),
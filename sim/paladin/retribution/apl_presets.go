@@ -0,0 +1,25 @@
+package retribution
+
+import (
+	"github.com/wowsims/wotlk/sim/core"
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// Standard single target priority with Judgement of Wisdom. Consecration and
+// Exorcism are only used when Crusader Strike, Divine Storm and Judgement are
+// all more than 500ms away, and Holy Wrath fills any remaining gaps as it
+// does against demon and undead targets.
+var RetributionAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+actions+=/cast_spell,id=53408
+actions+=/cast_spell,id=48806,if=target_health_percent<=20%
+actions+=/cast_spell,id=54428,if=mana_percent<75%
+actions+=/cast_spell,id=35395
+actions+=/cast_spell,id=53385
+actions+=/cast_spell,id=48801,if=aura_is_active(53488)&spell_time_to_ready(35395)>500ms&spell_time_to_ready(53385)>500ms&spell_time_to_ready(53408)>500ms
+actions+=/cast_spell,id=48819,if=spell_time_to_ready(35395)>500ms&spell_time_to_ready(53385)>500ms&spell_time_to_ready(53408)>500ms
+actions+=/cast_spell,id=48817
+`)
+
+func registerAPLPresets() {
+	core.RegisterAPLPreset(proto.Spec_SpecRetributionPaladin, "Retribution", RetributionAPL)
+}
@@ -23,6 +23,7 @@ func RegisterRetributionPaladin() {
 			player.Spec = playerSpec
 		},
 	)
+	registerAPLPresets()
 }
 
 func NewRetributionPaladin(character core.Character, options *proto.Player) *RetributionPaladin {
@@ -149,7 +150,7 @@ func (ret *RetributionPaladin) Reset(sim *core.Simulation) {
 	}
 
 	sim.RegisterExecutePhaseCallback(func(sim *core.Simulation, isExecute int) {
-		if isExecute == 20 {
+		if isExecute == 20 && !ret.IsUsingAPL() {
 			ret.OnGCDReady(sim)
 		}
 	})
@@ -115,6 +115,23 @@ func BenchmarkSimulate(b *testing.B) {
 	core.RaidBenchmark(b, rsr)
 }
 
+func TestAPLPresets(t *testing.T) {
+	core.RunAPLPresetTests(t, []core.APLPresetTestCase{
+		{
+			Preset: "Retribution",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceBloodElf,
+				Class:         proto.Class_ClassPaladin,
+				Equipment:     Phase1Gear,
+				Consumes:      FullConsumes,
+				Spec:          DefaultOptions,
+				Glyphs:        StandardGlyphs,
+				TalentsString: StandardTalents,
+			},
+		},
+	})
+}
+
 var StandardTalents = "050501-05-05232051203331302133231331"
 var StandardGlyphs = &proto.Glyphs{
 	Major1: int32(proto.PaladinMajorGlyph_GlyphOfSealOfVengeance),
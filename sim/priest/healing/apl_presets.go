@@ -0,0 +1,27 @@
+package healing
+
+import (
+	"github.com/wowsims/wotlk/sim/core"
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// Keeps Renew and Power Word: Shield up, uses the cooldown heals when they are
+// ready, and otherwise cycles through the other heals.
+const aplHeader = `actions+=/autocast_cooldowns,all_types=true
+actions+=/cast_spell,id=48068,if=!dot_is_active(48068)
+actions+=/cast_spell,id=48066
+actions+=/cast_spell,id=48113
+`
+const aplFiller = `actions+=/strict_sequence,actions=[{cast_spell,id=48063},{cast_spell,id=48071},{cast_spell,id=48120},{cast_spell,id=48072}]
+`
+
+var DiscAPL = core.APLRotationFromTextString(aplHeader + `actions+=/cast_spell,id=53007
+` + aplFiller)
+
+var HolyAPL = core.APLRotationFromTextString(aplHeader + `actions+=/cast_spell,id=48089
+` + aplFiller)
+
+func registerAPLPresets() {
+	core.RegisterAPLPreset(proto.Spec_SpecHealingPriest, "Disc", DiscAPL)
+	core.RegisterAPLPreset(proto.Spec_SpecHealingPriest, "Holy", HolyAPL)
+}
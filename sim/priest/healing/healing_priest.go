@@ -22,6 +22,7 @@ func RegisterHealingPriest() {
 			player.Spec = playerSpec
 		},
 	)
+	registerAPLPresets()
 }
 
 type HealingPriest struct {
@@ -74,6 +74,39 @@ func TestHoly(t *testing.T) {
 	}))
 }
 
+func TestAPLPresets(t *testing.T) {
+	// The presets use cooldown heals as soon as they are ready, which
+	// the hand-written cycle doesn't quite do, so allow a bit more slack.
+	core.RunAPLPresetTests(t, []core.APLPresetTestCase{
+		{
+			Preset: "Disc",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceUndead,
+				Class:         proto.Class_ClassPriest,
+				Equipment:     P1Gear,
+				Consumes:      FullConsumes,
+				Spec:          PlayerOptionsDisc,
+				Glyphs:        DiscGlyphs,
+				TalentsString: DiscTalents,
+			},
+			Tolerance: 0.05,
+		},
+		{
+			Preset: "Holy",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceUndead,
+				Class:         proto.Class_ClassPriest,
+				Equipment:     P1Gear,
+				Consumes:      FullConsumes,
+				Spec:          PlayerOptionsHoly,
+				Glyphs:        HolyGlyphs,
+				TalentsString: HolyTalents,
+			},
+			Tolerance: 0.05,
+		},
+	})
+}
+
 var DiscTalents = "0503203130300512301313231251-2351010303"
 var DiscGlyphs = &proto.Glyphs{
 	Major1: int32(proto.PriestMajorGlyph_GlyphOfPowerWordShield),
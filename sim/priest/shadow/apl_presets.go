@@ -0,0 +1,20 @@
+package shadow
+
+import (
+	"github.com/wowsims/wotlk/sim/core"
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+var ShadowAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+actions+=/cast_spell,id=48300,if=!dot_is_active(48300)
+actions+=/cast_spell,id=48160,if=dot_remaining_time(48160)<=spell_cast_time(48160)
+actions+=/cast_spell,id=48125,if=!dot_is_active(48125)&aura_num_stacks(15258)>=5
+actions+=/cast_spell,id=48127
+actions+=/cast_spell,id=48158
+actions+=/cast_spell,id=14751
+actions+=/cast_spell,id=48156@3
+`)
+
+func registerAPLPresets() {
+	core.RegisterAPLPreset(proto.Spec_SpecShadowPriest, "Shadow", ShadowAPL)
+}
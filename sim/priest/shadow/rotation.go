@@ -71,7 +71,7 @@ func (spriest *ShadowPriest) tryUseGCD(sim *core.Simulation) {
 }
 
 func (spriest *ShadowPriest) chooseSpellAOE(sim *core.Simulation) (*core.Spell, *core.Unit) {
-	if len(sim.Encounter.Targets) >= 4 {
+	if sim.GetNumTargets() >= 4 {
 		return spriest.MindSear[5], spriest.CurrentTarget
 	}
 
@@ -641,7 +641,7 @@ func (spriest *ShadowPriest) chooseSpellIdeal(sim *core.Simulation) (*core.Spell
 			return spriest.MindBlast, 0
 		} else {
 			//numTicks = 3
-			if spriest.rotation.RotationType == 4 && len(sim.Encounter.Targets) >= 3 {
+			if spriest.rotation.RotationType == 4 && sim.GetNumTargets() >= 3 {
 				return spriest.MindSear[numTicks], 0
 			} else {
 				return spriest.MindFlay[numTicks], 0
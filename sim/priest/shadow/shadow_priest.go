@@ -23,6 +23,7 @@ func RegisterShadowPriest() {
 			player.Spec = playerSpec
 		},
 	)
+	registerAPLPresets()
 }
 
 func NewShadowPriest(character core.Character, options *proto.Player) *ShadowPriest {
@@ -44,6 +44,23 @@ func TestShadow(t *testing.T) {
 	}))
 }
 
+func TestAPLPresets(t *testing.T) {
+	core.RunAPLPresetTests(t, []core.APLPresetTestCase{
+		{
+			Preset: "Shadow",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceUndead,
+				Class:         proto.Class_ClassPriest,
+				Equipment:     P1Gear,
+				Consumes:      FullConsumes,
+				Spec:          PlayerOptionsBasic,
+				Glyphs:        DefaultGlyphs,
+				TalentsString: DefaultTalents,
+			},
+		},
+	})
+}
+
 var DefaultTalents = "05032031--325023051223010323151301351"
 var DefaultGlyphs = &proto.Glyphs{
 	Major1: int32(proto.PriestMajorGlyph_GlyphOfShadow),
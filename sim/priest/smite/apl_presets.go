@@ -0,0 +1,24 @@
+package smite
+
+import (
+	"github.com/wowsims/wotlk/sim/core"
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// Spams Smite while Holy Fire is ticking, and waits briefly for Holy Fire
+// if it is about to come off cooldown.
+var SmiteAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+actions+=/cast_spell,id=14751,if=dot_remaining_time(48135)>=spell_cast_time(48123)
+actions+=/cast_spell,id=48123,if=dot_remaining_time(48135)>=spell_cast_time(48123)
+actions+=/cast_spell,id=48300,if=!dot_is_active(48300)
+actions+=/cast_spell,id=48125,if=!dot_is_active(48125)
+actions+=/cast_spell,id=48135
+actions+=/wait_until,condition=spell_is_ready(48135),if=spell_time_to_ready(48135)<=50ms
+actions+=/cast_spell,id=48158
+actions+=/cast_spell,id=48127
+actions+=/cast_spell,id=48123
+`)
+
+func registerAPLPresets() {
+	core.RegisterAPLPreset(proto.Spec_SpecSmitePriest, "Smite", SmiteAPL)
+}
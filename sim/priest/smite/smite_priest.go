@@ -23,6 +23,7 @@ func RegisterSmitePriest() {
 			player.Spec = playerSpec
 		},
 	)
+	registerAPLPresets()
 }
 
 func NewSmitePriest(character core.Character, options *proto.Player) *SmitePriest {
@@ -38,6 +38,23 @@ func TestSmite(t *testing.T) {
 	}))
 }
 
+func TestAPLPresets(t *testing.T) {
+	core.RunAPLPresetTests(t, []core.APLPresetTestCase{
+		{
+			Preset: "Smite",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceUndead,
+				Class:         proto.Class_ClassPriest,
+				Equipment:     P1Gear,
+				Consumes:      FullConsumes,
+				Spec:          PlayerOptionsBasic,
+				Glyphs:        DefaultGlyphs,
+				TalentsString: DefaultTalents,
+			},
+		},
+	})
+}
+
 var DefaultTalents = "05332031013005023310001-005551002020152-00502"
 var DefaultGlyphs = &proto.Glyphs{
 	Major1: int32(proto.PriestMajorGlyph_GlyphOfSmite),
@@ -6,6 +6,7 @@ import (
 	"github.com/wowsims/wotlk/sim/core"
 	"github.com/wowsims/wotlk/sim/core/proto"
 	"github.com/wowsims/wotlk/sim/core/stats"
+	googleProto "google.golang.org/protobuf/proto"
 )
 
 // 1 moonkin, 1 ele shaman, 1 spriest, 2x arcane
@@ -192,67 +193,218 @@ var castersWithResto = &proto.Party{
 	},
 }
 
+var enhancementShaman = &proto.Player{
+	Name:      "Enhancement Shaman 1",
+	Race:      proto.Race_RaceTroll,
+	Class:     proto.Class_ClassShaman,
+	Equipment: EnhancementEquipment,
+	Spec: &proto.Player_EnhancementShaman{
+		EnhancementShaman: &proto.EnhancementShaman{
+			Rotation: &proto.EnhancementShaman_Rotation{
+				Totems: &proto.ShamanTotems{
+					Earth: proto.EarthTotem_TremorTotem,
+					Air:   proto.AirTotem_WrathOfAirTotem,
+					Fire:  proto.FireTotem_TotemOfWrath,
+					Water: proto.WaterTotem_ManaSpringTotem,
+				},
+			},
+			Options: &proto.EnhancementShaman_Options{
+				Shield:    proto.ShamanShield_LightningShield,
+				Bloodlust: true,
+				SyncType:  proto.ShamanSyncType_SyncMainhandOffhandSwings,
+			},
+		},
+	},
+	Consumes: &proto.Consumes{},
+	Buffs: &proto.IndividualBuffs{
+		BlessingOfKings:  true,
+		BlessingOfWisdom: proto.TristateEffect_TristateEffectImproved,
+	},
+}
+
+var benchRaidBuffs = &proto.RaidBuffs{
+	GiftOfTheWild:    proto.TristateEffect_TristateEffectImproved,
+	ArcaneBrilliance: true,
+	Bloodlust:        true,
+	WrathOfAirTotem:  true,
+	ManaSpringTotem:  proto.TristateEffect_TristateEffectImproved,
+}
+
+var benchDebuffs = &proto.Debuffs{
+	JudgementOfWisdom: true,
+	CurseOfElements:   true,
+}
+
+func benchEncounter(numTargets int) *proto.Encounter {
+	encounter := &proto.Encounter{
+		Duration:             180,
+		ExecuteProportion_20: 0.1,
+	}
+	for i := 0; i < numTargets; i++ {
+		encounter.Targets = append(encounter.Targets, &proto.Target{
+			Stats:   stats.Stats{stats.Armor: 7684}.ToFloatArray(),
+			MobType: proto.MobType_MobTypeDemon,
+		})
+	}
+	return encounter
+}
+
 func BenchmarkSimulate(b *testing.B) {
 	rsr := &proto.RaidSimRequest{
 		Raid: &proto.Raid{
 			Parties: []*proto.Party{
 				castersWithElemental,
 				castersWithResto,
+				{
+					Players: []*proto.Player{
+						enhancementShaman,
+					},
+				},
+			},
+			Buffs:   benchRaidBuffs,
+			Debuffs: benchDebuffs,
+		},
+		Encounter:  benchEncounter(1),
+		SimOptions: core.AverageDefaultSimTestOptions,
+	}
+
+	core.RaidBenchmark(b, rsr)
+}
+
+// 5 full parties of casters and melee, for the most pets, auras and pending
+// actions at once.
+func BenchmarkSimulate25Man(b *testing.B) {
+	raid := &proto.Raid{
+		Buffs:   benchRaidBuffs,
+		Debuffs: benchDebuffs,
+	}
+	for i := 0; i < 5; i++ {
+		party := googleProto.Clone(castersWithElemental).(*proto.Party)
+		party.Players = append(party.Players, googleProto.Clone(enhancementShaman).(*proto.Player))
+		raid.Parties = append(raid.Parties, party)
+	}
+
+	rsr := &proto.RaidSimRequest{
+		Raid:       raid,
+		Encounter:  benchEncounter(1),
+		SimOptions: core.AverageDefaultSimTestOptions,
+	}
+
+	core.RaidBenchmark(b, rsr)
+}
+
+func BenchmarkSimulateMultiTarget(b *testing.B) {
+	rsr := &proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{
+				castersWithElemental,
+				castersWithResto,
+				{
+					Players: []*proto.Player{
+						enhancementShaman,
+					},
+				},
+			},
+			Buffs:   benchRaidBuffs,
+			Debuffs: benchDebuffs,
+		},
+		Encounter:  benchEncounter(4),
+		SimOptions: core.AverageDefaultSimTestOptions,
+	}
+
+	core.RaidBenchmark(b, rsr)
+}
+
+// A party of healers, each healing the raid under a constant damage intake.
+func BenchmarkSimulateHealing(b *testing.B) {
+	healingModel := &proto.HealingModel{
+		Hps:            8000,
+		CadenceSeconds: 2,
+	}
+	buffs := &proto.IndividualBuffs{
+		BlessingOfKings:  true,
+		BlessingOfWisdom: proto.TristateEffect_TristateEffectImproved,
+	}
+	consumes := &proto.Consumes{
+		DefaultPotion: proto.Potions_RunicManaPotion,
+	}
+
+	rsr := &proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{
 				{
 					Players: []*proto.Player{
 						{
-							Name:      "Enhancement Shaman 1",
-							Race:      proto.Race_RaceTroll,
-							Class:     proto.Class_ClassShaman,
-							Equipment: EnhancementEquipment,
-							Spec: &proto.Player_EnhancementShaman{
-								EnhancementShaman: &proto.EnhancementShaman{
-									Rotation: &proto.EnhancementShaman_Rotation{
+							Name:          "Restoration Shaman 1",
+							Race:          proto.Race_RaceTroll,
+							Class:         proto.Class_ClassShaman,
+							Equipment:     ElementalEquipment,
+							TalentsString: "-3020503-50005331335310501122331251",
+							Spec: &proto.Player_RestorationShaman{
+								RestorationShaman: &proto.RestorationShaman{
+									Options: &proto.RestorationShaman_Options{
+										Shield:    proto.ShamanShield_WaterShield,
+										Bloodlust: true,
+									},
+									Rotation: &proto.RestorationShaman_Rotation{
 										Totems: &proto.ShamanTotems{
 											Earth: proto.EarthTotem_TremorTotem,
 											Air:   proto.AirTotem_WrathOfAirTotem,
-											Fire:  proto.FireTotem_TotemOfWrath,
+											Fire:  proto.FireTotem_FlametongueTotem,
 											Water: proto.WaterTotem_ManaSpringTotem,
 										},
 									},
-									Options: &proto.EnhancementShaman_Options{
-										Shield:    proto.ShamanShield_LightningShield,
-										Bloodlust: true,
-										SyncType:  proto.ShamanSyncType_SyncMainhandOffhandSwings,
+								},
+							},
+							Consumes:     consumes,
+							Buffs:        buffs,
+							HealingModel: healingModel,
+						},
+						{
+							Name:          "Restoration Druid 1",
+							Race:          proto.Race_RaceTauren,
+							Class:         proto.Class_ClassDruid,
+							Equipment:     MoonkinEquipment,
+							TalentsString: "05320031103--230023312131502331050313051",
+							Spec: &proto.Player_RestorationDruid{
+								RestorationDruid: &proto.RestorationDruid{
+									Options: &proto.RestorationDruid_Options{
+										InnervateTarget: &proto.RaidTarget{TargetIndex: 1},
 									},
+									Rotation: &proto.RestorationDruid_Rotation{},
 								},
 							},
-							Consumes: &proto.Consumes{},
-							Buffs: &proto.IndividualBuffs{
-								BlessingOfKings:  true,
-								BlessingOfWisdom: proto.TristateEffect_TristateEffectImproved,
+							Consumes:     consumes,
+							Buffs:        buffs,
+							HealingModel: healingModel,
+						},
+						{
+							Name:          "Discipline Priest 1",
+							Race:          proto.Race_RaceUndead,
+							Class:         proto.Class_ClassPriest,
+							Equipment:     ShadowEquipment,
+							TalentsString: "0503203130300512301313231251-2351010303",
+							Spec: &proto.Player_HealingPriest{
+								HealingPriest: &proto.HealingPriest{
+									Options: &proto.HealingPriest_Options{
+										UseInnerFire:      true,
+										UseShadowfiend:    true,
+										RapturesPerMinute: 5,
+									},
+									Rotation: &proto.HealingPriest_Rotation{},
+								},
 							},
+							Consumes:     consumes,
+							Buffs:        buffs,
+							HealingModel: healingModel,
 						},
 					},
 				},
 			},
-			Buffs: &proto.RaidBuffs{
-				GiftOfTheWild:    proto.TristateEffect_TristateEffectImproved,
-				ArcaneBrilliance: true,
-				Bloodlust:        true,
-				WrathOfAirTotem:  true,
-				ManaSpringTotem:  proto.TristateEffect_TristateEffectImproved,
-			},
-			Debuffs: &proto.Debuffs{
-				JudgementOfWisdom: true,
-				CurseOfElements:   true,
-			},
-		},
-		Encounter: &proto.Encounter{
-			Duration:             180,
-			ExecuteProportion_20: 0.1,
-			Targets: []*proto.Target{
-				{
-					Stats:   stats.Stats{stats.Armor: 7684}.ToFloatArray(),
-					MobType: proto.MobType_MobTypeDemon,
-				},
-			},
+			Buffs:   benchRaidBuffs,
+			Debuffs: benchDebuffs,
 		},
+		Encounter:  benchEncounter(1),
 		SimOptions: core.AverageDefaultSimTestOptions,
 	}
 
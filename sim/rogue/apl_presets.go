@@ -0,0 +1,45 @@
+package rogue
+
+import (
+	"github.com/wowsims/wotlk/sim/core"
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// Mutilate with Envenom as the primary finisher, keeping Hunger for Blood,
+// Slice and Dice and Rupture up.
+var AssassinationAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+actions+=/cast_spell,id=6774,if=combo_points>=1&!aura_is_active(6774)
+actions+=/cast_spell,id=51662,if=!aura_is_active(51662)
+actions+=/cast_spell,id=48672,if=combo_points>=4&!dot_is_active(48672)&remaining_time>=10s
+actions+=/cast_spell,id=57993,if=combo_points>=4
+actions+=/cast_spell,id=48666,if=combo_points<4
+`)
+
+// Sinister Strike builds towards 5 point Ruptures, with Eviscerate used while
+// Rupture and Slice and Dice have plenty of time left.
+var CombatAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+actions+=/wait_until,condition=!aura_is_active(51690),if=aura_is_active(51690)
+actions+=/cast_spell,id=6774,if=combo_points>=1&!aura_is_active(6774)
+actions+=/cast_spell,id=48672,if=combo_points=5&!dot_is_active(48672)&remaining_time>=10s
+actions+=/cast_spell,id=48668,if=combo_points=5&(dot_remaining_time(48672)>=6s|remaining_time<10s)&aura_remaining_time(6774)>=6s
+actions+=/cast_spell,id=6774,if=combo_points=5&aura_remaining_time(6774)<6s
+actions+=/cast_spell,id=48638,if=combo_points<5
+`)
+
+// Hemorrhage builds towards 5 point Eviscerates, opening with Garrote. Building
+// at 4 combo points is held off unless energy is about to cap, since Honor
+// Among Thieves will usually provide the last one.
+var SubtletyAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+actions+=/cast_spell,id=48676,if=time<1s
+actions+=/cast_spell,id=6774,if=combo_points>=1&!aura_is_active(6774)
+actions+=/cast_spell,id=36554,if=combo_points=5&!dot_is_active(48672)&energy>=45
+actions+=/cast_spell,id=48672,if=combo_points=5&!dot_is_active(48672)&remaining_time>=8s
+actions+=/cast_spell,id=48668,if=combo_points=5
+actions+=/cast_spell,id=48660,if=combo_points<4|energy>90
+`)
+
+func registerAPLPresets() {
+	core.RegisterAPLPreset(proto.Spec_SpecRogue, "Assassination", AssassinationAPL)
+	core.RegisterAPLPreset(proto.Spec_SpecRogue, "Combat", CombatAPL)
+	core.RegisterAPLPreset(proto.Spec_SpecRogue, "Subtlety", SubtletyAPL)
+}
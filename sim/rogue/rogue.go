@@ -23,6 +23,7 @@ func RegisterRogue() {
 			player.Spec = playerSpec
 		},
 	)
+	registerAPLPresets()
 }
 
 const (
@@ -182,6 +183,10 @@ func (rogue *Rogue) Initialize() {
 	rogue.registerEnvenom()
 
 	rogue.finishingMoveEffectApplier = rogue.makeFinishingMoveEffectApplier()
+
+	if !rogue.IsUsingAPL() {
+		rogue.RegisterTargetSwitchHandler(rogue.onTargetSwitch)
+	}
 }
 
 func (rogue *Rogue) ApplyEnergyTickMultiplier(multiplier float64) {
@@ -189,10 +194,13 @@ func (rogue *Rogue) ApplyEnergyTickMultiplier(multiplier float64) {
 }
 
 func (rogue *Rogue) Reset(sim *core.Simulation) {
-	for _, mcd := range rogue.GetMajorCooldowns() {
-		mcd.Disable()
+	// The hand-written rotations enable cooldowns once Slice and Dice is up.
+	if !rogue.IsUsingAPL() {
+		for _, mcd := range rogue.GetMajorCooldowns() {
+			mcd.Disable()
+		}
+		rogue.allMCDsDisabled = true
 	}
-	rogue.allMCDsDisabled = true
 
 	// Stealth triggered effects (Overkill and Master of Subtlety) pre-pull activation
 	if rogue.Rotation.OpenWithGarrote || rogue.Options.StartingOverkillDuration > 0 {
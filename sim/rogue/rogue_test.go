@@ -172,6 +172,47 @@ func TestCritDamageMultipliers(t *testing.T) {
 	GenerateCriticalDamageMultiplierTestCase(t, "FinisherREDLethalityPotW", GearWithRED, CombatTalents, PlayerOptionsCombatDI, Finisher, 2.472)
 }
 
+func TestAPLPresets(t *testing.T) {
+	core.RunAPLPresetTests(t, []core.APLPresetTestCase{
+		{
+			Preset: "Assassination",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceHuman,
+				Class:         proto.Class_ClassRogue,
+				Equipment:     P1Gear,
+				Consumes:      FullConsumes,
+				Spec:          PlayerOptionsAssassinationDI,
+				Glyphs:        AssassinationGlyphs,
+				TalentsString: AssassinationTalents,
+			},
+		},
+		{
+			Preset: "Combat",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceHuman,
+				Class:         proto.Class_ClassRogue,
+				Equipment:     P1Gear,
+				Consumes:      FullConsumes,
+				Spec:          PlayerOptionsCombatDI,
+				Glyphs:        CombatGlyphs,
+				TalentsString: CombatTalents,
+			},
+		},
+		{
+			Preset: "Subtlety",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceBloodElf,
+				Class:         proto.Class_ClassRogue,
+				Equipment:     SubtletyP2Gear,
+				Consumes:      FullConsumes,
+				Spec:          PlayerOptionsSubtletyID,
+				Glyphs:        SubtletyGlyphs,
+				TalentsString: SubtletyTalents,
+			},
+		},
+	})
+}
+
 func BenchmarkSimulate(b *testing.B) {
 	rsr := &proto.RaidSimRequest{
 		Raid: core.SinglePlayerRaidProto(
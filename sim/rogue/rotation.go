@@ -26,6 +26,9 @@ type prio struct {
 }
 
 func (rogue *Rogue) OnEnergyGain(sim *core.Simulation) {
+	if rogue.IsUsingAPL() {
+		return
+	}
 	rogue.TryUseCooldowns(sim)
 
 	if !rogue.GCD.IsReady(sim) {
@@ -61,3 +64,17 @@ func (rogue *Rogue) setupRotation(sim *core.Simulation) {
 	}
 	rogue.rotation.setup(sim, rogue)
 }
+
+// Puts up Expose Armor on the raid's new target, as the rotation did on the
+// first one. Single target rotations already check the current target.
+func (rogue *Rogue) onTargetSwitch(sim *core.Simulation, oldTarget *core.Unit, newTarget *core.Unit) {
+	if multi, ok := rogue.rotation.(*rotation_multi); ok {
+		oldAura := rogue.ExposeArmorAuras.Get(oldTarget)
+		for i := range multi.priorityItems {
+			if item := &multi.priorityItems[i]; item.Aura == oldAura {
+				item.Aura = rogue.ExposeArmorAuras.Get(newTarget)
+				item.CastCount = 0
+			}
+		}
+	}
+}
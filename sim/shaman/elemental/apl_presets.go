@@ -0,0 +1,22 @@
+package elemental
+
+import (
+	"github.com/wowsims/wotlk/sim/core"
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// Single target adaptive rotation. Thunderstorm is used whenever mana falls
+// behind the remaining fight duration, and Lava Burst is only cast while Flame
+// Shock will still be up when it lands.
+var ElementalAPL = core.APLRotationFromTextString(`actions+=/cast_spell,id=66842
+actions+=/autocast_cooldowns,all_types=true
+actions+=/cast_spell,id=59159,if=mana_percent<remaining_time/(time+remaining_time)-10%
+actions+=/cast_spell,id=49233,if=!dot_is_active(49233)
+actions+=/cast_spell,id=60043,if=dot_remaining_time(49233)>spell_cast_time(60043)
+actions+=/wait_until,condition=spell_is_ready(60043),if=spell_time_to_ready(60043)<=175ms&dot_remaining_time(49233)>spell_cast_time(60043)+spell_time_to_ready(60043)
+actions+=/cast_spell,id=49238
+`)
+
+func registerAPLPresets() {
+	core.RegisterAPLPreset(proto.Spec_SpecElementalShaman, "Elemental", ElementalAPL)
+}
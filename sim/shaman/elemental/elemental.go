@@ -21,6 +21,7 @@ func RegisterElementalShaman() {
 			player.Spec = playerSpec
 		},
 	)
+	registerAPLPresets()
 }
 
 func NewElementalShaman(character core.Character, options *proto.Player) *ElementalShaman {
@@ -81,6 +81,23 @@ func BenchmarkSimulate(b *testing.B) {
 	core.RaidBenchmark(b, rsr)
 }
 
+func TestAPLPresets(t *testing.T) {
+	core.RunAPLPresetTests(t, []core.APLPresetTestCase{
+		{
+			Preset: "Elemental",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceTroll,
+				Class:         proto.Class_ClassShaman,
+				Equipment:     P1Gear,
+				Consumes:      FullConsumes,
+				Spec:          PlayerOptionsAdaptive,
+				Glyphs:        StandardGlyphs,
+				TalentsString: StandardTalents,
+			},
+		},
+	})
+}
+
 var StandardTalents = "0532001523212351322301351-005052031"
 var StandardGlyphs = &proto.Glyphs{
 	Major1: int32(proto.ShamanMajorGlyph_GlyphOfLava),
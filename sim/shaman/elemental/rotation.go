@@ -120,22 +120,22 @@ func (rotation *AdaptiveRotation) DoAction(eleShaman *ElementalShaman, sim *core
 func (rotation *AdaptiveRotation) Reset(eleShaman *ElementalShaman, sim *core.Simulation) {
 	rotation.fnmm = 1.0
 	rotation.clmm = 1.0
-	if len(sim.Encounter.Targets) > 4 {
+	if sim.GetNumTargets() > 4 {
 		// 5+ targets FN is better
 		rotation.fnmm = 0.33
 		// Allow CL as long as you have decent mana (leaving most mana for FN)
 		rotation.clmm = 0.5
-	} else if len(sim.Encounter.Targets) == 4 {
+	} else if sim.GetNumTargets() == 4 {
 		// 4 targets, enable both similar prio, prob looking at real AoE now (short fight)
 		rotation.clmm = 0.33
 		rotation.fnmm = 0.33
-	} else if len(sim.Encounter.Targets) == 3 {
+	} else if sim.GetNumTargets() == 3 {
 		// 3 targets, enable both, but prio CL (more efficient)
 		//  Still trying to be very mana efficient as 3 targets
 		//  is still often a "boss fight" and could be long.
 		rotation.clmm = 0.33
 		rotation.fnmm = 0.66
-	} else if len(sim.Encounter.Targets) == 2 {
+	} else if sim.GetNumTargets() == 2 {
 		// enable CL with 2
 		rotation.clmm = 0.33
 	}
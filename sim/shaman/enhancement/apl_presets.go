@@ -0,0 +1,25 @@
+package enhancement
+
+import (
+	"github.com/wowsims/wotlk/sim/core"
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// Default priority rotation with Earth Shock as the primary shock and Flame
+// Shock woven in whenever it falls off.
+var EnhancementAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+actions+=/cast_spell,id=49238,if=aura_num_stacks(53817)=5
+actions+=/cast_spell,id=17364,if=!aura_is_active(17364,on_target=true)
+actions+=/cast_spell,id=58734,if=!dot_is_active(58734)
+actions+=/cast_spell,id=17364
+actions+=/cast_spell,id=49233,if=!dot_is_active(49233)
+actions+=/cast_spell,id=49231
+actions+=/cast_spell,id=61657,if=dot_is_active(58734)&mana>3000
+actions+=/cast_spell,id=49281,if=!aura_is_active(49281)
+actions+=/cast_spell,id=66842
+actions+=/cast_spell,id=60103
+`)
+
+func registerAPLPresets() {
+	core.RegisterAPLPreset(proto.Spec_SpecEnhancementShaman, "Enhancement", EnhancementAPL)
+}
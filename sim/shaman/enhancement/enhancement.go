@@ -24,6 +24,7 @@ func RegisterEnhancementShaman() {
 			player.Spec = playerSpec
 		},
 	)
+	registerAPLPresets()
 }
 
 func NewEnhancementShaman(character core.Character, options *proto.Player) *EnhancementShaman {
@@ -74,6 +74,23 @@ func BenchmarkSimulate(b *testing.B) {
 	core.RaidBenchmark(b, rsr)
 }
 
+func TestAPLPresets(t *testing.T) {
+	core.RunAPLPresetTests(t, []core.APLPresetTestCase{
+		{
+			Preset: "Enhancement",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceTroll,
+				Class:         proto.Class_ClassShaman,
+				Equipment:     Phase1Gear,
+				Consumes:      FullConsumes,
+				Spec:          PlayerOptionsBasic,
+				Glyphs:        StandardGlyphs,
+				TalentsString: StandardTalents,
+			},
+		},
+	})
+}
+
 var StandardTalents = "053030152-30405003105021333031131031051"
 var StandardGlyphs = &proto.Glyphs{
 	Major1: int32(proto.ShamanMajorGlyph_GlyphOfStormstrike),
@@ -0,0 +1,17 @@
+package restoration
+
+import (
+	"github.com/wowsims/wotlk/sim/core"
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// Keeps totems up and spams Lesser Healing Wave, which is what the automatic
+// heal selection picks outside of a full party.
+var RestorationAPL = core.APLRotationFromTextString(`actions+=/cast_spell,id=66842
+actions+=/autocast_cooldowns,all_types=true
+actions+=/cast_spell,id=49276
+`)
+
+func registerAPLPresets() {
+	core.RegisterAPLPreset(proto.Spec_SpecRestorationShaman, "Restoration", RestorationAPL)
+}
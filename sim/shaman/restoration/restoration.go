@@ -23,6 +23,7 @@ func RegisterRestorationShaman() {
 			player.Spec = playerSpec
 		},
 	)
+	registerAPLPresets()
 }
 
 func NewRestorationShaman(character core.Character, options *proto.Player) *RestorationShaman {
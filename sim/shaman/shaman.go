@@ -126,6 +126,7 @@ type Shaman struct {
 	WindfuryTotem        *core.Spell
 	WrathOfAirTotem      *core.Spell
 	FlametongueTotem     *core.Spell
+	CallOfTheElements    *core.Spell
 
 	MaelstromWeaponAura *core.Aura
 
@@ -239,6 +240,7 @@ func (shaman *Shaman) Initialize() {
 	shaman.registerStoneskinTotemSpell()
 	shaman.registerWindfuryTotemSpell()
 	shaman.registerWrathOfAirTotemSpell()
+	shaman.registerCallOfTheElementsSpell()
 
 	shaman.registerBloodlustCD()
 
@@ -136,6 +136,52 @@ func (shaman *Shaman) NextTotemAt(_ *core.Simulation) time.Duration {
 	return nextTotemAt
 }
 
+// Returns the spell for the totem configured to be dropped next in the given
+// slot, or nil if there is none.
+func (shaman *Shaman) nextTotemSpell(totemTypeIdx int) *core.Spell {
+	nextDrop := shaman.NextTotemDropType[totemTypeIdx]
+	switch totemTypeIdx {
+	case AirTotem:
+		switch proto.AirTotem(nextDrop) {
+		case proto.AirTotem_WrathOfAirTotem:
+			return shaman.WrathOfAirTotem
+		case proto.AirTotem_WindfuryTotem:
+			return shaman.WindfuryTotem
+		}
+
+	case EarthTotem:
+		switch proto.EarthTotem(nextDrop) {
+		case proto.EarthTotem_StrengthOfEarthTotem:
+			return shaman.StrengthOfEarthTotem
+		case proto.EarthTotem_TremorTotem:
+			return shaman.TremorTotem
+		case proto.EarthTotem_StoneskinTotem:
+			return shaman.StoneskinTotem
+		}
+
+	case FireTotem:
+		switch proto.FireTotem(nextDrop) {
+		case proto.FireTotem_TotemOfWrath:
+			return shaman.TotemOfWrath
+		case proto.FireTotem_SearingTotem:
+			return shaman.SearingTotem
+		case proto.FireTotem_MagmaTotem:
+			return shaman.MagmaTotem
+		case proto.FireTotem_FlametongueTotem:
+			return shaman.FlametongueTotem
+		}
+
+	case WaterTotem:
+		switch proto.WaterTotem(nextDrop) {
+		case proto.WaterTotem_ManaSpringTotem:
+			return shaman.ManaSpringTotem
+		case proto.WaterTotem_HealingStreamTotem:
+			return shaman.HealingStreamTotem
+		}
+	}
+	return nil
+}
+
 // TryDropTotems will check to see if totems need to be re-cast.
 //
 //	Returns whether we tried to cast a totem, regardless of whether it succeeded.
@@ -144,46 +190,9 @@ func (shaman *Shaman) TryDropTotems(sim *core.Simulation) bool {
 
 	casted := false
 	for totemTypeIdx, totemExpiration := range shaman.NextTotemDrops {
-		nextDrop := shaman.NextTotemDropType[totemTypeIdx]
 		if sim.CurrentTime >= totemExpiration {
-			switch totemTypeIdx {
-			case AirTotem:
-				switch proto.AirTotem(nextDrop) {
-				case proto.AirTotem_WrathOfAirTotem:
-					spell = shaman.WrathOfAirTotem
-				case proto.AirTotem_WindfuryTotem:
-					spell = shaman.WindfuryTotem
-				}
-
-			case EarthTotem:
-				switch proto.EarthTotem(nextDrop) {
-				case proto.EarthTotem_StrengthOfEarthTotem:
-					spell = shaman.StrengthOfEarthTotem
-				case proto.EarthTotem_TremorTotem:
-					spell = shaman.TremorTotem
-				case proto.EarthTotem_StoneskinTotem:
-					spell = shaman.StoneskinTotem
-				}
-
-			case FireTotem:
-				switch proto.FireTotem(nextDrop) {
-				case proto.FireTotem_TotemOfWrath:
-					spell = shaman.TotemOfWrath
-				case proto.FireTotem_SearingTotem:
-					spell = shaman.SearingTotem
-				case proto.FireTotem_MagmaTotem:
-					spell = shaman.MagmaTotem
-				case proto.FireTotem_FlametongueTotem:
-					spell = shaman.FlametongueTotem
-				}
-
-			case WaterTotem:
-				switch proto.WaterTotem(nextDrop) {
-				case proto.WaterTotem_ManaSpringTotem:
-					spell = shaman.ManaSpringTotem
-				case proto.WaterTotem_HealingStreamTotem:
-					spell = shaman.HealingStreamTotem
-				}
+			if nextSpell := shaman.nextTotemSpell(totemTypeIdx); nextSpell != nil {
+				spell = nextSpell
 			}
 		}
 		if spell != nil {
@@ -200,3 +209,44 @@ func (shaman *Shaman) TryDropTotems(sim *core.Simulation) bool {
 	}
 	return casted
 }
+
+// Drops all configured totems which are due to be re-cast, for APL rotations.
+// Totems with their own GCD (Searing and Magma) are left out, and should be
+// cast directly by the rotation.
+func (shaman *Shaman) registerCallOfTheElementsSpell() {
+	dueTotems := func(sim *core.Simulation, callback func(*core.Spell)) {
+		for totemTypeIdx, totemExpiration := range shaman.NextTotemDrops {
+			if sim.CurrentTime < totemExpiration {
+				continue
+			}
+			if spell := shaman.nextTotemSpell(totemTypeIdx); spell != nil && spell.DefaultCast.GCD == 0 {
+				callback(spell)
+			}
+		}
+	}
+
+	shaman.CallOfTheElements = shaman.RegisterSpell(core.SpellConfig{
+		ActionID: core.ActionID{SpellID: 66842},
+		Flags:    core.SpellFlagAPL,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: time.Second,
+			},
+			IgnoreHaste: true,
+		},
+		ExtraCastCondition: func(sim *core.Simulation, target *core.Unit) bool {
+			canCast := false
+			dueTotems(sim, func(spell *core.Spell) {
+				canCast = canCast || spell.CanCast(sim, target)
+			})
+			return canCast
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, _ *core.Spell) {
+			dueTotems(sim, func(spell *core.Spell) {
+				spell.Cast(sim, target)
+			})
+		},
+	})
+}
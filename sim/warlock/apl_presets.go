@@ -0,0 +1,51 @@
+package warlock
+
+import (
+	"github.com/wowsims/wotlk/sim/core"
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// Keeps Glyph of Life Tap up, except near the end of the fight.
+const aplGlyphOfLifeTap = `actions+=/cast_spell,id=57946,if=aura_remaining_time(63321)<1s&remaining_time>10s&(remaining_time>55s|mana_percent<35%)
+`
+
+// Filler and mana regen shared by all presets.
+const aplFiller = `actions+=/cast_spell,id=47809
+actions+=/cast_spell,id=57946
+`
+
+var AfflictionAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+actions+=/cast_spell,id=59164,if=dot_is_active(47813)&aura_remaining_time(59164,on_target=true)<spell_cast_time(59164)+2s&remaining_time>5s
+actions+=/cast_spell,id=47813,if=!dot_is_active(47813)
+` + aplGlyphOfLifeTap + `actions+=/cast_spell,id=47843,if=dot_remaining_time(47843)<=spell_cast_time(47843)&remaining_time>=9s+spell_cast_time(47843)
+actions+=/cast_spell,id=47864,if=!dot_is_active(47864)&remaining_time>=16s
+# Drain Soul is not a true channel, so wait for it to tick instead of casting over it.
+actions+=/cast_spell,id=47855,if=target_health_percent<25%&!dot_is_active(47855)
+actions+=/wait,duration=500ms,if=target_health_percent<25%&dot_is_active(47855)
+` + aplFiller)
+
+var DemonologyAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+actions+=/cast_spell,id=47193
+actions+=/cast_spell,id=50589
+actions+=/cast_spell,id=47867,if=!dot_is_active(47867)&remaining_time>=60s
+` + aplGlyphOfLifeTap + `actions+=/cast_spell,id=47864,if=!dot_is_active(47867)&!dot_is_active(47864)&remaining_time>=22s
+actions+=/cast_spell,id=47813,if=!dot_is_active(47813)&remaining_time>=12s
+actions+=/cast_spell,id=47811,if=dot_remaining_time(47811)<=spell_cast_time(47811)&remaining_time>=12s+spell_cast_time(47811)
+actions+=/cast_spell,id=47825,if=aura_is_active(63167)
+actions+=/cast_spell,id=47838,if=aura_is_active(71165)
+` + aplFiller)
+
+var DestructionAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+actions+=/cast_spell,id=17962,if=dot_is_active(47811)
+actions+=/cast_spell,id=47867,if=!dot_is_active(47867)&remaining_time>=60s
+` + aplGlyphOfLifeTap + `actions+=/cast_spell,id=47864,if=!dot_is_active(47867)&!dot_is_active(47864)&remaining_time>=22s
+actions+=/cast_spell,id=47811,if=dot_remaining_time(47811)<=spell_cast_time(47811)&remaining_time>=6s+spell_cast_time(47811)
+actions+=/cast_spell,id=59172
+actions+=/cast_spell,id=47838
+` + aplFiller)
+
+func registerAPLPresets() {
+	core.RegisterAPLPreset(proto.Spec_SpecWarlock, "Affliction", AfflictionAPL)
+	core.RegisterAPLPreset(proto.Spec_SpecWarlock, "Demonology", DemonologyAPL)
+	core.RegisterAPLPreset(proto.Spec_SpecWarlock, "Destruction", DestructionAPL)
+}
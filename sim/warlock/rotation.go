@@ -494,7 +494,8 @@ func (warlock *Warlock) getNextActionTime(sim *core.Simulation, skipIndex int) t
 	return core.MaxDuration(core.GCDMin, nextSpellTime)
 }
 
-func (warlock *Warlock) OnGCDReady(sim *core.Simulation) {
+// Updates pet-based spell power bonuses, which change with the pet's stats.
+func (warlock *Warlock) updatePetBonuses(sim *core.Simulation) {
 	if warlock.Options.Summon != proto.Warlock_Options_NoSummon && warlock.Talents.DemonicKnowledge > 0 {
 		// TODO: investigate a better way of handling this like a "reverse inheritance" for pets.
 		bonus := (warlock.Pet.GetStat(stats.Stamina) + warlock.Pet.GetStat(stats.Intellect)) * (0.04 * float64(warlock.Talents.DemonicKnowledge))
@@ -519,6 +520,10 @@ func (warlock *Warlock) OnGCDReady(sim *core.Simulation) {
 
 		warlock.PreviousTime = sim.CurrentTime
 	}
+}
+
+func (warlock *Warlock) OnGCDReady(sim *core.Simulation) {
+	warlock.updatePetBonuses(sim)
 
 	for _, ac := range warlock.acl {
 		action, target := ac.Condition(sim)
@@ -72,6 +72,8 @@ type Warlock struct {
 	acl           []ActionCondition
 	skipList      map[int]struct{}
 	swapped       bool
+
+	aplHelperAura *core.Aura
 }
 
 type ACLaction int
@@ -128,6 +130,23 @@ func (warlock *Warlock) Initialize() {
 
 	warlock.defineRotation()
 
+	// Does the parts of the hand-written rotation which aren't about choosing
+	// spells, for when an APL rotation is used instead: updating pet bonuses,
+	// and stopping Drain Soul when another spell is cast.
+	warlock.aplHelperAura = warlock.RegisterAura(core.Aura{
+		Label:    "APL Rotation Helper",
+		Duration: core.NeverExpires,
+		OnCastComplete: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell) {
+			warlock.updatePetBonuses(sim)
+
+			if spell != warlock.DrainSoul && spell.DefaultCast.GCD > 0 {
+				if dot := warlock.DrainSoul.CurDot(); dot.IsActive() && dot.TickCount != 0 {
+					dot.Cancel(sim)
+				}
+			}
+		},
+	})
+
 	precastSpell := warlock.ShadowBolt
 	if warlock.Rotation.Type == proto.Warlock_Rotation_Destruction {
 		precastSpell = warlock.SoulFire
@@ -182,6 +201,10 @@ func (warlock *Warlock) Reset(sim *core.Simulation) {
 		proto.ItemSlot_ItemSlotOffHand, proto.ItemSlot_ItemSlotRanged}, false)
 	warlock.swapped = true
 	warlock.setupCooldowns(sim)
+
+	if warlock.IsUsingAPL() {
+		warlock.aplHelperAura.Activate(sim)
+	}
 }
 
 func NewWarlock(character core.Character, options *proto.Player) *Warlock {
@@ -237,6 +260,7 @@ func RegisterWarlock() {
 			player.Spec = playerSpec
 		},
 	)
+	registerAPLPresets()
 }
 
 func init() {
@@ -59,6 +59,47 @@ func TestDestruction(t *testing.T) {
 	}))
 }
 
+func TestAPLPresets(t *testing.T) {
+	core.RunAPLPresetTests(t, []core.APLPresetTestCase{
+		{
+			Preset: "Affliction",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceOrc,
+				Class:         proto.Class_ClassWarlock,
+				Equipment:     P2Gear_affliction,
+				Consumes:      FullConsumes,
+				Spec:          DefaultAfflictionWarlock,
+				Glyphs:        AfflictionGlyphs,
+				TalentsString: AfflictionTalents,
+			},
+		},
+		{
+			Preset: "Demonology",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceOrc,
+				Class:         proto.Class_ClassWarlock,
+				Equipment:     P2Gear_demodestro,
+				Consumes:      FullConsumes,
+				Spec:          DefaultDemonologyWarlock,
+				Glyphs:        DemonologyGlyphs,
+				TalentsString: DemonologyTalents,
+			},
+		},
+		{
+			Preset: "Destruction",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceOrc,
+				Class:         proto.Class_ClassWarlock,
+				Equipment:     P2Gear_demodestro,
+				Consumes:      FullConsumes,
+				Spec:          DefaultDestroWarlock,
+				Glyphs:        DestructionGlyphs,
+				TalentsString: DestructionTalents,
+			},
+		},
+	})
+}
+
 var ItemFilter = core.ItemFilter{
 	WeaponTypes: []proto.WeaponType{
 		proto.WeaponType_WeaponTypeSword,
@@ -0,0 +1,36 @@
+package dps
+
+import (
+	"github.com/wowsims/wotlk/sim/core"
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// Bloodthirst and Whirlwind on cooldown with instant Slams from Bloodsurge,
+// dipping into Battle Stance to keep Rend up outside of execute range.
+var FuryAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+actions+=/cast_spell,id=47450@1,if=rage>=30
+actions+=/cast_spell,id=23881
+actions+=/cast_spell,id=47475,if=aura_is_active(46916)
+actions+=/cast_spell,id=1680,if=aura_is_active(2458)
+actions+=/cast_spell,id=2457,if=!dot_is_active(47465)&target_health_percent>20%&rage<=25
+actions+=/cast_spell,id=47465,if=aura_is_active(2457)&!dot_is_active(47465)
+actions+=/cast_spell,id=7384,if=aura_is_active(2457)&aura_is_active(68051)
+actions+=/cast_spell,id=2458
+actions+=/cast_spell,id=47471
+`)
+
+// Mortal Strike and Slam in Battle Stance, keeping Rend up and using Overpower
+// and Sudden Death Executes as they come up.
+var ArmsAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+actions+=/cast_spell,id=47450@1,if=rage>=50
+actions+=/cast_spell,id=47471
+actions+=/cast_spell,id=47465,if=!dot_is_active(47465)
+actions+=/cast_spell,id=7384,if=aura_is_active(68051)
+actions+=/cast_spell,id=47486,if=rage>=35
+actions+=/cast_spell,id=47475,if=rage>=25
+`)
+
+func registerAPLPresets() {
+	core.RegisterAPLPreset(proto.Spec_SpecWarrior, "Fury", FuryAPL)
+	core.RegisterAPLPreset(proto.Spec_SpecWarrior, "Arms", ArmsAPL)
+}
@@ -24,6 +24,7 @@ func RegisterDpsWarrior() {
 			player.Spec = playerSpec
 		},
 	)
+	registerAPLPresets()
 }
 
 type DpsWarrior struct {
@@ -71,7 +72,7 @@ func NewDpsWarrior(character core.Character, options *proto.Player) *DpsWarrior
 	}
 
 	war.EnableRageBar(rbo, func(sim *core.Simulation) {
-		if war.GCD.IsReady(sim) {
+		if war.GCD.IsReady(sim) && !war.IsUsingAPL() {
 			war.TryUseCooldowns(sim)
 			if war.GCD.IsReady(sim) {
 				// Pause rotation until after AM ticks to detect procs that happened right after the ticks
@@ -127,6 +128,11 @@ func (war *DpsWarrior) Initialize() {
 		}
 	}
 
+	// Stacks sunder on the raid's new target too.
+	war.RegisterTargetSwitchHandler(func(sim *core.Simulation, _ *core.Unit, _ *core.Unit) {
+		war.maintainSunder = war.Rotation.SunderArmor != proto.Warrior_Rotation_SunderArmorNone
+	})
+
 	if war.Rotation.StanceOption == proto.Warrior_Rotation_DefaultStance {
 		if war.Warrior.PrimaryTalentTree == warrior.FuryTree {
 			war.Rotation.StanceOption = proto.Warrior_Rotation_BerserkerStance
@@ -64,6 +64,35 @@ func TestArms(t *testing.T) {
 	}))
 }
 
+func TestAPLPresets(t *testing.T) {
+	core.RunAPLPresetTests(t, []core.APLPresetTestCase{
+		{
+			Preset: "Fury",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceOrc,
+				Class:         proto.Class_ClassWarrior,
+				Equipment:     FuryP1Gear,
+				Consumes:      FullConsumes,
+				Spec:          PlayerOptionsFury,
+				Glyphs:        FuryGlyphs,
+				TalentsString: FuryTalents,
+			},
+		},
+		{
+			Preset: "Arms",
+			Player: &proto.Player{
+				Race:          proto.Race_RaceOrc,
+				Class:         proto.Class_ClassWarrior,
+				Equipment:     FuryP1Gear,
+				Consumes:      FullConsumes,
+				Spec:          PlayerOptionsArms,
+				Glyphs:        ArmsGlyphs,
+				TalentsString: ArmsTalents,
+			},
+		},
+	})
+}
+
 func BenchmarkSimulate(b *testing.B) {
 	rsr := &proto.RaidSimRequest{
 		Raid: core.SinglePlayerRaidProto(
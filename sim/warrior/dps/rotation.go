@@ -21,6 +21,9 @@ func (war *DpsWarrior) OnGCDReady(sim *core.Simulation) {
 }
 
 func (war *DpsWarrior) OnAutoAttack(sim *core.Simulation, spell *core.Spell) {
+	if war.IsUsingAPL() {
+		return
+	}
 	war.tryQueueHsCleave(sim)
 }
 
@@ -367,7 +370,6 @@ func (war *DpsWarrior) makeCustomRotation() *common.CustomRotation {
 
 				if (war.ShouldSlam(sim) && war.CurrentRage() >= war.Rotation.SlamRageThreshold || war.ShouldInstantSlam(sim)) &&
 					war.Slam.CanCast(sim, war.CurrentTarget) {
-					war.AutoAttacks.DelayMeleeBy(sim, war.Slam.CurCast.CastTime)
 					return true
 				}
 				return false
@@ -390,7 +392,6 @@ func (war *DpsWarrior) makeCustomRotation() *common.CustomRotation {
 				}
 
 				if war.CurrentRage() >= war.Rotation.SlamRageThreshold && war.Slam.CanCast(sim, war.CurrentTarget) {
-					war.AutoAttacks.DelayMeleeBy(sim, war.Slam.CurCast.CastTime)
 					return true
 				}
 				return false
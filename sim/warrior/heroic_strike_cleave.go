@@ -148,5 +148,18 @@ func (warrior *Warrior) RegisterHSOrCleave(useCleave bool, rageThreshold float64
 		Duration: core.NeverExpires,
 	})
 
+	warrior.HSOrCleaveQueueSpell = warrior.RegisterSpell(core.SpellConfig{
+		ActionID: warrior.HeroicStrikeOrCleave.WithTag(1),
+		Flags:    core.SpellFlagNoMetrics | core.SpellFlagAPL,
+
+		ExtraCastCondition: func(sim *core.Simulation, target *core.Unit) bool {
+			return !warrior.HSOrCleaveQueueAura.IsActive() && warrior.CurrentRage() >= warrior.HeroicStrikeOrCleave.DefaultCast.Cost
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			warrior.QueueHSOrCleave(sim)
+		},
+	})
+
 	warrior.HSRageThreshold = core.MaxFloat(warrior.HeroicStrikeOrCleave.DefaultCast.Cost, rageThreshold)
 }
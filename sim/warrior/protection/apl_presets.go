@@ -0,0 +1,21 @@
+package protection
+
+import (
+	"github.com/wowsims/wotlk/sim/core"
+	"github.com/wowsims/wotlk/sim/core/proto"
+)
+
+// Shield Slam, Revenge and Devastate, with Concussion Blow and Shockwave as
+// fillers and Heroic Strike queued with spare rage.
+var ProtectionAPL = core.APLRotationFromTextString(`actions+=/autocast_cooldowns,all_types=true
+actions+=/cast_spell,id=47450@1,if=rage>=30
+actions+=/cast_spell,id=47488
+actions+=/cast_spell,id=57823
+actions+=/cast_spell,id=47498
+actions+=/cast_spell,id=12809
+actions+=/cast_spell,id=46968
+`)
+
+func registerAPLPresets() {
+	core.RegisterAPLPreset(proto.Spec_SpecProtectionWarrior, "Protection", ProtectionAPL)
+}
@@ -22,6 +22,7 @@ func RegisterProtectionWarrior() {
 			player.Spec = playerSpec
 		},
 	)
+	registerAPLPresets()
 }
 
 type ProtectionWarrior struct {
@@ -57,7 +58,7 @@ func NewProtectionWarrior(character core.Character, options *proto.Player) *Prot
 	}
 
 	war.EnableRageBar(rbo, func(sim *core.Simulation) {
-		if war.GCD.IsReady(sim) {
+		if war.GCD.IsReady(sim) && !war.IsUsingAPL() {
 			war.TryUseCooldowns(sim)
 			if war.GCD.IsReady(sim) {
 				war.doRotation(sim)
@@ -81,6 +81,25 @@ func BenchmarkSimulate(b *testing.B) {
 	core.RaidBenchmark(b, rsr)
 }
 
+func TestAPLPresets(t *testing.T) {
+	core.RunAPLPresetTests(t, []core.APLPresetTestCase{
+		{
+			Preset: "Protection",
+			Player: &proto.Player{
+				Race:            proto.Race_RaceOrc,
+				Class:           proto.Class_ClassWarrior,
+				Equipment:       P1Gear,
+				Consumes:        FullConsumes,
+				Spec:            PlayerOptionsBasic,
+				Glyphs:          DefaultGlyphs,
+				TalentsString:   DefaultTalents,
+				InFrontOfTarget: true,
+			},
+			IsTank: true,
+		},
+	})
+}
+
 var DefaultTalents = "2500030023-302-053351225000012521030113321"
 var DefaultGlyphs = &proto.Glyphs{
 	Major1: int32(proto.WarriorMajorGlyph_GlyphOfBlocking),
@@ -12,6 +12,9 @@ func (war *ProtectionWarrior) OnGCDReady(sim *core.Simulation) {
 }
 
 func (war *ProtectionWarrior) OnAutoAttack(sim *core.Simulation, spell *core.Spell) {
+	if war.IsUsingAPL() {
+		return
+	}
 	war.tryQueueHsCleave(sim)
 }
 
@@ -77,8 +77,7 @@ func (warrior *Warrior) applyCriticalBlock() {
 		if result.Outcome.Matches(core.OutcomeBlock) && !result.Outcome.Matches(core.OutcomeMiss) && !result.Outcome.Matches(core.OutcomeParry) && !result.Outcome.Matches(core.OutcomeDodge) {
 			procChance := 0.2 * float64(warrior.Talents.CriticalBlock)
 			if sim.RandomFloat("Critical Block Roll") <= procChance {
-				blockValue := warrior.BlockValue()
-				result.Damage = core.MaxFloat(0, result.Damage-blockValue)
+				result.ApplyCriticalBlock(warrior.BlockValue())
 				dummyCriticalBlockSpell.Cast(sim, spell.Unit)
 			}
 		}
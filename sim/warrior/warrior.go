@@ -85,6 +85,7 @@ type Warrior struct {
 
 	HeroicStrikeOrCleave     *core.Spell
 	HSOrCleaveQueueAura      *core.Aura
+	HSOrCleaveQueueSpell     *core.Spell
 	OverpowerAura            *core.Aura
 	HSRageThreshold          float64
 	RendRageThresholdBelow   float64
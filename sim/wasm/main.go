@@ -31,6 +31,7 @@ func main() {
 	js.Global().Set("statWeights", js.FuncOf(statWeights))
 	js.Global().Set("statWeightsAsync", js.FuncOf(statWeightsAsync))
 	js.Global().Set("bulkSimAsync", js.FuncOf(bulkSimAsync))
+	js.Global().Set("validateAPL", js.FuncOf(validateAPL))
 	js.Global().Call("wasmready")
 	<-c
 }
@@ -217,6 +218,51 @@ func bulkSimAsync(this js.Value, args []js.Value) interface{} {
 	return result
 }
 
+func validateAPL(this js.Value, args []js.Value) (response interface{}) {
+	defer func() {
+		if err := recover(); err != nil {
+			errStr := ""
+			switch errt := err.(type) {
+			case string:
+				errStr = errt
+			case error:
+				errStr = errt.Error()
+			}
+
+			errStr += "\nStack Trace:\n" + string(debug.Stack())
+			result := &proto.ValidateAPLResult{
+				ErrorResult: errStr,
+			}
+			outbytes, err := googleProto.Marshal(result)
+			if err != nil {
+				log.Printf("[ERROR] Failed to marshal error (%s) result: %s", errStr, err.Error())
+				return
+			}
+			outArray := js.Global().Get("Uint8Array").New(len(outbytes))
+			js.CopyBytesToJS(outArray, outbytes)
+			response = outArray
+		}
+	}()
+	vr := &proto.ValidateAPLRequest{}
+	if err := googleProto.Unmarshal(getArgsBinary(args[0]), vr); err != nil {
+		log.Printf("Failed to parse request: %s", err)
+		return nil
+	}
+	result := core.ValidateAPL(vr)
+
+	outbytes, err := googleProto.Marshal(result)
+	if err != nil {
+		log.Printf("[ERROR] Failed to marshal result: %s", err.Error())
+		return nil
+	}
+
+	outArray := js.Global().Get("Uint8Array").New(len(outbytes))
+	js.CopyBytesToJS(outArray, outbytes)
+
+	response = outArray
+	return response
+}
+
 // Assumes args[0] is a Uint8Array
 func getArgsBinary(value js.Value) []byte {
 	data := make([]byte, value.Get("length").Int())
@@ -100,6 +100,9 @@ var handlers = map[string]apiHandler{
 	"/computeStats": {msg: func() googleProto.Message { return &proto.ComputeStatsRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
 		return core.ComputeStats(msg.(*proto.ComputeStatsRequest))
 	}},
+	"/validateAPL": {msg: func() googleProto.Message { return &proto.ValidateAPLRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.ValidateAPL(msg.(*proto.ValidateAPLRequest))
+	}},
 }
 
 var asyncAPIHandlers = map[string]asyncAPIHandler{
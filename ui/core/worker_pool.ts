@@ -10,6 +10,7 @@ import { ComputeStatsRequest, ComputeStatsResult } from './proto/api.js';
 import { RaidSimRequest, RaidSimResult, ProgressMetrics } from './proto/api.js';
 import { StatWeightsRequest, StatWeightsResult } from './proto/api.js';
 import { BulkSimRequest, BulkSimResult } from './proto/api.js';
+import { ValidateAPLRequest, ValidateAPLResult } from './proto/api.js';
 
 import { wait } from './utils.js';
 
@@ -40,6 +41,11 @@ export class WorkerPool {
 		return ComputeStatsResult.fromBinary(result);
 	}
 
+	async validateAPL(request: ValidateAPLRequest): Promise<ValidateAPLResult> {
+		const result = await this.makeApiCall('validateAPL', ValidateAPLRequest.toBinary(request));
+		return ValidateAPLResult.fromBinary(result);
+	}
+
 	async statWeightsAsync(request: StatWeightsRequest, onProgress: Function): Promise<StatWeightsResult> {
 		console.log('Stat weights request: ' + StatWeightsRequest.toJsonString(request));
 		const worker = this.getLeastBusyWorker();
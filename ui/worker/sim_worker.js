@@ -49,6 +49,7 @@ addEventListener('message', async (e) => {
 			});
 		}],
 		['statWeights', statWeights],
+		['validateAPL', validateAPL],
 		['statWeightsAsync', (data) => {
 			return statWeightsAsync(data, (result) => {
 				postMessage({